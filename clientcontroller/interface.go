@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg"
 	"go.uber.org/zap"
@@ -46,6 +47,10 @@ type ClientController interface {
 	// QueryLatestFinalizedBlocks returns the latest finalized blocks
 	QueryLatestFinalizedBlocks(count uint64) ([]*types.BlockInfo, error)
 
+	// QueryVotesAtHeight returns the BTC public keys of the finality providers
+	// whose finality signatures at the given height are on the consumer chain
+	QueryVotesAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error)
+
 	// QueryBlock queries the block at the given height
 	QueryBlock(height uint64) (*types.BlockInfo, error)
 
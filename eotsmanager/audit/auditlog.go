@@ -0,0 +1,270 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// ResultOK is the result of an entry whose signature has been produced
+	ResultOK = "ok"
+
+	// maxEntrySize is the maximum size of an encoded entry in the log
+	maxEntrySize = 1 << 20
+)
+
+var (
+	// ErrBrokenChain is returned if an entry of the log has been modified,
+	// removed or inserted
+	ErrBrokenChain = errors.New("the audit log hash chain is broken")
+
+	// genesisHash is the previous hash of the first entry
+	genesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+)
+
+// Entry is an entry of the audit log, which records a signing request
+type Entry struct {
+	// Seq is the sequence number of the entry starting from 1
+	Seq uint64 `json:"seq"`
+	// Timestamp is the unix timestamp in seconds when the entry is appended
+	Timestamp int64 `json:"timestamp"`
+	// Caller identifies who requested the signature
+	Caller string `json:"caller"`
+	// Method is the signing method, e.g., SignEOTS
+	Method string `json:"method"`
+	// FpPk is the hex of the EOTS public key, or the key name
+	// if the signature is requested by the key name
+	FpPk string `json:"fp_pk"`
+	// ChainID is the hex of the chain ID, which is empty for Schnorr signatures
+	ChainID string `json:"chain_id,omitempty"`
+	// Height is the height of the EOTS signature, which is 0 for Schnorr signatures
+	Height uint64 `json:"height,omitempty"`
+	// MsgHash is the hex of the SHA-256 hash of the message requested to sign
+	MsgHash string `json:"msg_hash"`
+	// Result is ResultOK if the signature has been produced, or the error otherwise
+	Result string `json:"result"`
+	// PrevHash is the hash of the previous entry
+	PrevHash string `json:"prev_hash"`
+	// Hash is the SHA-256 hash of the entry with an empty Hash
+	Hash string `json:"hash"`
+}
+
+// NewEntry creates an entry of a signing request with the result of the given error
+func NewEntry(caller, method string, fpPk, chainID []byte, height uint64, msg []byte, err error) *Entry {
+	msgHash := sha256.Sum256(msg)
+	result := ResultOK
+	if err != nil {
+		result = err.Error()
+	}
+
+	return &Entry{
+		Caller:  caller,
+		Method:  method,
+		FpPk:    hex.EncodeToString(fpPk),
+		ChainID: hex.EncodeToString(chainID),
+		Height:  height,
+		MsgHash: hex.EncodeToString(msgHash[:]),
+		Result:  result,
+	}
+}
+
+func (e *Entry) computeHash() (string, error) {
+	entryCopy := *e
+	entryCopy.Hash = ""
+	entryBytes, err := json.Marshal(&entryCopy)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(entryBytes)
+
+	return hex.EncodeToString(h[:]), nil
+}
+
+// Log is an append-only log of the signing requests, in which each entry
+// commits to the previous one by its hash, so that any modification of the
+// past entries breaks the chain
+type Log struct {
+	mu       sync.Mutex
+	f        *os.File
+	lastSeq  uint64
+	lastHash string
+}
+
+// Open opens the audit log at the given path, or creates it if it does not exist
+// It fails if the existing log does not pass the verification, so that new entries
+// are not appended to a tampered chain
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	l := &Log{lastHash: genesisHash}
+	err := forEachEntry(path, func(e *Entry) error {
+		l.lastSeq = e.Seq
+		l.lastHash = e.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to verify the audit log %s: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l.f = f
+
+	return l, nil
+}
+
+// Append links the entry to the last one and writes it to the log
+// The entry is synced to the disk before returning
+func (l *Log) Append(e *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = l.lastSeq + 1
+	e.Timestamp = time.Now().Unix()
+	e.PrevHash = l.lastHash
+	hash, err := e.computeHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+
+	entryBytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.f.Write(append(entryBytes, '\n')); err != nil {
+		return fmt.Errorf("failed to write the audit log entry: %w", err)
+	}
+	if err := l.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync the audit log: %w", err)
+	}
+
+	l.lastSeq = e.Seq
+	l.lastHash = e.Hash
+
+	return nil
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f.Close()
+}
+
+// Verify checks the integrity of the hash chain of the audit log at the given path
+// and returns the number of entries
+func Verify(path string) (uint64, error) {
+	var n uint64
+	err := forEachEntry(path, func(e *Entry) error {
+		n++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Filter selects the entries of a key within a range of heights
+type Filter struct {
+	// FpPk is the hex of the EOTS public key, all the keys are selected if it is empty
+	FpPk string
+	// FromHeight and ToHeight are the inclusive range of heights,
+	// ToHeight is unbounded if it is 0
+	FromHeight uint64
+	ToHeight   uint64
+}
+
+func (f *Filter) match(e *Entry) bool {
+	if f.FpPk != "" && f.FpPk != e.FpPk {
+		return false
+	}
+	if e.Height < f.FromHeight {
+		return false
+	}
+	if f.ToHeight != 0 && e.Height > f.ToHeight {
+		return false
+	}
+
+	return true
+}
+
+// Query returns the entries of the audit log at the given path that match the filter
+// The integrity of the log is verified while reading it
+func Query(path string, filter *Filter) ([]*Entry, error) {
+	var entries []*Entry
+	err := forEachEntry(path, func(e *Entry) error {
+		if filter.match(e) {
+			entries = append(entries, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// forEachEntry reads the entries of the log in order and verifies the chain
+// before passing each of them to fn
+func forEachEntry(path string, fn func(e *Entry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 4096), maxEntrySize)
+
+	prevSeq := uint64(0)
+	prevHash := genesisHash
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("%w: invalid entry after seq %d: %v", ErrBrokenChain, prevSeq, err)
+		}
+		if e.Seq != prevSeq+1 {
+			return fmt.Errorf("%w: expected seq %d, got %d", ErrBrokenChain, prevSeq+1, e.Seq)
+		}
+		if e.PrevHash != prevHash {
+			return fmt.Errorf("%w: the previous hash of seq %d does not match", ErrBrokenChain, e.Seq)
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+		if e.Hash != hash {
+			return fmt.Errorf("%w: the hash of seq %d does not match its content", ErrBrokenChain, e.Seq)
+		}
+
+		if err := fn(&e); err != nil {
+			return err
+		}
+		prevSeq = e.Seq
+		prevHash = e.Hash
+	}
+
+	return scanner.Err()
+}
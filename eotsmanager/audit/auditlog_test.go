@@ -0,0 +1,95 @@
+package audit_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/audit"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzAuditLog tests appending, querying and verifying the audit log,
+// and that modifying any entry breaks the hash chain
+func FuzzAuditLog(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		path := filepath.Join(t.TempDir(), "data", "audit.log")
+		fpPks := [][]byte{testutil.GenRandomByteArray(r, 32), testutil.GenRandomByteArray(r, 32)}
+		chainID := testutil.GenRandomByteArray(r, 10)
+
+		numEntries := 2 + r.Intn(20)
+		appendEntries := func(l *audit.Log, startHeight int) {
+			for i := 0; i < numEntries; i++ {
+				var signErr error
+				if r.Intn(4) == 0 {
+					signErr = errors.New("failed to sign")
+				}
+				height := startHeight + i
+				entry := audit.NewEntry("127.0.0.1:1234", "SignEOTS", fpPks[height%2], chainID,
+					uint64(height), testutil.GenRandomByteArray(r, 32), signErr)
+				require.NoError(t, l.Append(entry))
+			}
+		}
+
+		l, err := audit.Open(path)
+		require.NoError(t, err)
+		appendEntries(l, 1)
+		require.NoError(t, l.Close())
+
+		// the chain continues after reopening the log
+		l, err = audit.Open(path)
+		require.NoError(t, err)
+		appendEntries(l, numEntries+1)
+		require.NoError(t, l.Close())
+
+		n, err := audit.Verify(path)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2*numEntries), n)
+
+		fromHeight := uint64(1 + r.Intn(2*numEntries))
+		toHeight := fromHeight + uint64(r.Intn(numEntries))
+		entries, err := audit.Query(path, &audit.Filter{
+			FpPk:       hex.EncodeToString(fpPks[0]),
+			FromHeight: fromHeight,
+			ToHeight:   toHeight,
+		})
+		require.NoError(t, err)
+		for _, e := range entries {
+			require.Equal(t, hex.EncodeToString(fpPks[0]), e.FpPk)
+			require.GreaterOrEqual(t, e.Height, fromHeight)
+			require.LessOrEqual(t, e.Height, toHeight)
+		}
+		// the entries of the first key are at the even heights
+		expectedNum := 0
+		for h := fromHeight; h <= toHeight && h <= uint64(2*numEntries); h++ {
+			if h%2 == 0 {
+				expectedNum++
+			}
+		}
+		require.Len(t, entries, expectedNum)
+
+		// modify the result of an entry
+		logBytes, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := bytes.Split(bytes.TrimSpace(logBytes), []byte("\n"))
+		i := r.Intn(len(lines))
+		tampered := bytes.Replace(lines[i], []byte(`"result":"`), []byte(`"result":"x`), 1)
+		require.NotEqual(t, lines[i], tampered)
+		lines[i] = tampered
+		require.NoError(t, os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0600))
+
+		_, err = audit.Verify(path)
+		require.ErrorIs(t, err, audit.ErrBrokenChain)
+		_, err = audit.Open(path)
+		require.ErrorIs(t, err, audit.ErrBrokenChain)
+	})
+}
@@ -0,0 +1,296 @@
+package eotsmanager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/backup"
+	"github.com/babylonchain/finality-provider/eotsmanager/store"
+)
+
+// the files of a backup archive
+const (
+	backupKeyringDir         = "keyring"
+	backupKeyNamesFile       = "db/key_names.json"
+	backupRandSchemesFile    = "db/rand_schemes.json"
+	backupSigningHistoryFile = "db/signing_history.json"
+
+	keyringDirPrefix = "keyring-"
+	keyringDirPerm   = 0700
+	keyringFilePerm  = 0600
+)
+
+// ErrOutdatedBackup is returned when restoring a backup archive that is older than the database
+var ErrOutdatedBackup = errors.New("the database is newer than the backup archive")
+
+type backupKeyName struct {
+	FpPkHex string `json:"fp_pk_hex"`
+	KeyName string `json:"key_name"`
+}
+
+type backupRandScheme struct {
+	FpPkHex    string `json:"fp_pk_hex"`
+	ChainID    string `json:"chain_id"`
+	RandScheme string `json:"rand_scheme"`
+}
+
+// Backup writes an archive of the keyring records, the key names, the randomness
+// derivation schemes and the signing history, encrypted with the passphrase, to the
+// given absolute path on the host of the EOTS manager
+// The database and the keyring files are read in a single transaction, so that the
+// archive is consistent while the EOTS manager keeps serving requests
+func (lm *LocalEOTSManager) Backup(filePath, passphrase string) error {
+	if !filepath.IsAbs(filePath) {
+		return fmt.Errorf("the path of the backup archive should be absolute: %s", filePath)
+	}
+
+	keyringDir, err := lm.keyringDir()
+	if err != nil {
+		return err
+	}
+
+	// the keyring files are read while the read transaction is held, so that they
+	// match the key names in the snapshot
+	var keyringFiles []*keyringFile
+	snapshot, err := lm.es.Snapshot(func() error {
+		keyringFiles, err = readKeyringFiles(keyringDir)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to take the snapshot of the database: %w", err)
+	}
+
+	archive := backup.NewArchive(lm.keyringBackend)
+	for _, f := range keyringFiles {
+		if err := archive.AddFile(path.Join(backupKeyringDir, f.name), f.content); err != nil {
+			return err
+		}
+	}
+
+	keyNames := make([]*backupKeyName, 0, len(snapshot.KeyNames))
+	for _, kn := range snapshot.KeyNames {
+		keyNames = append(keyNames, &backupKeyName{FpPkHex: hex.EncodeToString(kn.FpPk), KeyName: kn.KeyName})
+	}
+	randSchemes := make([]*backupRandScheme, 0, len(snapshot.RandSchemes))
+	for _, rs := range snapshot.RandSchemes {
+		randSchemes = append(randSchemes, &backupRandScheme{
+			FpPkHex:    hex.EncodeToString(rs.FpPk),
+			ChainID:    string(rs.ChainID),
+			RandScheme: rs.Scheme,
+		})
+	}
+	dbFiles := map[string]interface{}{
+		backupKeyNamesFile:       keyNames,
+		backupRandSchemesFile:    randSchemes,
+		backupSigningHistoryFile: NewSigningHistoryInterchange(snapshot.SigningHistories),
+	}
+	for name, v := range dbFiles {
+		content, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := archive.AddFile(name, content); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.WriteFile(filePath, passphrase); err != nil {
+		return fmt.Errorf("failed to write the backup archive: %w", err)
+	}
+
+	return nil
+}
+
+type keyringFile struct {
+	name    string
+	content []byte
+}
+
+// readKeyringFiles reads the regular files of the keyring directory in the order of their names
+func readKeyringFiles(keyringDir string) ([]*keyringFile, error) {
+	entries, err := os.ReadDir(keyringDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the keyring directory: %w", err)
+	}
+
+	files := make([]*keyringFile, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(keyringDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the keyring file %s: %w", entry.Name(), err)
+		}
+		files = append(files, &keyringFile{name: entry.Name(), content: content})
+	}
+
+	return files, nil
+}
+
+// Restore restores the keyring records and rebuilds the database from the backup archive
+// It fails with ErrOutdatedBackup if the database has signed above the archive or has keys
+// that are not in the archive, as restoring it would lose the newer data
+// NOTE: the EOTS manager should not be serving requests while restoring
+func (lm *LocalEOTSManager) Restore(filePath, passphrase string) error {
+	archive, err := backup.ReadFile(filePath, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if archive.Manifest.KeyringBackend != lm.keyringBackend {
+		return fmt.Errorf("the backup archive is of the %s keyring backend, while the %s backend is used",
+			archive.Manifest.KeyringBackend, lm.keyringBackend)
+	}
+
+	keyringDir, err := lm.keyringDir()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := snapshotFromArchive(archive)
+	if err != nil {
+		return fmt.Errorf("invalid backup archive: %w", err)
+	}
+
+	existing, err := lm.es.Snapshot(nil)
+	if err != nil {
+		return fmt.Errorf("failed to take the snapshot of the database: %w", err)
+	}
+	if err := checkNotNewer(existing, snapshot); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(keyringDir, keyringDirPerm); err != nil {
+		return err
+	}
+	for name, content := range archive.Files {
+		if path.Dir(name) != backupKeyringDir {
+			continue
+		}
+		keyringFile := filepath.Join(keyringDir, path.Base(name))
+		if err := os.WriteFile(keyringFile, content, keyringFilePerm); err != nil {
+			return fmt.Errorf("failed to restore the keyring file %s: %w", path.Base(name), err)
+		}
+	}
+
+	if err := lm.es.Restore(snapshot); err != nil {
+		return fmt.Errorf("failed to rebuild the database: %w", err)
+	}
+
+	// the cached keys could be different from the restored ones
+	if lm.keyCache != nil {
+		lm.keyCache.lock(nil)
+	}
+
+	return nil
+}
+
+// keyringDir returns the directory of the keyring files, which only
+// exists for the file-based keyring backends
+func (lm *LocalEOTSManager) keyringDir() (string, error) {
+	switch lm.keyringBackend {
+	case keyring.BackendTest, keyring.BackendFile:
+		return filepath.Join(lm.homeDir, keyringDirPrefix+lm.keyringBackend), nil
+	default:
+		return "", fmt.Errorf("the %s keyring backend is not supported by backups", lm.keyringBackend)
+	}
+}
+
+func snapshotFromArchive(archive *backup.Archive) (*store.Snapshot, error) {
+	snapshot := &store.Snapshot{}
+
+	var keyNames []*backupKeyName
+	if err := unmarshalArchiveFile(archive, backupKeyNamesFile, &keyNames); err != nil {
+		return nil, err
+	}
+	for _, kn := range keyNames {
+		fpPk, err := hex.DecodeString(kn.FpPkHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", kn.FpPkHex, err)
+		}
+		if kn.KeyName == "" {
+			return nil, fmt.Errorf("empty key name of the key %s", kn.FpPkHex)
+		}
+		snapshot.KeyNames = append(snapshot.KeyNames, &store.EOTSKeyName{FpPk: fpPk, KeyName: kn.KeyName})
+	}
+
+	var randSchemes []*backupRandScheme
+	if err := unmarshalArchiveFile(archive, backupRandSchemesFile, &randSchemes); err != nil {
+		return nil, err
+	}
+	for _, rs := range randSchemes {
+		fpPk, err := hex.DecodeString(rs.FpPkHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", rs.FpPkHex, err)
+		}
+		snapshot.RandSchemes = append(snapshot.RandSchemes, &store.RandScheme{
+			FpPk:    fpPk,
+			ChainID: []byte(rs.ChainID),
+			Scheme:  rs.RandScheme,
+		})
+	}
+
+	var interchange SigningHistoryInterchange
+	if err := unmarshalArchiveFile(archive, backupSigningHistoryFile, &interchange); err != nil {
+		return nil, err
+	}
+	histories, err := interchange.ToSigningHistories()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.SigningHistories = histories
+
+	return snapshot, nil
+}
+
+func unmarshalArchiveFile(archive *backup.Archive, name string, v interface{}) error {
+	content, ok := archive.Files[name]
+	if !ok {
+		return fmt.Errorf("missing file %s", name)
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("invalid file %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// checkNotNewer returns ErrOutdatedBackup if the existing database has signed
+// above the signed watermarks in the archive, or has keys that are not in the archive
+func checkNotNewer(existing, archived *store.Snapshot) error {
+	archivedKeys := make(map[string]struct{}, len(archived.KeyNames))
+	for _, kn := range archived.KeyNames {
+		archivedKeys[hex.EncodeToString(kn.FpPk)] = struct{}{}
+	}
+	for _, kn := range existing.KeyNames {
+		if _, ok := archivedKeys[hex.EncodeToString(kn.FpPk)]; !ok {
+			return fmt.Errorf("%w: the key %s is not in the archive", ErrOutdatedBackup, kn.KeyName)
+		}
+	}
+
+	archivedWatermarks := make(map[string]uint64, len(archived.SigningHistories))
+	for _, h := range archived.SigningHistories {
+		archivedWatermarks[signingHistoryKey(h.FpPk, h.ChainID)] = h.SignedWatermark
+	}
+	for _, h := range existing.SigningHistories {
+		if h.SignedWatermark > archivedWatermarks[signingHistoryKey(h.FpPk, h.ChainID)] {
+			return fmt.Errorf("%w: the key %s has signed at height %d of chain %s",
+				ErrOutdatedBackup, hex.EncodeToString(h.FpPk), h.SignedWatermark, string(h.ChainID))
+		}
+	}
+
+	return nil
+}
+
+func signingHistoryKey(fpPk, chainID []byte) string {
+	return strings.Join([]string{hex.EncodeToString(fpPk), string(chainID)}, "/")
+}
@@ -0,0 +1,363 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// FormatVersion is the version of the backup archive format
+// it should be bumped whenever the format changes in a non-backward compatible way
+const FormatVersion = 1
+
+const (
+	manifestFileName = "manifest.json"
+
+	// the encrypted archive starts with the magic, the salt of the key derivation
+	// and the nonce, which are authenticated together with the ciphertext
+	archiveMagic = "EOTSDBAK"
+	saltSize     = 16
+
+	// scrypt parameters of deriving the encryption key from the passphrase
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+
+	maxFileSize = 1 << 30
+)
+
+var (
+	// ErrDecryptionFailed is returned if the passphrase is wrong or the archive has been modified
+	ErrDecryptionFailed = errors.New("failed to decrypt the backup archive: wrong passphrase or corrupted archive")
+
+	// ErrInvalidManifest is returned if the files in the archive do not match the manifest
+	ErrInvalidManifest = errors.New("the backup archive does not match its manifest")
+)
+
+// Manifest describes the content of a backup archive
+type Manifest struct {
+	FormatVersion uint32 `json:"backup_format_version,string"`
+	CreatedAt     string `json:"created_at"`
+	// KeyringBackend is the backend of the keyring whose files are in the archive
+	KeyringBackend string       `json:"keyring_backend"`
+	Files          []*FileEntry `json:"files"`
+}
+
+// FileEntry is a file in the backup archive with its checksum
+type FileEntry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// SHA256Hex is the hex of the SHA-256 hash of the file content
+	SHA256Hex string `json:"sha256_hex"`
+}
+
+// Archive is the decrypted content of a backup archive
+type Archive struct {
+	Manifest *Manifest
+	// file name -> content
+	Files map[string][]byte
+}
+
+// NewArchive creates an empty archive of the keyring with the given backend
+func NewArchive(keyringBackend string) *Archive {
+	return &Archive{
+		Manifest: &Manifest{
+			FormatVersion:  FormatVersion,
+			CreatedAt:      time.Now().UTC().Format(time.RFC3339),
+			KeyringBackend: keyringBackend,
+		},
+		Files: make(map[string][]byte),
+	}
+}
+
+// AddFile adds a file to the archive and records its checksum in the manifest
+func (a *Archive) AddFile(name string, content []byte) error {
+	if err := validateFileName(name); err != nil {
+		return err
+	}
+	if _, ok := a.Files[name]; ok {
+		return fmt.Errorf("duplicate file %s in the backup archive", name)
+	}
+
+	h := sha256.Sum256(content)
+	a.Files[name] = content
+	a.Manifest.Files = append(a.Manifest.Files, &FileEntry{
+		Name:      name,
+		Size:      int64(len(content)),
+		SHA256Hex: hex.EncodeToString(h[:]),
+	})
+
+	return nil
+}
+
+// CreatedAt returns the time when the archive is created
+func (a *Archive) CreatedAt() (time.Time, error) {
+	return time.Parse(time.RFC3339, a.Manifest.CreatedAt)
+}
+
+// Validate checks that the archive contains exactly the files in the manifest
+// and that their checksums match
+func (a *Archive) Validate() error {
+	if a.Manifest == nil {
+		return fmt.Errorf("%w: missing manifest", ErrInvalidManifest)
+	}
+	if a.Manifest.FormatVersion != FormatVersion {
+		return fmt.Errorf("unsupported backup format version %d, expected %d",
+			a.Manifest.FormatVersion, FormatVersion)
+	}
+	if _, err := a.CreatedAt(); err != nil {
+		return fmt.Errorf("%w: invalid creation time: %v", ErrInvalidManifest, err)
+	}
+	if len(a.Manifest.Files) != len(a.Files) {
+		return fmt.Errorf("%w: expected %d files, got %d", ErrInvalidManifest, len(a.Manifest.Files), len(a.Files))
+	}
+
+	for _, entry := range a.Manifest.Files {
+		content, ok := a.Files[entry.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing file %s", ErrInvalidManifest, entry.Name)
+		}
+		h := sha256.Sum256(content)
+		if int64(len(content)) != entry.Size || hex.EncodeToString(h[:]) != entry.SHA256Hex {
+			return fmt.Errorf("%w: checksum mismatch of file %s", ErrInvalidManifest, entry.Name)
+		}
+	}
+
+	return nil
+}
+
+// WriteFile encrypts the archive with the passphrase and writes it to the given path
+// It fails if the file already exists, so that an existing backup is never overwritten
+func (a *Archive) WriteFile(filePath, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("the passphrase of the backup archive should not be empty")
+	}
+
+	plaintext, err := a.marshal()
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encrypt(plaintext, passphrase)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(encrypted); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the backup archive: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync the backup archive: %w", err)
+	}
+
+	return f.Close()
+}
+
+// ReadFile decrypts the archive at the given path and validates it against its manifest
+func ReadFile(filePath, passphrase string) (*Archive, error) {
+	encrypted, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := decrypt(encrypted, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := unmarshal(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// marshal encodes the archive as a tar with the manifest as the first file
+func (a *Archive) marshal() ([]byte, error) {
+	manifestBytes, err := json.MarshalIndent(a.Manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(a.Files))
+	for name := range a.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := writeTarFile(tw, manifestFileName, manifestBytes); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := writeTarFile(tw, name, a.Files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, content []byte) error {
+	hdr := &tar.Header{
+		Name: name,
+		Mode: 0600,
+		Size: int64(len(content)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+
+	return err
+}
+
+func unmarshal(plaintext []byte) (*Archive, error) {
+	a := &Archive{Files: make(map[string][]byte)}
+	tr := tar.NewReader(bytes.NewReader(plaintext))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > maxFileSize {
+			return nil, fmt.Errorf("invalid file %s in the backup archive", hdr.Name)
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+
+		if hdr.Name == manifestFileName {
+			if a.Manifest != nil {
+				return nil, fmt.Errorf("%w: duplicate manifest", ErrInvalidManifest)
+			}
+			a.Manifest = &Manifest{}
+			if err := json.Unmarshal(content, a.Manifest); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+			}
+			continue
+		}
+
+		if err := validateFileName(hdr.Name); err != nil {
+			return nil, err
+		}
+		if _, ok := a.Files[hdr.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate file %s", ErrInvalidManifest, hdr.Name)
+		}
+		a.Files[hdr.Name] = content
+	}
+
+	return a, nil
+}
+
+// validateFileName refuses the names that could escape the directory
+// the files are restored to
+func validateFileName(name string) error {
+	if name == "" || name == manifestFileName || path.IsAbs(name) || path.Clean(name) != name ||
+		name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid file name %q in the backup archive", name)
+	}
+
+	return nil
+}
+
+func encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(archiveMagic)+saltSize+len(nonce))
+	header = append(header, archiveMagic...)
+	header = append(header, salt...)
+	header = append(header, nonce...)
+
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+func decrypt(encrypted []byte, passphrase string) ([]byte, error) {
+	if len(encrypted) < len(archiveMagic)+saltSize ||
+		string(encrypted[:len(archiveMagic)]) != archiveMagic {
+		return nil, fmt.Errorf("not an eotsd backup archive")
+	}
+	salt := encrypted[len(archiveMagic) : len(archiveMagic)+saltSize]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := len(archiveMagic) + saltSize + aead.NonceSize()
+	if len(encrypted) < headerSize {
+		return nil, ErrDecryptionFailed
+	}
+	header := encrypted[:headerSize]
+	nonce := encrypted[len(archiveMagic)+saltSize : headerSize]
+
+	plaintext, err := aead.Open(nil, nonce, encrypted[headerSize:], header)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
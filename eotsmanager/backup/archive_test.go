@@ -0,0 +1,66 @@
+package backup_test
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/backup"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzArchive tests that the encrypted archive is read back with its files
+// only with the right passphrase and if it has not been modified
+func FuzzArchive(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		archive := backup.NewArchive("test")
+		numFiles := 1 + r.Intn(5)
+		for i := 0; i < numFiles; i++ {
+			name := filepath.Join("dir", testutil.GenRandomHexStr(r, 8))
+			err := archive.AddFile(name, testutil.GenRandomByteArray(r, uint64(r.Intn(1024))))
+			require.NoError(t, err)
+		}
+		require.Error(t, archive.AddFile("../escape", []byte("content")))
+		require.NoError(t, archive.Validate())
+
+		filePath := filepath.Join(t.TempDir(), "eotsd.backup")
+		passphrase := testutil.GenRandomHexStr(r, 8)
+		err := archive.WriteFile(filePath, passphrase)
+		require.NoError(t, err)
+
+		// an existing archive is not overwritten
+		err = archive.WriteFile(filePath, passphrase)
+		require.ErrorIs(t, err, os.ErrExist)
+
+		restored, err := backup.ReadFile(filePath, passphrase)
+		require.NoError(t, err)
+		require.Equal(t, archive.Files, restored.Files)
+		require.Equal(t, archive.Manifest.KeyringBackend, restored.Manifest.KeyringBackend)
+		require.ElementsMatch(t, archive.Manifest.Files, restored.Manifest.Files)
+
+		_, err = backup.ReadFile(filePath, passphrase+"x")
+		require.ErrorIs(t, err, backup.ErrDecryptionFailed)
+
+		// modifying any byte of the archive fails the decryption
+		encrypted, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		encrypted[8+r.Intn(len(encrypted)-8)] ^= 0x01
+		tamperedPath := filepath.Join(t.TempDir(), "tampered.backup")
+		require.NoError(t, os.WriteFile(tamperedPath, encrypted, 0600))
+		_, err = backup.ReadFile(tamperedPath, passphrase)
+		require.ErrorIs(t, err, backup.ErrDecryptionFailed)
+
+		// the files are checked against the manifest
+		for name := range restored.Files {
+			restored.Files[name] = append(restored.Files[name], 0)
+			break
+		}
+		require.ErrorIs(t, restored.Validate(), backup.ErrInvalidManifest)
+	})
+}
@@ -0,0 +1,288 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+const (
+	defaultRequestTimeout      = 10 * time.Second
+	defaultMaxRetries          = 3
+	defaultRetryBackoff        = 200 * time.Millisecond
+	defaultHealthCheckInterval = 10 * time.Second
+
+	maxRetryBackoff = 5 * time.Second
+)
+
+// Endpoint is an eotsd the client sends requests to
+type Endpoint struct {
+	Address string
+	// SharedState marks that the eotsd shares the keys and the slashing protection
+	// database with the primary endpoint, so that the signing requests can fail over
+	// to it without the risk of signing conflicting messages
+	SharedState bool
+}
+
+// Options are the options of the retries and the health checks of the client
+type Options struct {
+	// RequestTimeout bounds each attempt of a request
+	RequestTimeout time.Duration
+	// MaxRetries is the number of rounds of retrying a request over the endpoints
+	// after the first one fails with a transient error
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, which is doubled for each of the next ones
+	RetryBackoff time.Duration
+	// HealthCheckInterval is the interval of pinging the endpoints, 0 disables the health checks
+	HealthCheckInterval time.Duration
+	// CheckVersion refuses the endpoints that the caller cannot work with, which is called
+	// once an endpoint is reachable, and no endpoint is refused if it is nil
+	CheckVersion func(info *types.VersionInfo) error
+}
+
+func DefaultOptions() *Options {
+	return &Options{
+		RequestTimeout:      defaultRequestTimeout,
+		MaxRetries:          defaultMaxRetries,
+		RetryBackoff:        defaultRetryBackoff,
+		HealthCheckInterval: defaultHealthCheckInterval,
+	}
+}
+
+// callKind decides which endpoints a request can be sent to
+type callKind int
+
+const (
+	// readCall does not change the state of eotsd, so it can fail over to any endpoint
+	readCall callKind = iota
+	// signCall can only fail over to the endpoints sharing the state with the primary one
+	signCall
+	// writeCall changes the state of the primary endpoint, so it is never failed over,
+	// and it is only retried if it has not reached the server
+	writeCall
+)
+
+type endpoint struct {
+	*Endpoint
+
+	conn    *grpc.ClientConn
+	client  proto.EOTSManagerClient
+	healthy atomic.Bool
+	// versionChecked is set once the version of the endpoint has been checked
+	versionChecked atomic.Bool
+	// incompatible is the error of the version check if the endpoint is refused,
+	// no request is sent to a refused endpoint
+	incompatible atomic.Pointer[error]
+}
+
+func dialEndpoint(e *Endpoint, tlsCfg *tls.Config) (*endpoint, error) {
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+
+	conn, err := grpc.Dial(e.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build gRPC connection to %s: %w", e.Address, err)
+	}
+
+	return &endpoint{
+		Endpoint: e,
+		conn:     conn,
+		client:   proto.NewEOTSManagerClient(conn),
+	}, nil
+}
+
+// isTransient returns whether the request could succeed if retried
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// candidates returns the endpoints the request of the given kind can be sent to,
+// the healthy ones first, each group in the configured order
+func (c *EOTSManagerGRpcClient) candidates(kind callKind) []*endpoint {
+	var healthy, unhealthy []*endpoint
+	for i, ep := range c.endpoints {
+		if i != 0 && !canFailOver(kind, ep) {
+			continue
+		}
+		if ep.incompatible.Load() != nil {
+			continue
+		}
+
+		if ep.healthy.Load() {
+			healthy = append(healthy, ep)
+		} else {
+			unhealthy = append(unhealthy, ep)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// canFailOver returns whether the request of the given kind can be sent
+// to the endpoint instead of the primary one
+func canFailOver(kind callKind, ep *endpoint) bool {
+	switch kind {
+	case readCall:
+		return true
+	case signCall:
+		return ep.SharedState
+	default:
+		return false
+	}
+}
+
+// invoke sends the request to the first candidate endpoint that serves it, and retries
+// the round of the candidates with backoff as long as they fail with transient errors
+func (c *EOTSManagerGRpcClient) invoke(kind callKind, call func(ctx context.Context, client proto.EOTSManagerClient) error) error {
+	var err error
+	backoff := c.opts.RetryBackoff
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-c.quit:
+				return err
+			}
+			backoff = min(2*backoff, maxRetryBackoff)
+		}
+
+		candidates := c.candidates(kind)
+		if len(candidates) == 0 {
+			// only the refused endpoints could serve the request
+			return fmt.Errorf("%w: no other endpoint can serve the request", types.ErrIncompatibleEOTSManager)
+		}
+		for _, ep := range candidates {
+			ctx, cancel := context.WithTimeout(context.Background(), c.opts.RequestTimeout)
+			err = call(ctx, ep.client)
+			cancel()
+			if err == nil {
+				c.setHealthy(ep, true, nil)
+				return nil
+			}
+			if !isTransient(err) {
+				return err
+			}
+
+			c.setHealthy(ep, false, err)
+			// the request could have been applied if it timed out
+			if kind == writeCall && status.Code(err) != codes.Unavailable {
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+func (c *EOTSManagerGRpcClient) setHealthy(ep *endpoint, healthy bool, err error) {
+	if ep.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		c.logger.Info("the EOTS manager endpoint is healthy", zap.String("address", ep.Address))
+	} else {
+		c.logger.Warn("the EOTS manager endpoint is unhealthy", zap.String("address", ep.Address), zap.Error(err))
+	}
+}
+
+// pingEndpoint pings the endpoint, checks its version once it is reachable,
+// and updates its health
+func (c *EOTSManagerGRpcClient) pingEndpoint(ep *endpoint) error {
+	if incompatibleErr := ep.incompatible.Load(); incompatibleErr != nil {
+		return *incompatibleErr
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.RequestTimeout)
+	defer cancel()
+
+	_, err := ep.client.Ping(ctx, &proto.PingRequest{})
+	if err == nil {
+		err = c.checkVersion(ctx, ep)
+	}
+	c.setHealthy(ep, err == nil, err)
+
+	return err
+}
+
+// checkVersion refuses the endpoint if it is not compatible, which is only checked once
+func (c *EOTSManagerGRpcClient) checkVersion(ctx context.Context, ep *endpoint) error {
+	if c.opts.CheckVersion == nil || ep.versionChecked.Load() {
+		return nil
+	}
+
+	info, err := getVersion(ctx, ep.client)
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		err = fmt.Errorf("%w: it does not report its version, which means it is older than the client",
+			types.ErrIncompatibleEOTSManager)
+	case err != nil:
+		// check again at the next ping
+		return err
+	default:
+		err = c.opts.CheckVersion(info)
+	}
+
+	ep.versionChecked.Store(true)
+	if err != nil {
+		err = fmt.Errorf("refused the EOTS manager at %s: %w", ep.Address, err)
+		ep.incompatible.Store(&err)
+		c.logger.Error("the EOTS manager endpoint is incompatible", zap.String("address", ep.Address), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func getVersion(ctx context.Context, client proto.EOTSManagerClient) (*types.VersionInfo, error) {
+	res, err := client.GetVersion(ctx, &proto.GetVersionRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.VersionInfo{
+		Version:         res.Version,
+		CommitHash:      res.CommitHash,
+		GoVersion:       res.GoVersion,
+		ProtocolVersion: res.ProtocolVersion,
+		RPCMethods:      res.RpcMethods,
+		RandSchemes:     res.RandSchemes,
+	}, nil
+}
+
+func (c *EOTSManagerGRpcClient) healthCheckLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, ep := range c.endpoints {
+				_ = c.pingEndpoint(ep)
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/client"
+	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+// fakeEOTSManager counts the requests it serves, and fails all of them
+// including pings with the configured error
+type fakeEOTSManager struct {
+	proto.UnimplementedEOTSManagerServer
+
+	err        atomic.Pointer[error]
+	version    *proto.GetVersionResponse
+	readCalls  atomic.Int32
+	signCalls  atomic.Int32
+	writeCalls atomic.Int32
+}
+
+func (s *fakeEOTSManager) setErr(err error) {
+	s.err.Store(&err)
+}
+
+func (s *fakeEOTSManager) loadErr() error {
+	if err := s.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (s *fakeEOTSManager) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
+	if err := s.loadErr(); err != nil {
+		return nil, err
+	}
+	return &proto.PingResponse{}, nil
+}
+
+func (s *fakeEOTSManager) GetVersion(ctx context.Context, req *proto.GetVersionRequest) (*proto.GetVersionResponse, error) {
+	if s.version == nil {
+		return s.UnimplementedEOTSManagerServer.GetVersion(ctx, req)
+	}
+	return s.version, nil
+}
+
+func (s *fakeEOTSManager) ListKeys(context.Context, *proto.ListKeysRequest) (*proto.ListKeysResponse, error) {
+	s.readCalls.Add(1)
+	if err := s.loadErr(); err != nil {
+		return nil, err
+	}
+	return &proto.ListKeysResponse{}, nil
+}
+
+func (s *fakeEOTSManager) SignEOTS(context.Context, *proto.SignEOTSRequest) (*proto.SignEOTSResponse, error) {
+	s.signCalls.Add(1)
+	if err := s.loadErr(); err != nil {
+		return nil, err
+	}
+	return &proto.SignEOTSResponse{Sig: make([]byte, 32)}, nil
+}
+
+func (s *fakeEOTSManager) DeleteKey(context.Context, *proto.DeleteKeyRequest) (*proto.DeleteKeyResponse, error) {
+	s.writeCalls.Add(1)
+	if err := s.loadErr(); err != nil {
+		return nil, err
+	}
+	return &proto.DeleteKeyResponse{}, nil
+}
+
+func startFakeEOTSManager(t *testing.T) (*fakeEOTSManager, string) {
+	return startFakeEOTSManagerWithVersion(t, nil)
+}
+
+func startFakeEOTSManagerWithVersion(t *testing.T, version *proto.GetVersionResponse) (*fakeEOTSManager, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeEOTSManager{version: version}
+	grpcServer := grpc.NewServer()
+	proto.RegisterEOTSManagerServer(grpcServer, s)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	return s, lis.Addr().String()
+}
+
+// TestFailover tests that the requests fail over to the healthy endpoints,
+// and that the signing requests only fail over to the endpoints sharing the state
+func TestFailover(t *testing.T) {
+	primary, primaryAddr := startFakeEOTSManager(t)
+	shared, sharedAddr := startFakeEOTSManager(t)
+	readOnly, readOnlyAddr := startFakeEOTSManager(t)
+
+	opts := &client.Options{
+		RequestTimeout: time.Second,
+		MaxRetries:     1,
+		RetryBackoff:   time.Millisecond,
+	}
+	c, err := client.NewEOTSManagerGRpcClientWithEndpoints([]*client.Endpoint{
+		{Address: primaryAddr},
+		{Address: sharedAddr, SharedState: true},
+		{Address: readOnlyAddr},
+	}, nil, opts, zap.NewNop())
+	require.NoError(t, err)
+	defer c.Close()
+
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	// all the requests are served by the primary endpoint if it is healthy
+	_, err = c.SignEOTS(nil, nil, nil, 1, "")
+	require.NoError(t, err)
+	_, err = c.ListKeys()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), primary.signCalls.Load())
+	require.Equal(t, int32(1), primary.readCalls.Load())
+
+	// the signing requests fail over to the endpoint sharing the state,
+	// while the other writes are not failed over
+	primary.setErr(unavailable)
+	_, err = c.SignEOTS(nil, nil, nil, 2, "")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), shared.signCalls.Load())
+	err = c.DeleteKey(nil, "")
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, int32(2), primary.writeCalls.Load())
+	require.Zero(t, shared.writeCalls.Load())
+	require.Zero(t, readOnly.writeCalls.Load())
+
+	// the signing requests never fail over to the endpoint not sharing the state,
+	// while the read-only requests do
+	shared.setErr(unavailable)
+	_, err = c.SignEOTS(nil, nil, nil, 3, "")
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Zero(t, readOnly.signCalls.Load())
+	_, err = c.ListKeys()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), readOnly.readCalls.Load())
+
+	// the requests go back to the primary endpoint once it recovers
+	primary.setErr(nil)
+	signCalls := primary.signCalls.Load()
+	_, err = c.SignEOTS(nil, nil, nil, 4, "")
+	require.NoError(t, err)
+	require.Equal(t, signCalls+1, primary.signCalls.Load())
+
+	// non-transient errors are neither retried nor failed over
+	shared.setErr(nil)
+	sharedSignCalls := shared.signCalls.Load()
+	primary.setErr(status.Error(codes.FailedPrecondition, types.ErrDoubleSign.Error()))
+	_, err = c.SignEOTS(nil, nil, nil, 5, "")
+	require.ErrorIs(t, err, types.ErrDoubleSign)
+	require.Equal(t, signCalls+2, primary.signCalls.Load())
+	require.Equal(t, sharedSignCalls, shared.signCalls.Load())
+}
+
+// TestVersionCheck tests that the client refuses the endpoints failing the version check
+func TestVersionCheck(t *testing.T) {
+	opts := client.DefaultOptions()
+	opts.HealthCheckInterval = 0
+	opts.CheckVersion = func(info *types.VersionInfo) error {
+		if info.ProtocolVersion != types.ProtocolVersion {
+			return fmt.Errorf("%w: protocol version %d", types.ErrIncompatibleEOTSManager, info.ProtocolVersion)
+		}
+		return nil
+	}
+	newClient := func(addrs ...string) (*client.EOTSManagerGRpcClient, error) {
+		endpoints := make([]*client.Endpoint, len(addrs))
+		for i, addr := range addrs {
+			endpoints[i] = &client.Endpoint{Address: addr}
+		}
+		return client.NewEOTSManagerGRpcClientWithEndpoints(endpoints, nil, opts, zap.NewNop())
+	}
+
+	_, compatibleAddr := startFakeEOTSManagerWithVersion(t, &proto.GetVersionResponse{ProtocolVersion: types.ProtocolVersion})
+	_, incompatibleAddr := startFakeEOTSManagerWithVersion(t, &proto.GetVersionResponse{ProtocolVersion: types.ProtocolVersion + 1})
+	_, oldAddr := startFakeEOTSManager(t)
+
+	c, err := newClient(compatibleAddr)
+	require.NoError(t, err)
+	info, err := c.GetVersion()
+	require.NoError(t, err)
+	require.Equal(t, types.ProtocolVersion, info.ProtocolVersion)
+	require.NoError(t, c.Close())
+
+	// an endpoint not reporting its version is refused
+	_, err = newClient(oldAddr)
+	require.ErrorIs(t, err, types.ErrIncompatibleEOTSManager)
+
+	// any incompatible endpoint is refused, even if it is not the primary one
+	_, err = newClient(compatibleAddr, incompatibleAddr)
+	require.ErrorIs(t, err, types.ErrIncompatibleEOTSManager)
+}
@@ -2,12 +2,17 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	"github.com/babylonchain/finality-provider/eotsmanager/proto"
@@ -16,33 +21,86 @@ import (
 
 var _ eotsmanager.EOTSManager = &EOTSManagerGRpcClient{}
 
+// EOTSManagerGRpcClient sends the requests to a list of eotsd endpoints, the first of
+// which is the primary one. The requests are retried with backoff on transient errors
+// and fail over to the healthy endpoints as long as it is safe for the request:
+// signing requests only to the endpoints sharing the state with the primary one,
+// other requests changing the state never, and read-only requests to any endpoint
 type EOTSManagerGRpcClient struct {
-	client proto.EOTSManagerClient
-	conn   *grpc.ClientConn
+	endpoints []*endpoint
+	opts      *Options
+	logger    *zap.Logger
+
+	wg   sync.WaitGroup
+	quit chan struct{}
 }
 
-func NewEOTSManagerGRpcClient(remoteAddr string) (*EOTSManagerGRpcClient, error) {
-	conn, err := grpc.Dial(remoteAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to build gRPC connection to %s: %w", remoteAddr, err)
+// NewEOTSManagerGRpcClient connects to the EOTS manager at the given address,
+// which is either host:port or unix:///path/to/socket
+// The connection is not encrypted if tlsCfg is nil
+func NewEOTSManagerGRpcClient(remoteAddr string, tlsCfg *tls.Config) (*EOTSManagerGRpcClient, error) {
+	return NewEOTSManagerGRpcClientWithEndpoints([]*Endpoint{{Address: remoteAddr}}, tlsCfg, DefaultOptions(), zap.NewNop())
+}
+
+// NewEOTSManagerGRpcClientWithEndpoints connects to the given endpoints, the first of which
+// is the primary one, with the same TLS config
+// It fails if none of the endpoints is responding or any responding one is refused by the
+// version check of the options
+func NewEOTSManagerGRpcClientWithEndpoints(
+	endpoints []*Endpoint,
+	tlsCfg *tls.Config,
+	opts *Options,
+	logger *zap.Logger,
+) (*EOTSManagerGRpcClient, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no EOTS manager endpoints")
 	}
 
-	gClient := &EOTSManagerGRpcClient{
-		client: proto.NewEOTSManagerClient(conn),
-		conn:   conn,
+	c := &EOTSManagerGRpcClient{
+		opts:   opts,
+		logger: logger,
+		quit:   make(chan struct{}),
+	}
+	for _, e := range endpoints {
+		ep, err := dialEndpoint(e, tlsCfg)
+		if err != nil {
+			c.closeConns()
+			return nil, err
+		}
+		c.endpoints = append(c.endpoints, ep)
+	}
+
+	var pingErrs []error
+	for _, ep := range c.endpoints {
+		err := c.pingEndpoint(ep)
+		if errors.Is(err, types.ErrIncompatibleEOTSManager) {
+			c.closeConns()
+			return nil, err
+		}
+		if err != nil {
+			pingErrs = append(pingErrs, fmt.Errorf("%s: %w", ep.Address, err))
+		}
+	}
+	if len(pingErrs) == len(c.endpoints) {
+		c.closeConns()
+		return nil, fmt.Errorf("the EOTS manager server is not responding: %w", errors.Join(pingErrs...))
 	}
 
-	if err := gClient.Ping(); err != nil {
-		return nil, fmt.Errorf("the EOTS manager server is not responding: %w", err)
+	if opts.HealthCheckInterval > 0 {
+		c.wg.Add(1)
+		go c.healthCheckLoop()
 	}
 
-	return gClient, nil
+	return c, nil
 }
 
 func (c *EOTSManagerGRpcClient) Ping() error {
 	req := &proto.PingRequest{}
 
-	_, err := c.client.Ping(context.Background(), req)
+	err := c.invoke(readCall, func(ctx context.Context, client proto.EOTSManagerClient) error {
+		_, err := client.Ping(ctx, req)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -50,9 +108,27 @@ func (c *EOTSManagerGRpcClient) Ping() error {
 	return nil
 }
 
+// GetVersion returns the build info and the capabilities of the EOTS manager
+func (c *EOTSManagerGRpcClient) GetVersion() (*types.VersionInfo, error) {
+	var info *types.VersionInfo
+	err := c.invoke(readCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		info, err = getVersion(ctx, client)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
 func (c *EOTSManagerGRpcClient) CreateKey(name, passphrase, hdPath string) ([]byte, error) {
 	req := &proto.CreateKeyRequest{Name: name, Passphrase: passphrase, HdPath: hdPath}
-	res, err := c.client.CreateKey(context.Background(), req)
+	var res *proto.CreateKeyResponse
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.CreateKey(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -60,13 +136,103 @@ func (c *EOTSManagerGRpcClient) CreateKey(name, passphrase, hdPath string) ([]by
 	return res.Pk, nil
 }
 
-func (c *EOTSManagerGRpcClient) CreateMasterRandPair(uid, chainID []byte, passphrase string) (string, error) {
+func (c *EOTSManagerGRpcClient) ImportMnemonic(name, passphrase, hdPath, mnemonic string, isKeyMnemonic bool) ([]byte, error) {
+	req := &proto.ImportMnemonicRequest{
+		Name:        name,
+		Passphrase:  passphrase,
+		HdPath:      hdPath,
+		Mnemonic:    mnemonic,
+		KeyMnemonic: isKeyMnemonic,
+	}
+	var res *proto.ImportMnemonicResponse
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.ImportMnemonic(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Pk, nil
+}
+
+func (c *EOTSManagerGRpcClient) ExportMnemonic(uid []byte, passphrase string) (string, error) {
+	req := &proto.ExportMnemonicRequest{Uid: uid, Passphrase: passphrase}
+	var res *proto.ExportMnemonicResponse
+	err := c.invoke(readCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.ExportMnemonic(ctx, req)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res.KeyMnemonic, nil
+}
+
+func (c *EOTSManagerGRpcClient) ListKeys() ([]*types.KeyInfo, error) {
+	var res *proto.ListKeysResponse
+	err := c.invoke(readCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.ListKeys(ctx, &proto.ListKeysRequest{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*types.KeyInfo, len(res.Keys))
+	for i, k := range res.Keys {
+		keys[i] = toKeyInfo(k)
+	}
+
+	return keys, nil
+}
+
+func (c *EOTSManagerGRpcClient) ShowKey(uid, chainID []byte, passphrase string) (*types.KeyInfo, error) {
+	req := &proto.ShowKeyRequest{Uid: uid, ChainId: chainID, Passphrase: passphrase}
+	var res *proto.ShowKeyResponse
+	err := c.invoke(readCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.ShowKey(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toKeyInfo(res.Key), nil
+}
+
+func (c *EOTSManagerGRpcClient) DeleteKey(uid []byte, passphrase string) error {
+	req := &proto.DeleteKeyRequest{Uid: uid, Passphrase: passphrase}
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) error {
+		_, err := client.DeleteKey(ctx, req)
+		return err
+	})
+
+	return err
+}
+
+func toKeyInfo(k *proto.KeyInfo) *types.KeyInfo {
+	return &types.KeyInfo{
+		Name:          k.Name,
+		FpPk:          k.Pk,
+		MasterPubRand: k.MasterPubRand,
+		RandScheme:    k.RandScheme,
+	}
+}
+
+func (c *EOTSManagerGRpcClient) CreateMasterRandPair(uid, chainID []byte, randScheme string, passphrase string) (string, error) {
 	req := &proto.CreateMasterRandPairRequest{
 		Uid:        uid,
 		ChainId:    chainID,
 		Passphrase: passphrase,
+		RandScheme: randScheme,
 	}
-	res, err := c.client.CreateMasterRandPair(context.Background(), req)
+	var res *proto.CreateMasterRandPairResponse
+	err := c.invoke(signCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.CreateMasterRandPair(ctx, req)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
@@ -77,7 +243,11 @@ func (c *EOTSManagerGRpcClient) CreateMasterRandPair(uid, chainID []byte, passph
 func (c *EOTSManagerGRpcClient) KeyRecord(uid []byte, passphrase string) (*types.KeyRecord, error) {
 	req := &proto.KeyRecordRequest{Uid: uid, Passphrase: passphrase}
 
-	res, err := c.client.KeyRecord(context.Background(), req)
+	var res *proto.KeyRecordResponse
+	err := c.invoke(readCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.KeyRecord(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -98,9 +268,13 @@ func (c *EOTSManagerGRpcClient) SignEOTS(uid, chaiID, msg []byte, height uint64,
 		Height:     height,
 		Passphrase: passphrase,
 	}
-	res, err := c.client.SignEOTS(context.Background(), req)
+	var res *proto.SignEOTSResponse
+	err := c.invoke(signCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.SignEOTS(ctx, req)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, toSigningErr(err)
 	}
 
 	var s btcec.ModNScalar
@@ -109,11 +283,71 @@ func (c *EOTSManagerGRpcClient) SignEOTS(uid, chaiID, msg []byte, height uint64,
 	return &s, nil
 }
 
+func (c *EOTSManagerGRpcClient) SignEOTSBatch(uid, chainID []byte, msgs []*types.HeightMsg, passphrase string) ([]*btcec.ModNScalar, error) {
+	heightMsgs := make([]*proto.HeightMsg, len(msgs))
+	for i, m := range msgs {
+		heightMsgs[i] = &proto.HeightMsg{Height: m.Height, Msg: m.Msg}
+	}
+	req := &proto.SignEOTSBatchRequest{
+		Uid:        uid,
+		ChainId:    chainID,
+		Msgs:       heightMsgs,
+		Passphrase: passphrase,
+	}
+	var res *proto.SignEOTSBatchResponse
+	err := c.invoke(signCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.SignEOTSBatch(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, toSigningErr(err)
+	}
+	if len(res.Sigs) != len(msgs) {
+		return nil, fmt.Errorf("expected %d signatures, got %d", len(msgs), len(res.Sigs))
+	}
+
+	sigs := make([]*btcec.ModNScalar, len(res.Sigs))
+	for i, sigBytes := range res.Sigs {
+		var s btcec.ModNScalar
+		s.SetByteSlice(sigBytes)
+		sigs[i] = &s
+	}
+
+	return sigs, nil
+}
+
+// toSigningErr recovers the typed error so that the caller can tell
+// a refused double sign, a signing policy violation or the halted signing from other failures
+func toSigningErr(err error) error {
+	errMsg := status.Convert(err).Message()
+	switch status.Code(err) {
+	case codes.FailedPrecondition:
+		errMsg = strings.TrimPrefix(errMsg, types.ErrDoubleSign.Error()+": ")
+		return fmt.Errorf("%w: %s", types.ErrDoubleSign, errMsg)
+	case codes.Aborted:
+		errMsg = strings.TrimPrefix(errMsg, types.ErrSigningHalted.Error())
+		return fmt.Errorf("%w%s", types.ErrSigningHalted, errMsg)
+	case codes.PermissionDenied, codes.ResourceExhausted:
+		for _, policyErr := range types.PolicyViolationErrs {
+			if strings.HasPrefix(errMsg, policyErr.Error()) {
+				return fmt.Errorf("%w%s", policyErr, strings.TrimPrefix(errMsg, policyErr.Error()))
+			}
+		}
+		return err
+	default:
+		return err
+	}
+}
+
 func (c *EOTSManagerGRpcClient) SignSchnorrSig(uid, msg []byte, passphrase string) (*schnorr.Signature, error) {
 	req := &proto.SignSchnorrSigRequest{Uid: uid, Msg: msg, Passphrase: passphrase}
-	res, err := c.client.SignSchnorrSig(context.Background(), req)
+	var res *proto.SignSchnorrSigResponse
+	err := c.invoke(signCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.SignSchnorrSig(ctx, req)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, toSigningErr(err)
 	}
 
 	sig, err := schnorr.ParseSignature(res.Sig)
@@ -124,6 +358,105 @@ func (c *EOTSManagerGRpcClient) SignSchnorrSig(uid, msg []byte, passphrase strin
 	return sig, nil
 }
 
+func (c *EOTSManagerGRpcClient) SignPoP(uid, chainPk, chainSig []byte, passphrase string) (*schnorr.Signature, error) {
+	req := &proto.SignPoPRequest{Uid: uid, ChainPk: chainPk, ChainSig: chainSig, Passphrase: passphrase}
+	var res *proto.SignPoPResponse
+	err := c.invoke(signCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.SignPoP(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, toSigningErr(err)
+	}
+
+	return schnorr.ParseSignature(res.BtcSig)
+}
+
+func (c *EOTSManagerGRpcClient) SigningRecord(uid, chainID []byte, height uint64) (*types.SigningRecord, error) {
+	req := &proto.SigningRecordRequest{Uid: uid, ChainId: chainID, Height: height}
+	var res *proto.SigningRecordResponse
+	err := c.invoke(readCall, func(ctx context.Context, client proto.EOTSManagerClient) (err error) {
+		res, err = client.SigningRecord(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SigningRecord{
+		FpPk:      res.Record.FpPk,
+		ChainID:   res.Record.ChainId,
+		Height:    res.Record.Height,
+		MsgHash:   res.Record.MsgHash,
+		Sig:       res.Record.Sig,
+		Timestamp: res.Record.Timestamp,
+	}, nil
+}
+
+func (c *EOTSManagerGRpcClient) Unlock(uid []byte, passphrase string) error {
+	req := &proto.UnlockRequest{Uid: uid, Passphrase: passphrase}
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) error {
+		_, err := client.Unlock(ctx, req)
+		return err
+	})
+
+	return err
+}
+
+func (c *EOTSManagerGRpcClient) Lock(uid []byte) error {
+	req := &proto.LockRequest{Uid: uid}
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) error {
+		_, err := client.Lock(ctx, req)
+		return err
+	})
+
+	return err
+}
+
+func (c *EOTSManagerGRpcClient) Halt(reason string) error {
+	req := &proto.HaltRequest{Reason: reason}
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) error {
+		_, err := client.Halt(ctx, req)
+		return err
+	})
+
+	return err
+}
+
+func (c *EOTSManagerGRpcClient) Resume(uid []byte, passphrase, adminCredential string) error {
+	req := &proto.ResumeRequest{Uid: uid, Passphrase: passphrase, AdminCredential: adminCredential}
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) error {
+		_, err := client.Resume(ctx, req)
+		return err
+	})
+
+	return err
+}
+
+func (c *EOTSManagerGRpcClient) Backup(filePath, passphrase string) error {
+	req := &proto.BackupRequest{Path: filePath, Passphrase: passphrase}
+	err := c.invoke(writeCall, func(ctx context.Context, client proto.EOTSManagerClient) error {
+		_, err := client.Backup(ctx, req)
+		return err
+	})
+
+	return err
+}
+
 func (c *EOTSManagerGRpcClient) Close() error {
-	return c.conn.Close()
+	close(c.quit)
+	c.wg.Wait()
+
+	return c.closeConns()
+}
+
+func (c *EOTSManagerGRpcClient) closeConns() error {
+	var errs []error
+	for _, ep := range c.endpoints {
+		if err := ep.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
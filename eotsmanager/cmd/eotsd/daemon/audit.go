@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/audit"
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+)
+
+var AuditCommands = []cli.Command{
+	{
+		Name:     "audit",
+		Usage:    "Command sets of inspecting the audit log of the signing requests.",
+		Category: "Audit",
+		Subcommands: []cli.Command{
+			VerifyAuditLogCmd,
+			QueryAuditLogCmd,
+		},
+	},
+}
+
+var VerifyAuditLogCmd = cli.Command{
+	Name:  "verify",
+	Usage: "Verify the integrity of the hash chain of the audit log.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the eotsd home directory",
+			Value: config.DefaultEOTSDir,
+		},
+	},
+	Action: verifyAuditLog,
+}
+
+var QueryAuditLogCmd = cli.Command{
+	Name:  "query",
+	Usage: "Query the entries of the audit log.",
+	Description: `The entries of the given EOTS public key within the inclusive range of heights
+	are printed. The integrity of the log is verified while it is read`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the eotsd home directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:  fpFlag,
+			Usage: "The hex string of the EOTS public key to query the entries of, all the keys if empty",
+		},
+		cli.Uint64Flag{
+			Name:  fromHeightFlag,
+			Usage: "The lowest height of the entries to query",
+		},
+		cli.Uint64Flag{
+			Name:  toHeightFlag,
+			Usage: "The highest height of the entries to query, unbounded if 0",
+		},
+	},
+	Action: queryAuditLog,
+}
+
+func verifyAuditLog(ctx *cli.Context) error {
+	auditLogFile, err := getAuditLogFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	n, err := audit.Verify(auditLogFile)
+	if err != nil {
+		return fmt.Errorf("failed to verify the audit log %s: %w", auditLogFile, err)
+	}
+
+	fmt.Printf("The audit log %s is intact with %d entries\n", auditLogFile, n)
+
+	return nil
+}
+
+func queryAuditLog(ctx *cli.Context) error {
+	fromHeight := ctx.Uint64(fromHeightFlag)
+	toHeight := ctx.Uint64(toHeightFlag)
+	if toHeight != 0 && toHeight < fromHeight {
+		return fmt.Errorf("--%s should not be lower than --%s", toHeightFlag, fromHeightFlag)
+	}
+
+	auditLogFile, err := getAuditLogFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := audit.Query(auditLogFile, &audit.Filter{
+		FpPk:       strings.ToLower(ctx.String(fpFlag)),
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the audit log %s: %w", auditLogFile, err)
+	}
+	if entries == nil {
+		entries = []*audit.Entry{}
+	}
+
+	printRespJSON(entries)
+
+	return nil
+}
+
+func getAuditLogFile(ctx *cli.Context) (string, error) {
+	homePath, err := getHomeFlag(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load home flag: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(homePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load config at %s: %w", homePath, err)
+	}
+
+	return cfg.AuditLogFile(), nil
+}
@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/client"
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+)
+
+const backupPassphraseEnvVar = "EOTSD_BACKUP_PASSPHRASE"
+
+var BackupCommand = cli.Command{
+	Name:      "backup",
+	Usage:     "Back up the keyring records and the database to an encrypted archive.",
+	UsageText: "backup [file-path]",
+	Description: `The archive is written by the running daemon to the given path on its host,
+	which should not exist. The database is read in a single transaction so that the
+	archive is consistent while the daemon keeps serving requests. Use --offline to back up
+	with the daemon stopped`,
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:   backupPassphraseFlag,
+			Usage:  "The passphrase used to encrypt the archive",
+			EnvVar: backupPassphraseEnvVar,
+		},
+		cli.BoolFlag{
+			Name:  offlineFlag,
+			Usage: "Open the database directly instead of connecting to the running daemon",
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring, only used with --offline",
+			Value: defaultKeyringBackend,
+		},
+	}, rpcClientFlags...),
+	Action: backupHome,
+}
+
+var RestoreCommand = cli.Command{
+	Name:      "restore",
+	Usage:     "Restore the keyring records and rebuild the database from an encrypted archive.",
+	UsageText: "restore [file-path]",
+	Description: `The manifest of the archive is validated before anything is restored.
+	The restore is refused if the database has signed above the archive or has keys that
+	are not in the archive. The daemon should be stopped while restoring`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the eotsd home directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:   backupPassphraseFlag,
+			Usage:  "The passphrase used to decrypt the archive",
+			EnvVar: backupPassphraseEnvVar,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: restoreHome,
+}
+
+func backupHome(ctx *cli.Context) error {
+	filePath, err := archivePathArg(ctx)
+	if err != nil {
+		return err
+	}
+	passphrase := ctx.String(backupPassphraseFlag)
+	if passphrase == "" {
+		return fmt.Errorf("the passphrase of the archive should be set by --%s", backupPassphraseFlag)
+	}
+
+	if ctx.Bool(offlineFlag) {
+		err = runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+			return em.Backup(filePath, passphrase)
+		})
+	} else {
+		err = runWithEOTSClient(ctx, func(c *client.EOTSManagerGRpcClient) error {
+			return c.Backup(filePath, passphrase)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to back up: %w", err)
+	}
+
+	fmt.Printf("The backup archive is written to %s\n", filePath)
+	return nil
+}
+
+func restoreHome(ctx *cli.Context) error {
+	filePath, err := archivePathArg(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		return em.Restore(filePath, ctx.String(backupPassphraseFlag))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore: %w", err)
+	}
+
+	fmt.Printf("The backup archive %s is restored\n", filePath)
+	return nil
+}
+
+func archivePathArg(ctx *cli.Context) (string, error) {
+	filePath := ctx.Args().First()
+	if len(filePath) == 0 {
+		return "", errors.New("invalid argument, please provide a valid file path as input argument")
+	}
+
+	return filepath.Abs(filePath)
+}
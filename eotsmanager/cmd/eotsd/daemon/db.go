@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+	"github.com/babylonchain/finality-provider/eotsmanager/store"
+)
+
+var DBCommands = []cli.Command{
+	{
+		Name:     "db",
+		Usage:    "Command sets of managing the database.",
+		Category: "Database",
+		Subcommands: []cli.Command{
+			MigrateDBCmd,
+		},
+	},
+}
+
+var MigrateDBCmd = cli.Command{
+	Name:  "migrate",
+	Usage: "Migrate the database to the latest schema version.",
+	Description: `All the pending migrations are applied in a single transaction. The daemon
+	also migrates the database when it starts, so this is only needed to check or apply the
+	migrations beforehand. Use --dry-run to report what would change without writing anything`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the eotsd home directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.BoolFlag{
+			Name:  dryRunFlag,
+			Usage: "Report the pending migrations without applying them",
+		},
+	},
+	Action: migrateDB,
+}
+
+func migrateDB(ctx *cli.Context) error {
+	homePath, err := getHomeFlag(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load home flag: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to load config at %s: %w", homePath, err)
+	}
+
+	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	if err != nil {
+		return fmt.Errorf("failed to create db backend: %w", err)
+	}
+	defer dbBackend.Close()
+
+	report, err := store.Migrate(dbBackend, ctx.Bool(dryRunFlag))
+	if err != nil {
+		return fmt.Errorf("failed to migrate the database: %w", err)
+	}
+
+	fmt.Print(report.String())
+	return nil
+}
@@ -0,0 +1,252 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/babylon/crypto/eots"
+	bbntypes "github.com/babylonchain/babylon/types"
+	ftypes "github.com/babylonchain/babylon/x/finality/types"
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/audit"
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// signEOTSCaller is the caller of the signatures made by sign-eots in the audit log
+const signEOTSCaller = "eotsd sign-eots"
+
+// EOTSSigned is the output of sign-eots
+type EOTSSigned struct {
+	PubKeyHex        string `json:"pub_key_hex"`
+	ChainID          string `json:"chain_id"`
+	Height           uint64 `json:"height"`
+	AppHashHex       string `json:"app_hash_hex"`
+	MsgToSignHex     string `json:"msg_to_sign_hex"`
+	MasterPubRand    string `json:"master_pub_rand"`
+	RandScheme       string `json:"rand_scheme"`
+	PubRandHex       string `json:"pub_rand_hex"`
+	EOTSSignatureHex string `json:"eots_signature_hex"`
+}
+
+var SignEOTSCommand = cli.Command{
+	Name:      "sign-eots",
+	Usage:     "Signs the finality signature over a block with the EOTS private key, without a running finality provider.",
+	UsageText: "sign-eots --fp-pk [fp-pk] --chain-id [chain-id] --height [height] --app-hash [app-hash]",
+	Description: `Build the message that the finality provider signs to vote for the block
+	of the given height and app hash, and sign it with EOTS. The signature is subject to the same
+	slashing protection as the signatures requested by the finality provider, so that signing a
+	different block at a height that has been signed is refused.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:     eotsFpPkFlag + ", " + fpPkFlag,
+			Usage:    "The hex string of the BIP-340 public key of the finality provider",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     chainIdFlag,
+			Usage:    "The identifier of the consumer chain the block belongs to",
+			Required: true,
+		},
+		cli.Uint64Flag{
+			Name:     heightFlag,
+			Usage:    "The height of the block",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     appHashFlag,
+			Usage:    "The hex string of the app hash of the block",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The passphrase used to decrypt the keyring",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: signEOTS,
+}
+
+var VerifyEOTSCommand = cli.Command{
+	Name:      "verify-eots",
+	Usage:     "Verify the finality signature over a block against the master public randomness of the finality provider.",
+	UsageText: "verify-eots --fp-pk [fp-pk] --height [height] --app-hash [app-hash] --master-pub-rand [master-pub-rand] --signature [signature]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     eotsFpPkFlag + ", " + fpPkFlag,
+			Usage:    "The hex string of the BIP-340 public key of the finality provider",
+			Required: true,
+		},
+		cli.Uint64Flag{
+			Name:     heightFlag,
+			Usage:    "The height of the block",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     appHashFlag,
+			Usage:    "The hex string of the app hash of the block",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     masterPubRandFlag,
+			Usage:    "The master public randomness registered by the finality provider",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  randSchemeFlag,
+			Usage: "The scheme deriving the public randomness at each height from the master public randomness, i.e., uint32 or uint64",
+			Value: fpkeyring.RandSchemeUint32,
+		},
+		cli.StringFlag{
+			Name:     signatureFlag,
+			Usage:    "The hex EOTS signature to verify",
+			Required: true,
+		},
+	},
+	Action: verifyEOTS,
+}
+
+func signEOTS(ctx *cli.Context) error {
+	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(ctx.String(eotsFpPkFlag))
+	if err != nil {
+		return fmt.Errorf("invalid finality-provider public key %s: %w", ctx.String(eotsFpPkFlag), err)
+	}
+	chainID := ctx.String(chainIdFlag)
+	height := ctx.Uint64(heightFlag)
+	msgToSign, err := finalitySigMsgToSign(fpPk, height, ctx.String(appHashFlag))
+	if err != nil {
+		return err
+	}
+	passphrase := ctx.String(passphraseFlag)
+
+	homePath, err := getHomeFlag(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load home flag: %w", err)
+	}
+	cfg, err := config.LoadConfig(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to load config at %s: %w", homePath, err)
+	}
+
+	// the log is opened before signing so that no signature is made if it cannot be recorded
+	auditLog, err := audit.Open(cfg.AuditLogFile())
+	if err != nil {
+		return fmt.Errorf("failed to open the audit log: %w", err)
+	}
+	defer auditLog.Close()
+
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		key, err := em.ShowKey(fpPk.MustMarshal(), types.MarshalChainID(chainID), passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to load the key %s: %w", fpPk.MarshalHex(), err)
+		}
+		pubRand, err := derivePubRand(key.MasterPubRand, key.RandScheme, height)
+		if err != nil {
+			return err
+		}
+
+		sig, err := em.SignEOTS(fpPk.MustMarshal(), types.MarshalChainID(chainID), msgToSign, height, passphrase)
+		entry := audit.NewEntry(signEOTSCaller, "SignEOTS", fpPk.MustMarshal(), types.MarshalChainID(chainID), height, msgToSign, err)
+		if auditErr := auditLog.Append(entry); auditErr != nil {
+			return fmt.Errorf("failed to record the signature in the audit log: %w", auditErr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to sign EOTS: %w", err)
+		}
+
+		pubRandBytes := pubRand.Bytes()
+		printRespJSON(EOTSSigned{
+			PubKeyHex:        fpPk.MarshalHex(),
+			ChainID:          chainID,
+			Height:           height,
+			AppHashHex:       ctx.String(appHashFlag),
+			MsgToSignHex:     hex.EncodeToString(msgToSign),
+			MasterPubRand:    key.MasterPubRand,
+			RandScheme:       key.RandScheme,
+			PubRandHex:       hex.EncodeToString(pubRandBytes[:]),
+			EOTSSignatureHex: hex.EncodeToString(bbntypes.NewSchnorrEOTSSigFromModNScalar(sig).MustMarshal()),
+		})
+
+		return nil
+	})
+}
+
+func verifyEOTS(ctx *cli.Context) error {
+	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(ctx.String(eotsFpPkFlag))
+	if err != nil {
+		return fmt.Errorf("invalid finality-provider public key %s: %w", ctx.String(eotsFpPkFlag), err)
+	}
+	height := ctx.Uint64(heightFlag)
+	msgToSign, err := finalitySigMsgToSign(fpPk, height, ctx.String(appHashFlag))
+	if err != nil {
+		return err
+	}
+
+	pubRand, err := derivePubRand(ctx.String(masterPubRandFlag), ctx.String(randSchemeFlag), height)
+	if err != nil {
+		return err
+	}
+
+	sigBytes, err := hex.DecodeString(ctx.String(signatureFlag))
+	if err != nil {
+		return fmt.Errorf("unable to decode signature %s: %w", ctx.String(signatureFlag), err)
+	}
+	sig, err := bbntypes.NewSchnorrEOTSSig(sigBytes)
+	if err != nil {
+		return fmt.Errorf("unable to parse EOTS signature %s: %w", ctx.String(signatureFlag), err)
+	}
+
+	if err := eots.Verify(fpPk.MustToBTCPK(), pubRand, msgToSign, sig.ToModNScalar()); err != nil {
+		return errors.New("invalid signature")
+	}
+
+	fmt.Print("Verification is successful!")
+	return nil
+}
+
+// finalitySigMsgToSign builds the message that the finality provider signs
+// to vote for the block of the given height and hex app hash
+func finalitySigMsgToSign(fpPk *bbntypes.BIP340PubKey, height uint64, appHashHex string) ([]byte, error) {
+	appHash, err := hex.DecodeString(appHashHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid app hash %s: %w", appHashHex, err)
+	}
+
+	msg := &ftypes.MsgAddFinalitySig{
+		FpBtcPk:      fpPk,
+		BlockHeight:  height,
+		BlockAppHash: appHash,
+	}
+
+	return msg.MsgToSign(), nil
+}
+
+// derivePubRand derives the public randomness at the given height
+// from the base58 master public randomness with the given scheme
+func derivePubRand(masterPubRand string, randScheme string, height uint64) (*eots.PublicRand, error) {
+	mpr, err := fpkeyring.NewMasterPublicRandFromBase58(masterPubRand, randScheme)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master public randomness %s: %w", masterPubRand, err)
+	}
+
+	pubRand, err := mpr.DerivePubRand(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the public randomness at height %d: %w", height, err)
+	}
+
+	return pubRand, nil
+}
@@ -8,6 +8,39 @@ const (
 	rpcListenerFlag = "rpc-listener"
 	fpPkFlag        = "btc-pk"
 	signatureFlag   = "signature"
+	tlsFlag         = "tls"
+	tlsHostsFlag    = "tls-hosts"
+	fpFlag          = "fp"
+	fromHeightFlag  = "from-height"
+	toHeightFlag    = "to-height"
+
+	// flags for connecting to the running daemon
+	rpcAddressFlag = "rpc-address"
+	tlsCACertFlag  = "tls-ca-cert"
+	tlsCertFlag    = "tls-cert"
+	tlsKeyFlag     = "tls-key"
+
+	// flags for halting and resuming the signing
+	reasonFlag          = "reason"
+	adminCredentialFlag = "admin-credential"
+
+	// flags for backups
+	backupPassphraseFlag = "backup-passphrase"
+	offlineFlag          = "offline"
+
+	// flags for the database
+	dryRunFlag = "dry-run"
+
+	// flags for EOTS signatures
+	eotsFpPkFlag      = "fp-pk"
+	heightFlag        = "height"
+	appHashFlag       = "app-hash"
+	masterPubRandFlag = "master-pub-rand"
+	randSchemeFlag    = "rand-scheme"
+
+	// flags for forensics
+	txHashFlag            = "tx-hash"
+	babylonRPCAddressFlag = "babylon-rpc-address"
 
 	// flags for keys
 	keyNameFlag        = "key-name"
@@ -15,6 +48,8 @@ const (
 	hdPathFlag         = "hd-path"
 	keyringBackendFlag = "keyring-backend"
 	recoverFlag        = "recover"
+	chainIdFlag        = "chain-id"
+	keyMnemonicFlag    = "key-mnemonic"
 
 	defaultKeyringBackend = keyring.BackendTest
 	defaultHdPath         = ""
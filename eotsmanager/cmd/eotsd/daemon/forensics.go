@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/forensics"
+)
+
+const (
+	defaultBabylonRPCAddress = "http://localhost:26657"
+	queryTxTimeout           = 30 * time.Second
+)
+
+// ExtractedKey is the output of forensics extract
+type ExtractedKey struct {
+	PubKeyHex           string `json:"pub_key_hex"`
+	Height              uint64 `json:"height"`
+	CanonicalAppHashHex string `json:"canonical_app_hash_hex"`
+	ForkAppHashHex      string `json:"fork_app_hash_hex"`
+	ExtractedSkHex      string `json:"extracted_sk_hex"`
+}
+
+var ForensicsCommands = []cli.Command{
+	{
+		Name:     "forensics",
+		Usage:    "Command sets of investigating the equivocations of finality providers.",
+		Category: "Forensics",
+		Subcommands: []cli.Command{
+			ExtractCmd,
+		},
+	},
+}
+
+var ExtractCmd = cli.Command{
+	Name:      "extract",
+	Usage:     "Extract the secret key from two EOTS signatures by the same key at the same height.",
+	UsageText: "extract [equivocation-file] or extract --tx-hash [tx-hash]",
+	Description: `Read the two signatures from the JSON file received as argument, or from the
+	slashing evidence in the events of the Babylon transaction that slashed the finality provider.
+	Both signatures are verified before the secret key is extracted, and the extracted key is
+	confirmed to be the one of the public key. The JSON file has the following fields, in which
+	the public randomness and the signatures are as output by sign-eots:
+	fp_pk_hex, height, pub_rand_hex, canonical_app_hash_hex, canonical_sig_hex, fork_app_hash_hex, fork_sig_hex`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  txHashFlag,
+			Usage: "The hex hash of the Babylon transaction that slashed the finality provider",
+		},
+		cli.StringFlag{
+			Name:  babylonRPCAddressFlag,
+			Usage: "The address of the Babylon RPC node to query the transaction from",
+			Value: defaultBabylonRPCAddress,
+		},
+		cli.StringFlag{
+			Name:  eotsFpPkFlag + ", " + fpPkFlag,
+			Usage: "The hex string of the BIP-340 public key that the equivocation is expected to be by, e.g., one of our own keys",
+		},
+	},
+	Action: extractSK,
+}
+
+func extractSK(ctx *cli.Context) error {
+	equivocation, err := loadEquivocation(ctx)
+	if err != nil {
+		return err
+	}
+
+	if expectedPkHex := ctx.String(eotsFpPkFlag); expectedPkHex != "" {
+		expectedPk, err := bbntypes.NewBIP340PubKeyFromHex(expectedPkHex)
+		if err != nil {
+			return fmt.Errorf("invalid public key %s: %w", expectedPkHex, err)
+		}
+		if !expectedPk.Equals(equivocation.FpPk) {
+			return fmt.Errorf("the equivocation is by the key %s, not %s",
+				equivocation.FpPk.MarshalHex(), expectedPk.MarshalHex())
+		}
+	}
+
+	sk, err := equivocation.ExtractSK()
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(ExtractedKey{
+		PubKeyHex:           equivocation.FpPk.MarshalHex(),
+		Height:              equivocation.Height,
+		CanonicalAppHashHex: hex.EncodeToString(equivocation.CanonicalAppHash),
+		ForkAppHashHex:      hex.EncodeToString(equivocation.ForkAppHash),
+		ExtractedSkHex:      hex.EncodeToString(sk.Serialize()),
+	})
+
+	return nil
+}
+
+// loadEquivocation reads the equivocation from the file argument or the Babylon transaction
+func loadEquivocation(ctx *cli.Context) (*forensics.Equivocation, error) {
+	filePath := ctx.Args().First()
+	txHashHex := ctx.String(txHashFlag)
+	if (filePath == "") == (txHashHex == "") {
+		return nil, fmt.Errorf("either an equivocation file or --%s should be given", txHashFlag)
+	}
+
+	if filePath != "" {
+		bz, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the equivocation file %s: %w", filePath, err)
+		}
+		return forensics.ParseEquivocationJSON(bz)
+	}
+
+	txHash, err := hex.DecodeString(txHashHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hash %s: %w", txHashHex, err)
+	}
+
+	rpcAddress := ctx.String(babylonRPCAddressFlag)
+	rpcClient, err := rpchttp.New(rpcAddress, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Babylon RPC node %s: %w", rpcAddress, err)
+	}
+
+	queryCtx, cancel := context.WithTimeout(context.Background(), queryTxTimeout)
+	defer cancel()
+	res, err := rpcClient.Tx(queryCtx, txHash, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the transaction %s: %w", txHashHex, err)
+	}
+
+	evidence, err := forensics.EvidenceFromEvents(res.TxResult.Events)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: %w", txHashHex, err)
+	}
+
+	return forensics.NewEquivocationFromEvidence(evidence)
+}
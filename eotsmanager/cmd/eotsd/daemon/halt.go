@@ -0,0 +1,172 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/client"
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+	"github.com/babylonchain/finality-provider/util"
+)
+
+const (
+	adminCredentialEnvVar = "EOTSD_ADMIN_CREDENTIAL"
+
+	// adminRequestTimeout bounds the requests to the running daemon from the commands
+	adminRequestTimeout = 10 * time.Minute
+)
+
+// rpcClientFlags are the flags of the commands that connect to the running daemon
+var rpcClientFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  homeFlag,
+		Usage: "Path to the eotsd home directory",
+		Value: config.DefaultEOTSDir,
+	},
+	cli.StringFlag{
+		Name:  rpcAddressFlag,
+		Usage: "The address of the RPC server of the daemon, the RPC listener in the config if empty",
+	},
+	cli.StringFlag{
+		Name:  tlsCACertFlag,
+		Usage: "Path to the CA certificate to verify the daemon with, the generated one under the home directory if empty and TLS is enabled in the config",
+	},
+	cli.StringFlag{
+		Name:  tlsCertFlag,
+		Usage: "Path to the client certificate, the generated one under the home directory if empty and TLS is enabled in the config",
+	},
+	cli.StringFlag{
+		Name:  tlsKeyFlag,
+		Usage: "Path to the private key of the client certificate",
+	},
+}
+
+var HaltCommand = cli.Command{
+	Name:  "halt",
+	Usage: "Halt all the signing of the running daemon.",
+	Description: `All the signing requests are refused until the signing is resumed,
+	even if the daemon is restarted`,
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  reasonFlag,
+			Usage: "The reason why the signing is halted",
+		},
+	}, rpcClientFlags...),
+	Action: halt,
+}
+
+var ResumeCommand = cli.Command{
+	Name:  "resume",
+	Usage: "Resume the halted signing of the running daemon.",
+	Description: `Resuming requires either the passphrase of an EOTS key, which is only
+	accepted with the file keyring backend, or the admin credential whose hash is set in the config`,
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  fpPkFlag,
+			Usage: "The hex string of the EOTS public key whose passphrase authorizes resuming",
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The passphrase used to decrypt the keyring",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:   adminCredentialFlag,
+			Usage:  "The admin credential that authorizes resuming",
+			EnvVar: adminCredentialEnvVar,
+		},
+	}, rpcClientFlags...),
+	Action: resume,
+}
+
+func halt(ctx *cli.Context) error {
+	return runWithEOTSClient(ctx, func(c *client.EOTSManagerGRpcClient) error {
+		if err := c.Halt(ctx.String(reasonFlag)); err != nil {
+			return fmt.Errorf("failed to halt the signing: %w", err)
+		}
+
+		fmt.Println("The signing is halted")
+		return nil
+	})
+}
+
+func resume(ctx *cli.Context) error {
+	var fpPk []byte
+	adminCredential := ctx.String(adminCredentialFlag)
+	if adminCredential == "" {
+		fpPkHex := ctx.String(fpPkFlag)
+		if fpPkHex == "" {
+			return fmt.Errorf("either --%s or --%s should be set", fpPkFlag, adminCredentialFlag)
+		}
+		pk, err := bbntypes.NewBIP340PubKeyFromHex(fpPkHex)
+		if err != nil {
+			return fmt.Errorf("invalid public key %s: %w", fpPkHex, err)
+		}
+		fpPk = pk.MustMarshal()
+	}
+
+	return runWithEOTSClient(ctx, func(c *client.EOTSManagerGRpcClient) error {
+		if err := c.Resume(fpPk, ctx.String(passphraseFlag), adminCredential); err != nil {
+			return fmt.Errorf("failed to resume the signing: %w", err)
+		}
+
+		fmt.Println("The signing is resumed")
+		return nil
+	})
+}
+
+// runWithEOTSClient connects to the running daemon with the config under the home directory
+func runWithEOTSClient(ctx *cli.Context, fn func(c *client.EOTSManagerGRpcClient) error) error {
+	homePath, err := getHomeFlag(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load home flag: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to load config at %s: %w", homePath, err)
+	}
+
+	rpcAddress := ctx.String(rpcAddressFlag)
+	if rpcAddress == "" {
+		rpcAddress = cfg.RpcListener
+	}
+
+	caCertPath := ctx.String(tlsCACertFlag)
+	certPath := ctx.String(tlsCertFlag)
+	keyPath := ctx.String(tlsKeyFlag)
+	// fall back to the certificates generated by init --tls
+	if caCertPath == "" && cfg.TLS.Enabled() {
+		tlsDir := config.TLSDir(homePath)
+		caCertPath = filepath.Join(tlsDir, config.CACertFileName)
+		if !util.FileExists(caCertPath) {
+			return fmt.Errorf("TLS is enabled but the CA certificate is not found in %s, set --%s", tlsDir, tlsCACertFlag)
+		}
+		if certPath == "" && util.FileExists(filepath.Join(tlsDir, config.ClientCertFileName)) {
+			certPath = filepath.Join(tlsDir, config.ClientCertFileName)
+			keyPath = filepath.Join(tlsDir, config.ClientKeyFileName)
+		}
+	}
+
+	tlsCfg, err := config.NewClientTLSConfig(caCertPath, certPath, keyPath)
+	if err != nil {
+		return err
+	}
+
+	// backing up a large database could take longer than the signing requests
+	opts := client.DefaultOptions()
+	opts.RequestTimeout = adminRequestTimeout
+	opts.HealthCheckInterval = 0
+	c, err := client.NewEOTSManagerGRpcClientWithEndpoints([]*client.Endpoint{{Address: rpcAddress}}, tlsCfg, opts, zap.NewNop())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return fn(c)
+}
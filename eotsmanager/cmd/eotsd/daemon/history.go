@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+	"github.com/babylonchain/finality-provider/log"
+)
+
+var HistoryCommands = []cli.Command{
+	{
+		Name:     "history",
+		Usage:    "Command sets of managing the signing history used for slashing protection.",
+		Category: "Slashing protection",
+		Subcommands: []cli.Command{
+			ExportHistoryCmd,
+			ImportHistoryCmd,
+		},
+	},
+}
+
+var ExportHistoryCmd = cli.Command{
+	Name:      "export",
+	Usage:     "Export the signing history of all the EOTS keys to a JSON interchange file.",
+	UsageText: "history export [file-path]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: exportHistory,
+}
+
+var ImportHistoryCmd = cli.Command{
+	Name:      "import",
+	Usage:     "Import the signing history from a JSON interchange file.",
+	UsageText: "history import [file-path]",
+	Description: `The imported history is merged with the existing one. Existing records
+	are kept and the signed watermarks are never lowered. The import is rejected if any of
+	the keys is not in the local keyring or any record conflicts with the existing one`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The passphrase used to decrypt the keyring",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: importHistory,
+}
+
+func exportHistory(ctx *cli.Context) error {
+	outputFilePath := ctx.Args().First()
+	if len(outputFilePath) == 0 {
+		return errors.New("invalid argument, please provide a valid file path as input argument")
+	}
+
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		interchange, err := em.ExportSigningHistory()
+		if err != nil {
+			return err
+		}
+
+		interchangeBytes, err := json.MarshalIndent(interchange, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to encode the signing history: %w", err)
+		}
+
+		if err := os.WriteFile(outputFilePath, interchangeBytes, 0600); err != nil {
+			return fmt.Errorf("failed to write the signing history to %s: %w", outputFilePath, err)
+		}
+
+		fmt.Printf("Signing history of %d keys is exported to %s\n", len(interchange.Data), outputFilePath)
+		return nil
+	})
+}
+
+func importHistory(ctx *cli.Context) error {
+	inputFilePath := ctx.Args().First()
+	if len(inputFilePath) == 0 {
+		return errors.New("invalid argument, please provide a valid file path as input argument")
+	}
+
+	interchangeBytes, err := os.ReadFile(inputFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read the file %s: %w", inputFilePath, err)
+	}
+
+	var interchange eotsmanager.SigningHistoryInterchange
+	if err := json.Unmarshal(interchangeBytes, &interchange); err != nil {
+		return fmt.Errorf("failed to decode the signing history from %s: %w", inputFilePath, err)
+	}
+
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		if err := em.ImportSigningHistory(&interchange, ctx.String(passphraseFlag)); err != nil {
+			return err
+		}
+
+		fmt.Printf("Signing history of %d keys is imported from %s\n", len(interchange.Data), inputFilePath)
+		return nil
+	})
+}
+
+// runWithLocalEOTSManager creates a local EOTS manager from the home directory
+// and runs the given function with it
+func runWithLocalEOTSManager(ctx *cli.Context, fn func(em *eotsmanager.LocalEOTSManager) error) error {
+	homePath, err := getHomeFlag(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load home flag: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to load config at %s: %w", homePath, err)
+	}
+
+	logger, err := log.NewRootLoggerWithFile(config.LogFile(homePath), cfg.LogLevel)
+	if err != nil {
+		return fmt.Errorf("failed to load the logger")
+	}
+
+	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	if err != nil {
+		return fmt.Errorf("failed to create db backend: %w", err)
+	}
+	defer dbBackend.Close()
+
+	eotsManager, err := eotsmanager.NewLocalEOTSManager(homePath, ctx.String(keyringBackendFlag), dbBackend, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create EOTS manager: %w", err)
+	}
+
+	return fn(eotsManager)
+}
@@ -25,6 +25,14 @@ var InitCommand = cli.Command{
 			Usage:    "Override existing configuration",
 			Required: false,
 		},
+		cli.BoolFlag{
+			Name:  tlsFlag,
+			Usage: "Generate a self-signed CA, and a server and a client certificate issued by it, and enable TLS with client certificate verification",
+		},
+		cli.StringSliceFlag{
+			Name:  tlsHostsFlag,
+			Usage: "The extra IP addresses or domain names the server certificate is valid for besides localhost, e.g., 10.0.0.2",
+		},
 	},
 	Action: initHome,
 }
@@ -58,6 +66,22 @@ func initHome(c *cli.Context) error {
 
 	defaultConfig := eotscfg.DefaultConfig()
 	defaultConfig.DatabaseConfig.DBPath = dataDir
+
+	if c.Bool(tlsFlag) {
+		tlsDir := eotscfg.TLSDir(homePath)
+		if err := eotscfg.GenerateTLSCerts(tlsDir, c.StringSlice(tlsHostsFlag)); err != nil {
+			return fmt.Errorf("failed to generate TLS certificates: %w", err)
+		}
+		defaultConfig.TLS.CertPath = filepath.Join(tlsDir, eotscfg.ServerCertFileName)
+		defaultConfig.TLS.KeyPath = filepath.Join(tlsDir, eotscfg.ServerKeyFileName)
+		defaultConfig.TLS.ClientCAPath = filepath.Join(tlsDir, eotscfg.CACertFileName)
+
+		fmt.Printf("TLS certificates are generated in %s\n", tlsDir)
+		fmt.Printf("Copy %s, %s and %s to the finality provider and set them as "+
+			"EOTSManagerTLSCACert, EOTSManagerTLSCert and EOTSManagerTLSKey in fpd.conf\n",
+			eotscfg.CACertFileName, eotscfg.ClientCertFileName, eotscfg.ClientKeyFileName)
+	}
+
 	fileParser := flags.NewParser(defaultConfig, flags.Default)
 
 	return flags.NewIniParser(fileParser).WriteFile(eotscfg.ConfigFile(homePath), flags.IniIncludeComments|flags.IniIncludeDefaults)
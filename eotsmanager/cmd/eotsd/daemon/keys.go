@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"bufio"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	"github.com/babylonchain/finality-provider/eotsmanager/config"
 	"github.com/babylonchain/finality-provider/log"
+	"github.com/babylonchain/finality-provider/types"
 )
 
 type KeyOutput struct {
@@ -23,6 +25,14 @@ type KeyOutput struct {
 	Mnemonic  string `json:"mnemonic,omitempty" yaml:"mnemonic"`
 }
 
+type KeyInfoOutput struct {
+	Name          string `json:"name" yaml:"name"`
+	PubKeyHex     string `json:"pub_key_hex" yaml:"pub_key_hex"`
+	ChainID       string `json:"chain_id,omitempty" yaml:"chain_id"`
+	MasterPubRand string `json:"master_pub_rand,omitempty" yaml:"master_pub_rand"`
+	RandScheme    string `json:"rand_scheme,omitempty" yaml:"rand_scheme"`
+}
+
 var KeysCommands = []cli.Command{
 	{
 		Name:     "keys",
@@ -30,6 +40,11 @@ var KeysCommands = []cli.Command{
 		Category: "Key management",
 		Subcommands: []cli.Command{
 			AddKeyCmd,
+			ListKeysCmd,
+			ShowKeyCmd,
+			ImportMnemonicCmd,
+			ExportMnemonicCmd,
+			DeleteKeyCmd,
 		},
 	},
 }
@@ -155,6 +170,305 @@ func getMnemonic(ctx *cli.Context) (string, error) {
 	return eotsmanager.NewMnemonic()
 }
 
+var ListKeysCmd = cli.Command{
+	Name:  "list",
+	Usage: "List the keys in the EOTS manager keyring.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: listKeys,
+}
+
+var ShowKeyCmd = cli.Command{
+	Name:  "show",
+	Usage: "Show the BIP-340 public key of a key and its master public randomness for a chain.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:  keyNameFlag,
+			Usage: "The name of the key; either this or the public key should be set",
+		},
+		cli.StringFlag{
+			Name:  fpPkFlag,
+			Usage: "The hex string of the BIP-340 public key of the key",
+		},
+		cli.StringFlag{
+			Name:  chainIdFlag,
+			Usage: "The identifier of the consumer chain to show the master public randomness for",
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to decrypt the key, needed to derive the master public randomness",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: showKey,
+}
+
+var ImportMnemonicCmd = cli.Command{
+	Name:  "import-mnemonic",
+	Usage: "Import a key to the EOTS manager keyring from a mnemonic read from stdin.",
+	Description: `The mnemonic is the seed phrase that the key is derived from with the hd path,
+	or the key mnemonic printed by export-mnemonic if --key-mnemonic is set`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:     keyNameFlag,
+			Usage:    "The name of the key to be imported",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to encrypt the keys",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  hdPathFlag,
+			Usage: "The hd path used to derive the private key",
+			Value: defaultHdPath,
+		},
+		cli.BoolFlag{
+			Name:  keyMnemonicFlag,
+			Usage: "Whether the mnemonic is a key mnemonic printed by export-mnemonic",
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: importMnemonic,
+}
+
+var ExportMnemonicCmd = cli.Command{
+	Name:  "export-mnemonic",
+	Usage: "Export the private key of a key as a BIP-39 key mnemonic.",
+	Description: `The key mnemonic encodes the private key itself, as the seed phrase that
+	the key is created from is not kept in the keyring. It can be imported by
+	import-mnemonic --key-mnemonic and should be kept in a safe place`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:  keyNameFlag,
+			Usage: "The name of the key; either this or the public key should be set",
+		},
+		cli.StringFlag{
+			Name:  fpPkFlag,
+			Usage: "The hex string of the BIP-340 public key of the key",
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to decrypt the key",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: exportMnemonic,
+}
+
+var DeleteKeyCmd = cli.Command{
+	Name:  "delete",
+	Usage: "Delete a key from the EOTS manager keyring.",
+	Description: `The signing history of the key is kept, so that it is still protected
+	from double signing if it is imported again`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.StringFlag{
+			Name:  keyNameFlag,
+			Usage: "The name of the key; either this or the public key should be set",
+		},
+		cli.StringFlag{
+			Name:  fpPkFlag,
+			Usage: "The hex string of the BIP-340 public key of the key",
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to decrypt the key",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+	},
+	Action: deleteKey,
+}
+
+func listKeys(ctx *cli.Context) error {
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		keys, err := em.ListKeys()
+		if err != nil {
+			return err
+		}
+
+		keysOutput := make([]KeyInfoOutput, len(keys))
+		for i, k := range keys {
+			keysOutput[i] = KeyInfoOutput{
+				Name:      k.Name,
+				PubKeyHex: hex.EncodeToString(k.FpPk),
+			}
+		}
+		printRespJSON(keysOutput)
+
+		return nil
+	})
+}
+
+func showKey(ctx *cli.Context) error {
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		fpPk, err := getKeyPk(ctx, em)
+		if err != nil {
+			return err
+		}
+
+		chainID := ctx.String(chainIdFlag)
+		key, err := em.ShowKey(fpPk, types.MarshalChainID(chainID), ctx.String(passphraseFlag))
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(KeyInfoOutput{
+			Name:          key.Name,
+			PubKeyHex:     hex.EncodeToString(key.FpPk),
+			ChainID:       chainID,
+			MasterPubRand: key.MasterPubRand,
+			RandScheme:    key.RandScheme,
+		})
+
+		return nil
+	})
+}
+
+func importMnemonic(ctx *cli.Context) error {
+	reader := bufio.NewReader(os.Stdin)
+	mnemonic, err := input.GetString("Enter your mnemonic", reader)
+	if err != nil {
+		return fmt.Errorf("failed to read mnemonic from stdin: %w", err)
+	}
+
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		keyName := ctx.String(keyNameFlag)
+		fpPk, err := em.ImportMnemonic(
+			keyName,
+			ctx.String(passphraseFlag),
+			ctx.String(hdPathFlag),
+			mnemonic,
+			ctx.Bool(keyMnemonicFlag),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to import key: %w", err)
+		}
+
+		printRespJSON(KeyInfoOutput{
+			Name:      keyName,
+			PubKeyHex: hex.EncodeToString(fpPk),
+		})
+
+		return nil
+	})
+}
+
+func exportMnemonic(ctx *cli.Context) error {
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		fpPk, err := getKeyPk(ctx, em)
+		if err != nil {
+			return err
+		}
+
+		keyMnemonic, err := em.ExportMnemonic(fpPk, ctx.String(passphraseFlag))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Key mnemonic of the key %s "+
+			"(it should be kept in a safe place):\n%s\n", hex.EncodeToString(fpPk), keyMnemonic)
+
+		return nil
+	})
+}
+
+func deleteKey(ctx *cli.Context) error {
+	return runWithLocalEOTSManager(ctx, func(em *eotsmanager.LocalEOTSManager) error {
+		fpPk, err := getKeyPk(ctx, em)
+		if err != nil {
+			return err
+		}
+
+		if err := em.DeleteKey(fpPk, ctx.String(passphraseFlag)); err != nil {
+			return err
+		}
+
+		fmt.Printf("Key %s is deleted\n", hex.EncodeToString(fpPk))
+
+		return nil
+	})
+}
+
+// getKeyPk returns the public key set by the flag, or the one of the key
+// with the name set by the flag; the public key takes priority if both are set
+func getKeyPk(ctx *cli.Context, em *eotsmanager.LocalEOTSManager) ([]byte, error) {
+	fpPkHex := ctx.String(fpPkFlag)
+	keyName := ctx.String(keyNameFlag)
+	if fpPkHex == "" && keyName == "" {
+		return nil, fmt.Errorf("either --%s or --%s should be set", keyNameFlag, fpPkFlag)
+	}
+
+	if fpPkHex != "" {
+		fpPk, err := bbntypes.NewBIP340PubKeyFromHex(fpPkHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", fpPkHex, err)
+		}
+		return fpPk.MustMarshal(), nil
+	}
+
+	keys, err := em.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if k.Name == keyName {
+			return k.FpPk, nil
+		}
+	}
+
+	return nil, fmt.Errorf("key %s is not found", keyName)
+}
+
 func printRespJSONKeys(resp interface{}) {
 	jsonBytes, err := json.MarshalIndent(resp, "", "    ")
 	if err != nil {
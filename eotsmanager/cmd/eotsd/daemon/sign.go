@@ -11,12 +11,16 @@ import (
 
 	bbntypes "github.com/babylonchain/babylon/types"
 	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/audit"
 	"github.com/babylonchain/finality-provider/eotsmanager/config"
 	"github.com/babylonchain/finality-provider/log"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/urfave/cli"
 )
 
+// signSchnorrCaller is the caller of the signatures made by sign-schnorr in the audit log
+const signSchnorrCaller = "eotsd sign-schnorr"
+
 type DataSigned struct {
 	KeyName             string `json:"key_name"`
 	PubKeyHex           string `json:"pub_key_hex"`
@@ -168,7 +172,28 @@ func SignSchnorr(ctx *cli.Context) error {
 		return fmt.Errorf("failed to generate hash from file %s: %w", inputFilePath, err)
 	}
 
+	// the log is opened before signing so that no signature is made if it cannot be recorded
+	auditLog, err := audit.Open(cfg.AuditLogFile())
+	if err != nil {
+		return fmt.Errorf("failed to open the audit log: %w", err)
+	}
+	defer auditLog.Close()
+
 	signature, pubKey, err := singMsg(eotsManager, keyName, fpPkStr, passphrase, hashOfMsgToSign)
+
+	entry := audit.NewEntry(signSchnorrCaller, "SignSchnorrSig", nil, nil, 0, hashOfMsgToSign, err)
+	entry.FpPk = fpPkStr
+	if len(fpPkStr) == 0 {
+		entry.Method = "SignSchnorrSigFromKeyname"
+		entry.FpPk = keyName
+	}
+	if pubKey != nil {
+		entry.FpPk = pubKey.MarshalHex()
+	}
+	if auditErr := auditLog.Append(entry); auditErr != nil {
+		return fmt.Errorf("failed to record the signature in the audit log: %w", auditErr)
+	}
+
 	if err != nil {
 		return fmt.Errorf("failed to sign msg: %w", err)
 	}
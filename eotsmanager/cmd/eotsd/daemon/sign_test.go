@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	sdkmath "cosmossdk.io/math"
 	dcli "github.com/babylonchain/finality-provider/eotsmanager/cmd/eotsd/daemon"
@@ -150,6 +151,55 @@ func testApp() *cli.App {
 	app := cli.NewApp()
 	app.Name = "eotsd"
 	app.Commands = append(app.Commands, dcli.StartCommand, dcli.InitCommand, dcli.SignSchnorrSig, dcli.VerifySchnorrSig)
+	app.Commands = append(app.Commands, dcli.SignEOTSCommand, dcli.VerifyEOTSCommand)
 	app.Commands = append(app.Commands, dcli.KeysCommands...)
+	app.Commands = append(app.Commands, dcli.HistoryCommands...)
 	return app
 }
+
+func TestSignAndVerifyEOTS(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	homeDir := filepath.Join(t.TempDir(), "eots-home")
+	app := testApp()
+	hFlag := fmt.Sprintf("--home=%s", homeDir)
+	err := app.Run([]string{"eotsd", "init", hFlag})
+	require.NoError(t, err)
+
+	outputKeysAdd := appRunWithOutput(r, t, app, []string{"eotsd", "keys", "add", hFlag, "--key-name=eots-key"})
+	var keyOut dcli.KeyOutput
+	err = json.Unmarshal([]byte(searchInTxt(outputKeysAdd, "for recovery):")), &keyOut)
+	require.NoError(t, err)
+
+	fpPkFlag := fmt.Sprintf("--fp-pk=%s", keyOut.PubKeyHex)
+	appHash := testutil.GenRandomHexStr(r, 32)
+	output := appRunWithOutput(r, t, app, []string{"eotsd", "sign-eots", hFlag, fpPkFlag,
+		"--chain-id=chain-test", "--height=100", fmt.Sprintf("--app-hash=%s", appHash)})
+	var signed dcli.EOTSSigned
+	err = json.Unmarshal([]byte(searchInTxt(output, "")), &signed)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), signed.Height)
+
+	verifyArgs := func(height uint64, appHash string) []string {
+		return []string{"eotsd", "verify-eots", fpPkFlag,
+			fmt.Sprintf("--height=%d", height),
+			fmt.Sprintf("--app-hash=%s", appHash),
+			fmt.Sprintf("--master-pub-rand=%s", signed.MasterPubRand),
+			fmt.Sprintf("--rand-scheme=%s", signed.RandScheme),
+			fmt.Sprintf("--signature=%s", signed.EOTSSignatureHex),
+		}
+	}
+	err = app.Run(verifyArgs(100, appHash))
+	require.NoError(t, err)
+
+	// the signature does not verify against another block
+	err = app.Run(verifyArgs(101, appHash))
+	require.Error(t, err)
+	err = app.Run(verifyArgs(100, testutil.GenRandomHexStr(r, 32)))
+	require.Error(t, err)
+
+	// signing another block at the same height is refused
+	err = app.Run([]string{"eotsd", "sign-eots", hFlag, fpPkFlag,
+		"--chain-id=chain-test", "--height=100", fmt.Sprintf("--app-hash=%s", testutil.GenRandomHexStr(r, 32))})
+	require.ErrorContains(t, err, "double sign")
+}
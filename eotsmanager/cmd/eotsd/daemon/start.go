@@ -2,7 +2,6 @@ package daemon
 
 import (
 	"fmt"
-	"net"
 	"path/filepath"
 
 	"github.com/lightningnetwork/lnd/signal"
@@ -27,7 +26,7 @@ var StartCommand = cli.Command{
 		},
 		cli.StringFlag{
 			Name:  rpcListenerFlag,
-			Usage: "The address that the RPC server listens to",
+			Usage: "The address that the RPC server listens to, either host:port or unix:///path/to/socket",
 		},
 	},
 	Action: startFn,
@@ -46,8 +45,7 @@ func startFn(ctx *cli.Context) error {
 
 	rpcListener := ctx.String(rpcListenerFlag)
 	if rpcListener != "" {
-		_, err := net.ResolveTCPAddr("tcp", rpcListener)
-		if err != nil {
+		if _, _, err := config.ParseRPCAddress(rpcListener); err != nil {
 			return fmt.Errorf("invalid RPC listener address %s, %w", rpcListener, err)
 		}
 		cfg.RpcListener = rpcListener
@@ -67,6 +65,19 @@ func startFn(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create EOTS manager: %w", err)
 	}
+	eotsManager.EnableKeyCache(cfg.UnlockedKeyTTL, cfg.RandWindowSize)
+
+	signingPolicies, err := cfg.ParseSigningPolicies()
+	if err != nil {
+		return fmt.Errorf("invalid signing policies: %w", err)
+	}
+	eotsManager.SetSigningPolicies(signingPolicies)
+
+	adminCredHash, err := cfg.AdminCredentialHash()
+	if err != nil {
+		return err
+	}
+	eotsManager.SetAdminCredentialHash(adminCredHash)
 
 	// Hook interceptor for os signals.
 	shutdownInterceptor, err := signal.Intercept()
@@ -19,7 +19,14 @@ func main() {
 	app.Name = "eotsd"
 	app.Usage = "Extractable One Time Signature Daemon (eotsd)."
 	app.Commands = append(app.Commands, dcli.StartCommand, dcli.InitCommand, dcli.SignSchnorrSig, dcli.VerifySchnorrSig)
+	app.Commands = append(app.Commands, dcli.SignEOTSCommand, dcli.VerifyEOTSCommand)
 	app.Commands = append(app.Commands, dcli.KeysCommands...)
+	app.Commands = append(app.Commands, dcli.HistoryCommands...)
+	app.Commands = append(app.Commands, dcli.AuditCommands...)
+	app.Commands = append(app.Commands, dcli.HaltCommand, dcli.ResumeCommand)
+	app.Commands = append(app.Commands, dcli.BackupCommand, dcli.RestoreCommand)
+	app.Commands = append(app.Commands, dcli.DBCommands...)
+	app.Commands = append(app.Commands, dcli.ForensicsCommands...)
 
 	if err := app.Run(os.Args); err != nil {
 		fatal(err)
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"net"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
@@ -22,6 +24,9 @@ const (
 	defaultConfigFileName = "eotsd.conf"
 	DefaultRPCPort        = 12582
 	defaultKeyringBackend = keyring.BackendTest
+	defaultUnlockedKeyTTL = time.Hour
+	defaultRandWindowSize = 100
+	defaultAuditLogName   = "audit.log"
 )
 
 var (
@@ -37,8 +42,16 @@ var (
 type Config struct {
 	LogLevel       string          `long:"loglevel" description:"Logging level for all subsystems" choice:"trace" choice:"debug" choice:"info" choice:"warn" choice:"error" choice:"fatal"`
 	KeyringBackend string          `long:"keyring-type" description:"Type of keyring to use"`
-	RpcListener    string          `long:"rpclistener" description:"the listener for RPC connections, e.g., 127.0.0.1:1234"`
+	RpcListener    string          `long:"rpclistener" description:"the listener for RPC connections, e.g., 127.0.0.1:1234 or unix:///var/run/eotsd/eotsd.sock"`
+	RpcSocketMode  string          `long:"rpcsocketmode" description:"The octal file mode of the unix socket of the RPC listener"`
+	RpcSocketUIDs  []uint32        `long:"rpcsocketuid" description:"The UID of a process allowed to connect to the unix socket of the RPC listener, checked with SO_PEERCRED on Linux; it can be set multiple times and any UID is allowed if not set"`
+	UnlockedKeyTTL time.Duration   `long:"unlockedkeyttl" description:"The duration for which an unlocked EOTS key is kept in memory since it was last used, 0 disables caching the unlocked keys"`
+	RandWindowSize uint32          `long:"randwindowsize" description:"The number of heights after the last signed height of each unlocked key and chain to derive the randomness for in the background, 0 disables it"`
+	AllowKeyExport bool            `long:"allow-key-export" description:"Allow the EOTS private keys to be exported through the KeyRecord, ExportMnemonic and Backup RPCs; this should only be enabled for testing"`
+	AdminCredHash  string          `long:"admincredhash" description:"The hex of the SHA-256 hash of the admin credential, which can resume the halted signing without the passphrase of a key; resuming requires a key passphrase, which is only accepted with the file keyring backend, if empty"`
+	SigningPolicy  []string        `long:"signingpolicy" description:"The signing policy of a key in the format of fp=<pk hex or *>;chainids=<id>,<id>;maxheightjump=<n>;monotonic=<bool>;rps=<n>, where the policy of * applies to the keys without their own policy; it can be set once for each key"`
 	Metrics        *metrics.Config `group:"metrics" namespace:"metrics"`
+	TLS            *TLSConfig      `group:"tls" namespace:"tls"`
 
 	DatabaseConfig *DBConfig `group:"dbconfig" namespace:"dbconfig"`
 }
@@ -80,15 +93,18 @@ func LoadConfig(homePath string) (*Config, error) {
 // illegal values or combination of values are set. All file system paths are
 // normalized. The cleaned up config is returned on success.
 func (cfg *Config) Validate() error {
-	_, err := net.ResolveTCPAddr("tcp", cfg.RpcListener)
-	if err != nil {
-		return fmt.Errorf("invalid RPC listener address %s, %w", cfg.RpcListener, err)
+	if err := cfg.validateRPCListener(); err != nil {
+		return err
 	}
 
 	if cfg.KeyringBackend == "" {
 		return fmt.Errorf("the keyring backend should not be empty")
 	}
 
+	if cfg.UnlockedKeyTTL < 0 {
+		return fmt.Errorf("the unlocked key TTL should not be negative")
+	}
+
 	if cfg.Metrics == nil {
 		return fmt.Errorf("empty metrics config")
 	}
@@ -97,9 +113,36 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("invalid metrics config")
 	}
 
+	if err := cfg.TLS.Validate(); err != nil {
+		return fmt.Errorf("invalid TLS config: %w", err)
+	}
+
+	if _, err := cfg.ParseSigningPolicies(); err != nil {
+		return fmt.Errorf("invalid signing policies: %w", err)
+	}
+
+	if _, err := cfg.AdminCredentialHash(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// AdminCredentialHash returns the decoded hash of the admin credential,
+// or nil if it is not configured
+func (cfg *Config) AdminCredentialHash() ([]byte, error) {
+	if cfg.AdminCredHash == "" {
+		return nil, nil
+	}
+
+	hash, err := hex.DecodeString(cfg.AdminCredHash)
+	if err != nil || len(hash) != sha256.Size {
+		return nil, fmt.Errorf("the admin credential hash should be the hex of a SHA-256 hash")
+	}
+
+	return hash, nil
+}
+
 func ConfigFile(homePath string) string {
 	return filepath.Join(homePath, defaultConfigFileName)
 }
@@ -116,6 +159,12 @@ func DataDir(homePath string) string {
 	return filepath.Join(homePath, defaultDataDirname)
 }
 
+// AuditLogFile returns the path of the audit log of the signing requests,
+// which is kept next to the database
+func (cfg *Config) AuditLogFile() string {
+	return filepath.Join(cfg.DatabaseConfig.DBPath, defaultAuditLogName)
+}
+
 func DefaultConfig() *Config {
 	return DefaultConfigWithHomePath(DefaultEOTSDir)
 }
@@ -126,7 +175,11 @@ func DefaultConfigWithHomePath(homePath string) *Config {
 		KeyringBackend: defaultKeyringBackend,
 		DatabaseConfig: DefaultDBConfigWithHomePath(homePath),
 		RpcListener:    defaultRpcListener,
+		RpcSocketMode:  fmt.Sprintf("%04o", defaultRpcSocketMode),
+		UnlockedKeyTTL: defaultUnlockedKeyTTL,
+		RandWindowSize: defaultRandWindowSize,
 		Metrics:        metrics.DefaultEotsConfig(),
+		TLS:            DefaultTLSConfig(),
 	}
 	if err := cfg.Validate(); err != nil {
 		panic(err)
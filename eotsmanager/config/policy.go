@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+const (
+	// AnyKey is the key of the signing policy that applies to
+	// the keys without their own policies
+	AnyKey = "*"
+
+	policyFieldSep   = ";"
+	policyKVSep      = "="
+	policyChainIDSep = ","
+
+	policyFpKey            = "fp"
+	policyChainIDsKey      = "chainids"
+	policyMaxHeightJumpKey = "maxheightjump"
+	policyMonotonicKey     = "monotonic"
+	policyRPSKey           = "rps"
+)
+
+// SigningPolicy is the set of rules that the signing requests of an EOTS key
+// should follow, the zero value of each rule means no restriction
+type SigningPolicy struct {
+	// FpPk is the hex of the EOTS public key that the policy applies to, or AnyKey
+	FpPk string
+	// ChainIDs are the chains that EOTS signatures are allowed for
+	ChainIDs []string
+	// MaxHeightJump is the maximum height of an EOTS signature above
+	// the highest height signed before
+	MaxHeightJump uint64
+	// Monotonic refuses EOTS signatures below the highest height signed before
+	Monotonic bool
+	// RequestsPerSecond is the maximum rate of the signing requests
+	RequestsPerSecond float64
+}
+
+// ParseSigningPolicy parses the policy in the format of
+// fp=<pk hex or *>;chainids=<id>,<id>;maxheightjump=<n>;monotonic=<bool>;rps=<n>
+// where all the fields but fp are optional
+func ParseSigningPolicy(s string) (*SigningPolicy, error) {
+	policy := &SigningPolicy{}
+	for _, field := range strings.Split(s, policyFieldSep) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, policyKVSep)
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q of the signing policy, expected key=value", field)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case policyFpKey:
+			policy.FpPk, err = parsePolicyFpPk(value)
+		case policyChainIDsKey:
+			for _, chainID := range strings.Split(value, policyChainIDSep) {
+				if chainID = strings.TrimSpace(chainID); chainID != "" {
+					policy.ChainIDs = append(policy.ChainIDs, chainID)
+				}
+			}
+		case policyMaxHeightJumpKey:
+			policy.MaxHeightJump, err = strconv.ParseUint(value, 10, 64)
+		case policyMonotonicKey:
+			policy.Monotonic, err = strconv.ParseBool(value)
+		case policyRPSKey:
+			policy.RequestsPerSecond, err = strconv.ParseFloat(value, 64)
+			if err == nil && policy.RequestsPerSecond < 0 {
+				err = fmt.Errorf("should not be negative")
+			}
+		default:
+			return nil, fmt.Errorf("unknown field %q of the signing policy", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s of the signing policy: %w", key, err)
+		}
+	}
+
+	if policy.FpPk == "" {
+		return nil, fmt.Errorf("the signing policy should set %s to a public key or %s", policyFpKey, AnyKey)
+	}
+
+	return policy, nil
+}
+
+func parsePolicyFpPk(value string) (string, error) {
+	if value == AnyKey {
+		return value, nil
+	}
+
+	pkBytes, err := hex.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	if _, err := schnorr.ParsePubKey(pkBytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(pkBytes), nil
+}
+
+// ParseSigningPolicies parses the signing policies in the config
+func (cfg *Config) ParseSigningPolicies() ([]*SigningPolicy, error) {
+	policies := make([]*SigningPolicy, 0, len(cfg.SigningPolicy))
+	fpPks := make(map[string]struct{}, len(cfg.SigningPolicy))
+	for _, s := range cfg.SigningPolicy {
+		policy, err := ParseSigningPolicy(s)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := fpPks[policy.FpPk]; ok {
+			return nil, fmt.Errorf("duplicate signing policies of %s", policy.FpPk)
+		}
+		fpPks[policy.FpPk] = struct{}{}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// UnixSocketScheme is the prefix of the RPC addresses that are unix domain sockets,
+	// e.g., unix:///var/run/eotsd/eotsd.sock
+	UnixSocketScheme = "unix://"
+
+	defaultRpcSocketMode = 0600
+)
+
+// ParseRPCAddress returns the network and the address to listen on or dial of
+// the given RPC address, which is either a TCP address or the absolute path of
+// a unix domain socket prefixed by unix://
+func ParseRPCAddress(addr string) (network, address string, err error) {
+	if strings.HasPrefix(addr, UnixSocketScheme) {
+		socketPath := strings.TrimPrefix(addr, UnixSocketScheme)
+		if !filepath.IsAbs(socketPath) {
+			return "", "", fmt.Errorf("the path of the unix socket %s should be absolute", socketPath)
+		}
+		return "unix", filepath.Clean(socketPath), nil
+	}
+
+	if _, err := net.ResolveTCPAddr("tcp", addr); err != nil {
+		return "", "", err
+	}
+
+	return "tcp", addr, nil
+}
+
+// IsUnixSocket returns whether the RPC server listens on a unix domain socket
+func (cfg *Config) IsUnixSocket() bool {
+	return strings.HasPrefix(cfg.RpcListener, UnixSocketScheme)
+}
+
+// SocketMode returns the file mode of the unix domain socket of the RPC server,
+// which is only accessible by the owner if not set
+func (cfg *Config) SocketMode() (uint32, error) {
+	if cfg.RpcSocketMode == "" {
+		return defaultRpcSocketMode, nil
+	}
+	mode, err := strconv.ParseUint(cfg.RpcSocketMode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid socket mode %s, it should be octal permission bits like 0600", cfg.RpcSocketMode)
+	}
+
+	return uint32(mode), nil
+}
+
+func (cfg *Config) validateRPCListener() error {
+	if _, _, err := ParseRPCAddress(cfg.RpcListener); err != nil {
+		return fmt.Errorf("invalid RPC listener address %s, %w", cfg.RpcListener, err)
+	}
+
+	if !cfg.IsUnixSocket() {
+		if len(cfg.RpcSocketUIDs) != 0 {
+			return fmt.Errorf("the allowed UIDs of the peers can only be set with a unix socket RPC listener")
+		}
+		return nil
+	}
+
+	if _, err := cfg.SocketMode(); err != nil {
+		return err
+	}
+
+	if len(cfg.RpcSocketUIDs) != 0 && runtime.GOOS != "linux" {
+		return fmt.Errorf("checking the UIDs of the peers is not supported on %s", runtime.GOOS)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/babylonchain/finality-provider/util"
+)
+
+const (
+	defaultTLSDirname = "tls"
+	tlsCertOrg        = "eotsd autogenerated cert"
+	tlsCertValidity   = 10 * 365 * 24 * time.Hour
+
+	CACertFileName     = "ca.cert"
+	caKeyFileName      = "ca.key"
+	ServerCertFileName = "server.cert"
+	ServerKeyFileName  = "server.key"
+	ClientCertFileName = "client.cert"
+	ClientKeyFileName  = "client.key"
+)
+
+// TLSConfig is the TLS config of the eotsd RPC server
+type TLSConfig struct {
+	CertPath     string `long:"certpath" description:"Path to the TLS certificate of the RPC server; TLS is disabled if empty"`
+	KeyPath      string `long:"keypath" description:"Path to the TLS private key of the RPC server"`
+	ClientCAPath string `long:"clientcapath" description:"Path to the CA certificate to verify the client certificates with; clients are required to present a certificate issued by it if set"`
+}
+
+func DefaultTLSConfig() *TLSConfig {
+	return &TLSConfig{}
+}
+
+// Enabled returns whether the RPC server serves over TLS
+func (cfg *TLSConfig) Enabled() bool {
+	return cfg != nil && cfg.CertPath != ""
+}
+
+func (cfg *TLSConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+
+	if (cfg.CertPath == "") != (cfg.KeyPath == "") {
+		return fmt.Errorf("the TLS certificate and key should be set together")
+	}
+
+	if cfg.ClientCAPath != "" && cfg.CertPath == "" {
+		return fmt.Errorf("the client CA requires the TLS certificate and key to be set")
+	}
+
+	return nil
+}
+
+// ServerTLSConfig loads the certificates of the RPC server
+// Client certificates are required and verified if the client CA is set
+func (cfg *TLSConfig) ServerTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertPath, cfg.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS key pair: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAPath != "" {
+		clientCAs, err := loadCertPool(cfg.ClientCAPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.ClientCAs = clientCAs
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsCfg, nil
+}
+
+// NewClientTLSConfig loads the certificates to connect to the RPC server
+// nil is returned if the CA certificate is empty, which means TLS is disabled
+// The client certificate is optional unless the server verifies client certificates
+func NewClientTLSConfig(caCertPath, certPath, keyPath string) (*tls.Config, error) {
+	if caCertPath == "" {
+		if certPath != "" || keyPath != "" {
+			return nil, fmt.Errorf("the client certificate requires the CA certificate to be set")
+		}
+		return nil, nil
+	}
+
+	rootCAs, err := loadCertPool(caCertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg := &tls.Config{
+		RootCAs:    rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if (certPath == "") != (keyPath == "") {
+		return nil, fmt.Errorf("the client certificate and key should be set together")
+	}
+	if certPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client TLS key pair: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+func loadCertPool(certPath string) (*x509.CertPool, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(certPEM) {
+		return nil, fmt.Errorf("no valid certificate found in %s", certPath)
+	}
+
+	return pool, nil
+}
+
+func TLSDir(homePath string) string {
+	return filepath.Join(homePath, defaultTLSDirname)
+}
+
+// GenerateTLSCerts generates a self-signed CA, and a server and a client certificate
+// issued by it under the given directory
+// The server certificate is valid for localhost and the given extra hosts,
+// which can be either IP addresses or domain names
+func GenerateTLSCerts(tlsDir string, extraHosts []string) error {
+	if err := util.MakeDirectory(tlsDir); err != nil {
+		return err
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	caTemplate, err := certTemplate("eotsd CA")
+	if err != nil {
+		return err
+	}
+	caTemplate.IsCA = true
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageCRLSign
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to create the CA certificate: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return err
+	}
+	if err := writeCertAndKey(tlsDir, CACertFileName, caKeyFileName, caDER, caKey); err != nil {
+		return err
+	}
+
+	serverTemplate, err := certTemplate("eotsd")
+	if err != nil {
+		return err
+	}
+	serverTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	serverTemplate.DNSNames = []string{"localhost"}
+	serverTemplate.IPAddresses = []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback}
+	for _, host := range extraHosts {
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+	if err := issueCert(tlsDir, ServerCertFileName, ServerKeyFileName, serverTemplate, caCert, caKey); err != nil {
+		return fmt.Errorf("failed to create the server certificate: %w", err)
+	}
+
+	clientTemplate, err := certTemplate("eotsd client")
+	if err != nil {
+		return err
+	}
+	clientTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if err := issueCert(tlsDir, ClientCertFileName, ClientKeyFileName, clientTemplate, caCert, caKey); err != nil {
+		return fmt.Errorf("failed to create the client certificate: %w", err)
+	}
+
+	return nil
+}
+
+func certTemplate(commonName string) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{tlsCertOrg},
+			CommonName:   commonName,
+		},
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(tlsCertValidity),
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	}, nil
+}
+
+func issueCert(
+	tlsDir, certFileName, keyFileName string,
+	template, caCert *x509.Certificate,
+	caKey *ecdsa.PrivateKey,
+) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+
+	return writeCertAndKey(tlsDir, certFileName, keyFileName, certDER, key)
+}
+
+func writeCertAndKey(tlsDir, certFileName, keyFileName string, certDER []byte, key *ecdsa.PrivateKey) error {
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	if err := os.WriteFile(filepath.Join(tlsDir, certFileName), certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", certFileName, err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(filepath.Join(tlsDir, keyFileName), keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", keyFileName, err)
+	}
+
+	return nil
+}
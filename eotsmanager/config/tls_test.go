@@ -0,0 +1,87 @@
+package config_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+)
+
+// TestGenerateTLSCerts tests that the generated certificates authenticate
+// both the server and the client, and that a client without a certificate is rejected
+func TestGenerateTLSCerts(t *testing.T) {
+	tlsDir := config.TLSDir(t.TempDir())
+	err := config.GenerateTLSCerts(tlsDir, []string{"10.0.0.2", "eotsd.example.com"})
+	require.NoError(t, err)
+
+	serverCfg := &config.TLSConfig{
+		CertPath:     filepath.Join(tlsDir, config.ServerCertFileName),
+		KeyPath:      filepath.Join(tlsDir, config.ServerKeyFileName),
+		ClientCAPath: filepath.Join(tlsDir, config.CACertFileName),
+	}
+	require.NoError(t, serverCfg.Validate())
+	serverTLSCfg, err := serverCfg.ServerTLSConfig()
+	require.NoError(t, err)
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", serverTLSCfg)
+	require.NoError(t, err)
+	defer lis.Close()
+
+	handshakeErrs := make(chan error)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			err = conn.(*tls.Conn).Handshake()
+			conn.Close()
+			handshakeErrs <- err
+		}
+	}()
+
+	caCertPath := filepath.Join(tlsDir, config.CACertFileName)
+	clientTLSCfg, err := config.NewClientTLSConfig(
+		caCertPath,
+		filepath.Join(tlsDir, config.ClientCertFileName),
+		filepath.Join(tlsDir, config.ClientKeyFileName),
+	)
+	require.NoError(t, err)
+	clientTLSCfg.ServerName = "localhost"
+	require.NoError(t, dialAndHandshake(lis.Addr(), clientTLSCfg))
+	require.NoError(t, <-handshakeErrs)
+
+	// the server rejects a client without a certificate
+	noCertTLSCfg, err := config.NewClientTLSConfig(caCertPath, "", "")
+	require.NoError(t, err)
+	noCertTLSCfg.ServerName = "localhost"
+	_ = dialAndHandshake(lis.Addr(), noCertTLSCfg)
+	require.Error(t, <-handshakeErrs)
+
+	// TLS is disabled without the CA certificate
+	disabledTLSCfg, err := config.NewClientTLSConfig("", "", "")
+	require.NoError(t, err)
+	require.Nil(t, disabledTLSCfg)
+}
+
+func dialAndHandshake(addr net.Addr, tlsCfg *tls.Config) error {
+	conn, err := tls.Dial(addr.Network(), addr.String(), tlsCfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the server verifies the client certificate after the client
+	// has finished the handshake in TLS 1.3, so wait for it to close
+	_, err = conn.Read(make([]byte, 1))
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
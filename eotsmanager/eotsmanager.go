@@ -13,25 +13,98 @@ type EOTSManager interface {
 	// It fails if there is an existing key Info with the same name or public key.
 	CreateKey(name, passphrase, hdPath string) ([]byte, error)
 
+	// ImportMnemonic imports the EOTS key at the given name from the mnemonic, which is either
+	// the seed phrase that the key is derived from with hdPath, or the key mnemonic returned by
+	// ExportMnemonic if isKeyMnemonic is set
+	// It fails if there is an existing key Info with the same name or public key.
+	ImportMnemonic(name, passphrase, hdPath, mnemonic string, isKeyMnemonic bool) ([]byte, error)
+
+	// ExportMnemonic returns the key mnemonic of the EOTS key, which encodes its private key
+	// It fails if the finality provider does not exist or passPhrase is incorrect
+	// NOTE: the EOTS manager server refuses it unless the key export is allowed in its config
+	ExportMnemonic(uid []byte, passphrase string) (string, error)
+
+	// ListKeys returns the names and public keys of all the EOTS keys
+	ListKeys() ([]*types.KeyInfo, error)
+
+	// ShowKey returns the name and public key of the EOTS key, along with its master public
+	// randomness for the given chain if chainID is not empty
+	// It fails if the finality provider does not exist or passPhrase is incorrect
+	ShowKey(uid []byte, chainID []byte, passphrase string) (*types.KeyInfo, error)
+
+	// DeleteKey removes the EOTS key from the keyring, while its signing records are kept
+	// It fails if the finality provider does not exist or passPhrase is incorrect
+	DeleteKey(uid []byte, passphrase string) error
+
 	// CreateMasterRandPair generates a pair of master secret/public randomness
+	// that derive the randomness at each height with the given scheme, which is
+	// recorded for the EOTS key and chainID. An empty scheme refers to the recorded
+	// one, or keyring.RandSchemeUint32 if there is none
 	// It fails if the finality provider does not exist or passPhrase is incorrect
+	// or a different scheme has been recorded
 	// NOTE: the master randomness pair is deterministically generated based on the EOTS key and chainID
-	CreateMasterRandPair(uid []byte, chainID []byte, passphrase string) (string, error)
+	CreateMasterRandPair(uid []byte, chainID []byte, randScheme string, passphrase string) (string, error)
 
-	// KeyRecord returns the finality provider record
+	// KeyRecord returns the finality provider record, which contains the EOTS private key
 	// It fails if the finality provider does not exist or passPhrase is incorrect
+	// NOTE: the EOTS manager server refuses it unless the key export is allowed in its config
 	KeyRecord(uid []byte, passphrase string) (*types.KeyRecord, error)
 
 	// SignEOTS signs an EOTS using the private key of the finality provider and the corresponding
 	// secret randomness of the give chain at the given height
 	// It fails if the finality provider does not exist or there's no randomness committed to the given height
 	// or passPhrase is incorrect
+	// The signature is recorded before being returned. Signing the same message at the same height
+	// again returns the recorded signature, while signing a different one, or signing at or below
+	// the signed watermark without a record, fails with ErrDoubleSign
 	SignEOTS(uid []byte, chainID []byte, msg []byte, height uint64, passphrase string) (*btcec.ModNScalar, error)
 
+	// SignEOTSBatch signs a batch of EOTS over the given messages at their heights using the private key
+	// of the finality provider and the corresponding secret randomness of the given chain
+	// The key is unlocked and the master secret randomness is derived once for the whole batch, and the
+	// signatures are returned in the same order as the messages
+	// It fails if any of the messages cannot be signed by SignEOTS
+	SignEOTSBatch(uid []byte, chainID []byte, msgs []*types.HeightMsg, passphrase string) ([]*btcec.ModNScalar, error)
+
+	// SigningRecord returns the record of the EOTS signature made for the given chain at the given height
+	// It fails if no signature has been made at the height
+	SigningRecord(uid []byte, chainID []byte, height uint64) (*types.SigningRecord, error)
+
 	// SignSchnorrSig signs a Schnorr signature using the private key of the finality provider
 	// It fails if the finality provider does not exist or the message size is not 32 bytes
 	// or passPhrase is incorrect
 	SignSchnorrSig(uid []byte, msg []byte, passphrase string) (*schnorr.Signature, error)
 
+	// SignPoP signs the BTC part of the proof-of-possession that binds the EOTS key to the chain key,
+	// which is a Schnorr signature over the SHA-256 hash of chainSig, so that the EOTS private key
+	// never leaves the EOTS manager
+	// It fails if chainSig is not a signature of the chain key chainPk over the EOTS public key,
+	// or the finality provider does not exist or passPhrase is incorrect
+	SignPoP(uid []byte, chainPk []byte, chainSig []byte, passphrase string) (*schnorr.Signature, error)
+
+	// Unlock loads the private key of the finality provider from the keyring and keeps it
+	// in memory, so that the following requests can be served without the passphrase
+	// until the key is locked or has not been used for the configured TTL
+	// It fails if the finality provider does not exist or passPhrase is incorrect
+	Unlock(uid []byte, passphrase string) error
+
+	// Lock removes the unlocked private key of the finality provider from memory
+	// All the unlocked keys are removed if uid is empty
+	Lock(uid []byte) error
+
+	// Halt refuses all the signing requests with ErrSigningHalted until Resume is called
+	// The halt is persisted so that the signing stays halted across restarts
+	Halt(reason string) error
+
+	// Resume resumes the signing halted by Halt
+	// It fails with ErrResumeUnauthorized unless adminCredential matches the configured one,
+	// or the finality provider exists and passPhrase is correct if adminCredential is empty
+	Resume(uid []byte, passphrase, adminCredential string) error
+
+	// Backup writes an archive of the keyring records and the database, encrypted with
+	// the passphrase, to the given absolute path on the host of the EOTS manager
+	// It fails if the file already exists or the keyring backend does not keep its records in files
+	Backup(filePath, passphrase string) error
+
 	Close() error
 }
@@ -0,0 +1,205 @@
+package forensics
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/babylonchain/babylon/crypto/eots"
+	bbntypes "github.com/babylonchain/babylon/types"
+	ftypes "github.com/babylonchain/babylon/x/finality/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+const (
+	slashedEventType      = "EventSlashedFinalityProvider"
+	evidenceAttributeName = "evidence"
+)
+
+var (
+	// ErrNotEquivocation is returned if the two signatures are not over different blocks
+	ErrNotEquivocation = errors.New("the signatures are not over different blocks at the same height")
+	// ErrInvalidSignature is returned if a signature does not verify against the public randomness
+	ErrInvalidSignature = errors.New("invalid EOTS signature")
+	// ErrKeyMismatch is returned if the extracted secret key is not the one of the public key
+	ErrKeyMismatch = errors.New("the extracted secret key does not match the public key")
+	// ErrNoEvidence is returned if a transaction has not slashed any finality provider
+	ErrNoEvidence = errors.New("no slashing evidence found")
+)
+
+// Equivocation is a pair of EOTS signatures by the same key over different blocks
+// at the same height, both with the public randomness at that height
+type Equivocation struct {
+	FpPk             *bbntypes.BIP340PubKey
+	Height           uint64
+	PubRand          *eots.PublicRand
+	CanonicalAppHash []byte
+	CanonicalSig     *eots.Signature
+	ForkAppHash      []byte
+	ForkSig          *eots.Signature
+}
+
+// EquivocationJSON is the JSON form of an equivocation, in which the bytes are hex encoded
+// The public randomness and the signatures are the ones output by eotsd sign-eots
+type EquivocationJSON struct {
+	FpPkHex             string `json:"fp_pk_hex"`
+	Height              uint64 `json:"height"`
+	PubRandHex          string `json:"pub_rand_hex"`
+	CanonicalAppHashHex string `json:"canonical_app_hash_hex"`
+	CanonicalSigHex     string `json:"canonical_sig_hex"`
+	ForkAppHashHex      string `json:"fork_app_hash_hex"`
+	ForkSigHex          string `json:"fork_sig_hex"`
+}
+
+// ParseEquivocationJSON parses the equivocation from its JSON form
+func ParseEquivocationJSON(bz []byte) (*Equivocation, error) {
+	var ej EquivocationJSON
+	if err := json.Unmarshal(bz, &ej); err != nil {
+		return nil, fmt.Errorf("failed to decode the equivocation: %w", err)
+	}
+
+	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(ej.FpPkHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %w", ej.FpPkHex, err)
+	}
+	pubRandBytes, err := hex.DecodeString(ej.PubRandHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public randomness %s: %w", ej.PubRandHex, err)
+	}
+	pubRand, err := bbntypes.NewSchnorrPubRand(pubRandBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public randomness %s: %w", ej.PubRandHex, err)
+	}
+	canonicalAppHash, err := hex.DecodeString(ej.CanonicalAppHashHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid canonical app hash %s: %w", ej.CanonicalAppHashHex, err)
+	}
+	forkAppHash, err := hex.DecodeString(ej.ForkAppHashHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fork app hash %s: %w", ej.ForkAppHashHex, err)
+	}
+	canonicalSig, err := parseEOTSSig(ej.CanonicalSigHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid canonical signature: %w", err)
+	}
+	forkSig, err := parseEOTSSig(ej.ForkSigHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fork signature: %w", err)
+	}
+
+	return &Equivocation{
+		FpPk:             fpPk,
+		Height:           ej.Height,
+		PubRand:          pubRand.ToFieldVal(),
+		CanonicalAppHash: canonicalAppHash,
+		CanonicalSig:     canonicalSig.ToModNScalar(),
+		ForkAppHash:      forkAppHash,
+		ForkSig:          forkSig.ToModNScalar(),
+	}, nil
+}
+
+func parseEOTSSig(sigHex string) (*bbntypes.SchnorrEOTSSig, error) {
+	sigBytes, err := hex.DecodeString(sigHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return bbntypes.NewSchnorrEOTSSig(sigBytes)
+}
+
+// NewEquivocationFromEvidence returns the equivocation of the slashing evidence recorded by Babylon
+func NewEquivocationFromEvidence(evidence *ftypes.Evidence) (*Equivocation, error) {
+	if evidence.FpBtcPk == nil || evidence.PubRand == nil ||
+		evidence.CanonicalFinalitySig == nil || evidence.ForkFinalitySig == nil {
+		return nil, fmt.Errorf("incomplete slashing evidence")
+	}
+
+	return &Equivocation{
+		FpPk:             evidence.FpBtcPk,
+		Height:           evidence.BlockHeight,
+		PubRand:          evidence.PubRand.ToFieldVal(),
+		CanonicalAppHash: evidence.CanonicalAppHash,
+		CanonicalSig:     evidence.CanonicalFinalitySig.ToModNScalar(),
+		ForkAppHash:      evidence.ForkAppHash,
+		ForkSig:          evidence.ForkFinalitySig.ToModNScalar(),
+	}, nil
+}
+
+// EvidenceFromEvents returns the slashing evidence in the events of a Babylon transaction
+func EvidenceFromEvents(events []abci.Event) (*ftypes.Evidence, error) {
+	for _, ev := range events {
+		if !strings.Contains(ev.Type, slashedEventType) {
+			continue
+		}
+		for _, attr := range ev.Attributes {
+			if attr.Key != evidenceAttributeName {
+				continue
+			}
+			var evidence ftypes.Evidence
+			if err := jsonpb.UnmarshalString(attr.Value, &evidence); err != nil {
+				return nil, fmt.Errorf("failed to decode the slashing evidence: %w", err)
+			}
+			return &evidence, nil
+		}
+	}
+
+	return nil, ErrNoEvidence
+}
+
+// Verify checks that the two signatures are over different blocks and that
+// both of them verify against the public key and the public randomness
+func (e *Equivocation) Verify() error {
+	if bytes.Equal(e.CanonicalAppHash, e.ForkAppHash) {
+		return ErrNotEquivocation
+	}
+
+	pk := e.FpPk.MustToBTCPK()
+	if err := eots.Verify(pk, e.PubRand, e.msgToSign(e.CanonicalAppHash), e.CanonicalSig); err != nil {
+		return fmt.Errorf("%w: the canonical signature: %v", ErrInvalidSignature, err)
+	}
+	if err := eots.Verify(pk, e.PubRand, e.msgToSign(e.ForkAppHash), e.ForkSig); err != nil {
+		return fmt.Errorf("%w: the fork signature: %v", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
+
+// ExtractSK verifies the equivocation and extracts the secret key from it,
+// which is confirmed to be the one of the public key
+func (e *Equivocation) ExtractSK() (*btcec.PrivateKey, error) {
+	if err := e.Verify(); err != nil {
+		return nil, err
+	}
+
+	sk, err := eots.Extract(
+		e.FpPk.MustToBTCPK(), e.PubRand,
+		e.msgToSign(e.CanonicalAppHash), e.CanonicalSig,
+		e.msgToSign(e.ForkAppHash), e.ForkSig,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the secret key: %w", err)
+	}
+
+	if !bytes.Equal(schnorr.SerializePubKey(sk.PubKey()), e.FpPk.MustMarshal()) {
+		return nil, ErrKeyMismatch
+	}
+
+	return sk, nil
+}
+
+// msgToSign builds the message of the finality signature over the block with the given app hash
+func (e *Equivocation) msgToSign(appHash []byte) []byte {
+	msg := &ftypes.MsgAddFinalitySig{
+		FpBtcPk:      e.FpPk,
+		BlockHeight:  e.Height,
+		BlockAppHash: appHash,
+	}
+
+	return msg.MsgToSign()
+}
@@ -0,0 +1,105 @@
+package forensics_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/babylonchain/babylon/crypto/eots"
+	bbntypes "github.com/babylonchain/babylon/types"
+	ftypes "github.com/babylonchain/babylon/x/finality/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/forensics"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzExtractSK tests that the secret key is extracted from a verified equivocation only
+func FuzzExtractSK(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		sk, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		fpPk := bbntypes.NewBIP340PubKeyFromBTCPK(sk.PubKey())
+		height := uint64(r.Int31n(1000) + 1)
+		msr, _, err := fpkeyring.GenerateMasterRandPair(sk.Serialize(), []byte("chain-test"))
+		require.NoError(t, err)
+		privRand, pubRand, err := msr.DeriveRandPair(uint32(height))
+		require.NoError(t, err)
+
+		sign := func(appHash []byte) *eots.Signature {
+			msg := &ftypes.MsgAddFinalitySig{FpBtcPk: fpPk, BlockHeight: height, BlockAppHash: appHash}
+			sig, err := eots.Sign(sk, privRand, msg.MsgToSign())
+			require.NoError(t, err)
+			return sig
+		}
+		canonicalAppHash := testutil.GenRandomByteArray(r, 32)
+		forkAppHash := testutil.GenRandomByteArray(r, 32)
+
+		pubRandBytes := pubRand.Bytes()
+		ej := &forensics.EquivocationJSON{
+			FpPkHex:             fpPk.MarshalHex(),
+			Height:              height,
+			PubRandHex:          hex.EncodeToString(pubRandBytes[:]),
+			CanonicalAppHashHex: hex.EncodeToString(canonicalAppHash),
+			CanonicalSigHex:     hex.EncodeToString(bbntypes.NewSchnorrEOTSSigFromModNScalar(sign(canonicalAppHash)).MustMarshal()),
+			ForkAppHashHex:      hex.EncodeToString(forkAppHash),
+			ForkSigHex:          hex.EncodeToString(bbntypes.NewSchnorrEOTSSigFromModNScalar(sign(forkAppHash)).MustMarshal()),
+		}
+		bz, err := json.Marshal(ej)
+		require.NoError(t, err)
+		equivocation, err := forensics.ParseEquivocationJSON(bz)
+		require.NoError(t, err)
+
+		extractedSK, err := equivocation.ExtractSK()
+		require.NoError(t, err)
+		requireSameKey(t, sk, extractedSK)
+
+		// the same equivocation recorded as a slashing evidence
+		evidence := &ftypes.Evidence{
+			FpBtcPk:              fpPk,
+			BlockHeight:          height,
+			PubRand:              bbntypes.NewSchnorrPubRandFromFieldVal(pubRand),
+			CanonicalAppHash:     canonicalAppHash,
+			ForkAppHash:          forkAppHash,
+			CanonicalFinalitySig: bbntypes.NewSchnorrEOTSSigFromModNScalar(equivocation.CanonicalSig),
+			ForkFinalitySig:      bbntypes.NewSchnorrEOTSSigFromModNScalar(equivocation.ForkSig),
+		}
+		fromEvidence, err := forensics.NewEquivocationFromEvidence(evidence)
+		require.NoError(t, err)
+		extractedSK, err = fromEvidence.ExtractSK()
+		require.NoError(t, err)
+		requireSameKey(t, sk, extractedSK)
+
+		// a signature over another block is not verified
+		equivocation.ForkAppHash = testutil.GenRandomByteArray(r, 32)
+		_, err = equivocation.ExtractSK()
+		require.ErrorIs(t, err, forensics.ErrInvalidSignature)
+
+		// two signatures over the same block are not an equivocation
+		equivocation.ForkAppHash = equivocation.CanonicalAppHash
+		equivocation.ForkSig = equivocation.CanonicalSig
+		_, err = equivocation.ExtractSK()
+		require.ErrorIs(t, err, forensics.ErrNotEquivocation)
+	})
+}
+
+// requireSameKey checks that the keys are the same up to their sign, which BIP-340
+// public keys do not tell apart
+func requireSameKey(t *testing.T, expected, actual *btcec.PrivateKey) {
+	expectedKey := new(btcec.ModNScalar).Set(&expected.Key)
+	if !expectedKey.Equals(&actual.Key) {
+		expectedKey.Negate()
+	}
+	require.True(t, expectedKey.Equals(&actual.Key))
+}
+
+func TestEvidenceFromEventsWithoutSlashing(t *testing.T) {
+	_, err := forensics.EvidenceFromEvents(nil)
+	require.ErrorIs(t, err, forensics.ErrNoEvidence)
+}
@@ -0,0 +1,121 @@
+package eotsmanager
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"go.uber.org/zap"
+
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+// SetAdminCredentialHash sets the SHA-256 hash of the admin credential
+// that can resume the signing without the passphrase of a key
+// NOTE: it should be called before the manager starts serving requests
+func (lm *LocalEOTSManager) SetAdminCredentialHash(hash []byte) {
+	lm.adminCredHash = hash
+}
+
+// Halt refuses all the signing requests until Resume is called
+// The halt is persisted so that it survives restarts
+func (lm *LocalEOTSManager) Halt(reason string) error {
+	state := &eotstypes.HaltState{
+		Reason:   reason,
+		HaltedAt: time.Now().Unix(),
+	}
+	// stop signing in memory first, so that the requests are refused
+	// even if the halt state fails to be persisted
+	lm.haltState.CompareAndSwap(nil, state)
+
+	if err := lm.es.SetHaltState(state); err != nil {
+		return fmt.Errorf("failed to persist the halt state: %w", err)
+	}
+
+	lm.logger.Warn("halted the signing", zap.String("reason", reason))
+
+	return nil
+}
+
+// Resume resumes the signing halted by Halt, which is authorized by either
+// the passphrase of an EOTS key or the admin credential
+// The passphrase is only accepted with the file keyring backend, as the other
+// backends do not verify it
+func (lm *LocalEOTSManager) Resume(fpPk []byte, passphrase, adminCredential string) error {
+	if err := lm.authorizeResume(fpPk, passphrase, adminCredential); err != nil {
+		return err
+	}
+
+	if err := lm.es.ClearHaltState(); err != nil {
+		return fmt.Errorf("failed to clear the halt state: %w", err)
+	}
+	lm.haltState.Store(nil)
+
+	lm.logger.Info("resumed the signing")
+
+	return nil
+}
+
+func (lm *LocalEOTSManager) authorizeResume(fpPk []byte, passphrase, adminCredential string) error {
+	if adminCredential != "" {
+		if len(lm.adminCredHash) == 0 {
+			return fmt.Errorf("%w: the admin credential is not configured", eotstypes.ErrResumeUnauthorized)
+		}
+		credHash := sha256.Sum256([]byte(adminCredential))
+		if subtle.ConstantTimeCompare(credHash[:], lm.adminCredHash) != 1 {
+			return fmt.Errorf("%w: invalid admin credential", eotstypes.ErrResumeUnauthorized)
+		}
+		return nil
+	}
+
+	if len(fpPk) == 0 {
+		return eotstypes.ErrResumeUnauthorized
+	}
+	if err := lm.verifyPassphrase(fpPk, passphrase); err != nil {
+		return fmt.Errorf("%w: %v", eotstypes.ErrResumeUnauthorized, err)
+	}
+
+	return nil
+}
+
+// verifyPassphrase checks the passphrase of the EOTS key against the keyring
+// Only the file backend encrypts the keys with the passphrase, and it caches the
+// passphrase once unlocked, so the key is read through a new keyring that has to
+// be unlocked with the given passphrase. The other backends return the keys with
+// any passphrase, so that they cannot verify it
+func (lm *LocalEOTSManager) verifyPassphrase(fpPk []byte, passphrase string) error {
+	if lm.keyringBackend != keyring.BackendFile {
+		return fmt.Errorf("the %s keyring backend does not verify the passphrase, the admin credential is required",
+			lm.keyringBackend)
+	}
+
+	keyName, err := lm.es.GetEOTSKeyName(fpPk)
+	if err != nil {
+		return err
+	}
+	kr, err := initKeyring(lm.homeDir, lm.keyringBackend, strings.NewReader(passphrase))
+	if err != nil {
+		return err
+	}
+	_, err = kr.Key(keyName)
+
+	return err
+}
+
+// HaltState returns the state of the halted signing, or nil if the signing is not halted
+func (lm *LocalEOTSManager) HaltState() *eotstypes.HaltState {
+	return lm.haltState.Load()
+}
+
+// checkHalted returns ErrSigningHalted if the signing is halted
+func (lm *LocalEOTSManager) checkHalted() error {
+	if state := lm.haltState.Load(); state != nil {
+		return fmt.Errorf("%w since %s: %s", eotstypes.ErrSigningHalted,
+			time.Unix(state.HaltedAt, 0).UTC().Format(time.RFC3339), state.Reason)
+	}
+
+	return nil
+}
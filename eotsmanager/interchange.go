@@ -0,0 +1,206 @@
+package eotsmanager
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+// InterchangeFormatVersion is the version of the signing history interchange format
+// it should be bumped whenever the format changes in a non-backward compatible way
+const InterchangeFormatVersion = 1
+
+// SigningHistoryInterchange is the interchange format of the signing history of
+// EOTS keys, which allows the slashing protection data to travel together with the
+// keys when a finality provider is moved between machines. It is similar in spirit
+// to EIP-3076 for Ethereum validators.
+type SigningHistoryInterchange struct {
+	Metadata InterchangeMetadata     `json:"metadata"`
+	Data     []*InterchangeKeyRecord `json:"data"`
+}
+
+type InterchangeMetadata struct {
+	InterchangeFormatVersion uint32 `json:"interchange_format_version,string"`
+	ExportedAt               string `json:"exported_at,omitempty"`
+}
+
+// InterchangeKeyRecord is the signing history of an EOTS key
+type InterchangeKeyRecord struct {
+	FpPkHex string                    `json:"fp_pk_hex"`
+	Chains  []*InterchangeChainRecord `json:"chains"`
+}
+
+// InterchangeChainRecord is the signing history of an EOTS key on a consumer chain
+type InterchangeChainRecord struct {
+	ChainID string `json:"chain_id"`
+	// SignedWatermark is the highest height that has been signed
+	SignedWatermark uint64                     `json:"signed_watermark,string"`
+	SignedHeights   []*InterchangeSignedHeight `json:"signed_heights"`
+}
+
+type InterchangeSignedHeight struct {
+	Height uint64 `json:"height,string"`
+	// MsgHashHex is the hex of the SHA-256 hash of the signed message
+	MsgHashHex string `json:"msg_hash_hex"`
+	// SigHex is the hex of the EOTS signature, which is optional
+	SigHex string `json:"sig_hex,omitempty"`
+}
+
+// ExportSigningHistory exports the signing history of all the EOTS keys
+func (lm *LocalEOTSManager) ExportSigningHistory() (*SigningHistoryInterchange, error) {
+	histories, err := lm.es.GetAllSigningHistories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the signing histories: %w", err)
+	}
+
+	return NewSigningHistoryInterchange(histories), nil
+}
+
+// ImportSigningHistory merges the given signing history with the existing one
+// The import is rejected if the interchange is invalid, any of the keys is not
+// in the local keyring, or any record conflicts with the existing one
+func (lm *LocalEOTSManager) ImportSigningHistory(interchange *SigningHistoryInterchange, passphrase string) error {
+	histories, err := interchange.ToSigningHistories()
+	if err != nil {
+		return fmt.Errorf("invalid signing history interchange: %w", err)
+	}
+
+	for _, h := range histories {
+		if err := lm.checkKeyInKeyring(h.FpPk, passphrase); err != nil {
+			return err
+		}
+	}
+
+	if err := lm.es.MergeSigningHistories(histories); err != nil {
+		return fmt.Errorf("failed to merge the signing histories: %w", err)
+	}
+
+	return nil
+}
+
+// checkKeyInKeyring checks that the EOTS key of the given public key
+// is in the local keyring
+func (lm *LocalEOTSManager) checkKeyInKeyring(fpPk []byte, passphrase string) error {
+	keyName, err := lm.es.GetEOTSKeyName(fpPk)
+	if err != nil {
+		return fmt.Errorf("the key %s is not in the local keyring: %w", hex.EncodeToString(fpPk), err)
+	}
+
+	lm.input.Reset(passphrase)
+	k, err := lm.kr.Key(keyName)
+	if err != nil {
+		return fmt.Errorf("the key %s is not in the local keyring: %w", hex.EncodeToString(fpPk), err)
+	}
+
+	eotsPk, err := loadBIP340PubKeyFromKeyringRecord(k)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(*eotsPk, fpPk) {
+		return fmt.Errorf("the key %s in the local keyring does not match the public key %s",
+			keyName, hex.EncodeToString(fpPk))
+	}
+
+	return nil
+}
+
+// NewSigningHistoryInterchange converts the signing histories into the interchange format
+func NewSigningHistoryInterchange(histories []*types.SigningHistory) *SigningHistoryInterchange {
+	interchange := &SigningHistoryInterchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			ExportedAt:               time.Now().UTC().Format(time.RFC3339),
+		},
+		Data: []*InterchangeKeyRecord{},
+	}
+
+	keyRecords := make(map[string]*InterchangeKeyRecord)
+	for _, h := range histories {
+		fpPkHex := hex.EncodeToString(h.FpPk)
+		keyRecord, ok := keyRecords[fpPkHex]
+		if !ok {
+			keyRecord = &InterchangeKeyRecord{FpPkHex: fpPkHex}
+			keyRecords[fpPkHex] = keyRecord
+			interchange.Data = append(interchange.Data, keyRecord)
+		}
+
+		chainRecord := &InterchangeChainRecord{
+			ChainID:         string(h.ChainID),
+			SignedWatermark: h.SignedWatermark,
+			SignedHeights:   make([]*InterchangeSignedHeight, 0, len(h.Records)),
+		}
+		for _, r := range h.Records {
+			chainRecord.SignedHeights = append(chainRecord.SignedHeights, &InterchangeSignedHeight{
+				Height:     r.Height,
+				MsgHashHex: hex.EncodeToString(r.MsgHash),
+				SigHex:     hex.EncodeToString(r.Sig),
+			})
+		}
+		keyRecord.Chains = append(keyRecord.Chains, chainRecord)
+	}
+
+	return interchange
+}
+
+// ToSigningHistories validates the interchange and converts it into signing histories
+func (sh *SigningHistoryInterchange) ToSigningHistories() ([]*types.SigningHistory, error) {
+	if sh.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return nil, fmt.Errorf("unsupported interchange format version %d, expected %d",
+			sh.Metadata.InterchangeFormatVersion, InterchangeFormatVersion)
+	}
+
+	var histories []*types.SigningHistory
+	for _, keyRecord := range sh.Data {
+		fpPk, err := hex.DecodeString(keyRecord.FpPkHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", keyRecord.FpPkHex, err)
+		}
+
+		for _, chainRecord := range keyRecord.Chains {
+			if chainRecord.ChainID == "" {
+				return nil, fmt.Errorf("empty chain ID for the key %s", keyRecord.FpPkHex)
+			}
+
+			history := &types.SigningHistory{
+				FpPk:            fpPk,
+				ChainID:         []byte(chainRecord.ChainID),
+				SignedWatermark: chainRecord.SignedWatermark,
+			}
+
+			signedHeights := make(map[uint64]struct{})
+			for _, signed := range chainRecord.SignedHeights {
+				if _, ok := signedHeights[signed.Height]; ok {
+					return nil, fmt.Errorf("duplicate height %d of chain %s for the key %s",
+						signed.Height, chainRecord.ChainID, keyRecord.FpPkHex)
+				}
+				signedHeights[signed.Height] = struct{}{}
+
+				msgHash, err := hex.DecodeString(signed.MsgHashHex)
+				if err != nil || len(msgHash) != 32 {
+					return nil, fmt.Errorf("invalid message hash at height %d of chain %s for the key %s",
+						signed.Height, chainRecord.ChainID, keyRecord.FpPkHex)
+				}
+				sig, err := hex.DecodeString(signed.SigHex)
+				if err != nil || (len(sig) != 0 && len(sig) != 32) {
+					return nil, fmt.Errorf("invalid signature at height %d of chain %s for the key %s",
+						signed.Height, chainRecord.ChainID, keyRecord.FpPkHex)
+				}
+
+				history.Records = append(history.Records, &types.SigningRecord{
+					FpPk:    fpPk,
+					ChainID: history.ChainID,
+					Height:  signed.Height,
+					MsgHash: msgHash,
+					Sig:     sig,
+				})
+			}
+
+			histories = append(histories, history)
+		}
+	}
+
+	return histories, nil
+}
@@ -0,0 +1,112 @@
+package eotsmanager_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/babylonchain/babylon/testutil/datagen"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	eotscfg "github.com/babylonchain/finality-provider/eotsmanager/config"
+	"github.com/babylonchain/finality-provider/eotsmanager/store"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzSigningHistoryInterchange tests exporting the signing history from
+// an EOTS manager and importing it to another one with the same key
+func FuzzSigningHistoryInterchange(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		mnemonic, err := eotsmanager.NewMnemonic()
+		require.NoError(t, err)
+		fpName := testutil.GenRandomHexStr(r, 4)
+
+		srcLm := newLocalEOTSManager(t, filepath.Join(t.TempDir(), "src-eots-home"))
+		fpPk, err := srcLm.CreateKeyWithMnemonic(fpName, passphrase, hdPath, mnemonic)
+		require.NoError(t, err)
+
+		chainID := []byte(testutil.GenRandomHexStr(r, 10))
+		startHeight := datagen.RandomInt(r, 100) + 1
+		num := r.Intn(10) + 1
+		msgs := make([][]byte, num)
+		for i := 0; i < num; i++ {
+			msgs[i] = datagen.GenRandomByteArray(r, 32)
+			_, err := srcLm.SignEOTS(*fpPk, chainID, msgs[i], startHeight+uint64(i), passphrase)
+			require.NoError(t, err)
+		}
+		lastHeight := startHeight + uint64(num) - 1
+
+		interchange, err := srcLm.ExportSigningHistory()
+		require.NoError(t, err)
+		require.Len(t, interchange.Data, 1)
+		require.Equal(t, lastHeight, interchange.Data[0].Chains[0].SignedWatermark)
+
+		// the interchange travels as a JSON file
+		interchangeBytes, err := json.Marshal(interchange)
+		require.NoError(t, err)
+		var decoded eotsmanager.SigningHistoryInterchange
+		err = json.Unmarshal(interchangeBytes, &decoded)
+		require.NoError(t, err)
+
+		// the import is rejected if the key is not in the local keyring
+		otherLm := newLocalEOTSManager(t, filepath.Join(t.TempDir(), "other-eots-home"))
+		err = otherLm.ImportSigningHistory(&decoded, passphrase)
+		require.ErrorIs(t, err, store.ErrEOTSKeyNameNotFound)
+
+		dstLm := newLocalEOTSManager(t, filepath.Join(t.TempDir(), "dst-eots-home"))
+		_, err = dstLm.CreateKeyWithMnemonic(fpName, passphrase, hdPath, mnemonic)
+		require.NoError(t, err)
+		// a higher height has been signed on the destination
+		_, err = dstLm.SignEOTS(*fpPk, chainID, datagen.GenRandomByteArray(r, 32), lastHeight+1, passphrase)
+		require.NoError(t, err)
+
+		err = dstLm.ImportSigningHistory(&decoded, passphrase)
+		require.NoError(t, err)
+
+		// the imported heights are protected
+		for i := 0; i < num; i++ {
+			height := startHeight + uint64(i)
+			_, err := dstLm.SignEOTS(*fpPk, chainID, datagen.GenRandomByteArray(r, 32), height, passphrase)
+			require.ErrorIs(t, err, types.ErrDoubleSign)
+			_, err = dstLm.SignEOTS(*fpPk, chainID, msgs[i], height, passphrase)
+			require.NoError(t, err)
+		}
+
+		// the watermark is not lowered by the import
+		exported, err := dstLm.ExportSigningHistory()
+		require.NoError(t, err)
+		require.Equal(t, lastHeight+1, exported.Data[0].Chains[0].SignedWatermark)
+		require.Len(t, exported.Data[0].Chains[0].SignedHeights, num+1)
+
+		// the import is rejected if any record conflicts with the existing one
+		decoded.Data[0].Chains[0].SignedHeights[0].MsgHashHex = testutil.GenRandomHexStr(r, 32)
+		err = dstLm.ImportSigningHistory(&decoded, passphrase)
+		require.ErrorIs(t, err, store.ErrConflictingSigningRecord)
+
+		// the import is rejected if the version is not supported
+		decoded.Metadata.InterchangeFormatVersion = eotsmanager.InterchangeFormatVersion + 1
+		err = dstLm.ImportSigningHistory(&decoded, passphrase)
+		require.Error(t, err)
+	})
+}
+
+func newLocalEOTSManager(t *testing.T, homeDir string) *eotsmanager.LocalEOTSManager {
+	eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+	dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		dbBackend.Close()
+	})
+
+	lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+	require.NoError(t, err)
+
+	return lm
+}
@@ -0,0 +1,389 @@
+package eotsmanager
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/babylonchain/babylon/crypto/eots"
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
+)
+
+// keyCache keeps the unlocked EOTS private keys and the master secret randomness
+// derived from them in memory, so that signing does not need to decrypt the
+// keyring record and derive the master randomness again for every request
+// An entry that has not been used for the TTL is evicted, and its private key
+// and master secret randomness are zeroized, except for the master randomness of
+// RandSchemeUint32, which can only be dropped
+// (see uint32MasterSecretRand.Zero in the keyring package)
+// It also keeps a window of the randomness pairs derived in the background
+// ahead of the last signed height of each key and chain, so that signing does
+// not derive the secret randomness on the critical path
+type keyCache struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	keys map[string]*unlockedKey
+	// windowSize is the number of heights in each randomness window, 0 disables the windows
+	windowSize uint64
+	// refill wakes up the precomputation loop once a window has moved
+	refill chan struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+type unlockedKey struct {
+	privKey *btcec.PrivateKey
+	// chain ID -> master secret randomness
+	masterRands map[string]fpkeyring.MasterSecretRand
+	// chain ID -> randomness window
+	randWindows map[string]*randWindow
+	lastUsed    time.Time
+}
+
+// randWindow holds the randomness pairs at the heights in [start, start+windowSize)
+// that have been derived so far
+type randWindow struct {
+	start uint64
+	pairs map[uint64]*randPair
+}
+
+type randPair struct {
+	secretRand *eots.PrivateRand
+	pubRand    *eots.PublicRand
+}
+
+func newKeyCache(ttl time.Duration, windowSize uint32) *keyCache {
+	kc := &keyCache{
+		ttl:        ttl,
+		keys:       make(map[string]*unlockedKey),
+		windowSize: uint64(windowSize),
+		refill:     make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}
+
+	kc.wg.Add(1)
+	go kc.evictionLoop()
+
+	if kc.windowSize > 0 {
+		kc.wg.Add(1)
+		go kc.precomputeLoop()
+	}
+
+	return kc
+}
+
+// evictionLoop periodically evicts the entries that have expired
+// so that they do not stay in memory until the next access
+func (kc *keyCache) evictionLoop() {
+	defer kc.wg.Done()
+
+	ticker := time.NewTicker(kc.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			kc.evictExpired()
+		case <-kc.quit:
+			return
+		}
+	}
+}
+
+func (kc *keyCache) evictExpired() {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range kc.keys {
+		if now.Sub(entry.lastUsed) >= kc.ttl {
+			kc.evict(k, entry)
+		}
+	}
+}
+
+// evict zeroizes the private key and the master secret randomness of the entry
+// and removes it from the cache
+// NOTE: the caller must hold the lock
+func (kc *keyCache) evict(k string, entry *unlockedKey) {
+	entry.privKey.Zero()
+	for chainID, msr := range entry.masterRands {
+		msr.Zero()
+		delete(entry.masterRands, chainID)
+	}
+	for chainID, w := range entry.randWindows {
+		for height, pair := range w.pairs {
+			pair.secretRand.Zero()
+			delete(w.pairs, height)
+		}
+		delete(entry.randWindows, chainID)
+	}
+	delete(kc.keys, k)
+}
+
+// get returns the entry of the given key if it has not expired
+// NOTE: the caller must hold the lock
+func (kc *keyCache) get(fpPk []byte) *unlockedKey {
+	k := hex.EncodeToString(fpPk)
+	entry, ok := kc.keys[k]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	if now.Sub(entry.lastUsed) >= kc.ttl {
+		kc.evict(k, entry)
+		return nil
+	}
+	entry.lastUsed = now
+
+	return entry
+}
+
+// privKey returns a copy of the cached private key, or nil if the key is not unlocked
+// A copy is returned so that evicting the entry does not affect the signing in progress
+func (kc *keyCache) privKey(fpPk []byte) *btcec.PrivateKey {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	entry := kc.get(fpPk)
+	if entry == nil {
+		return nil
+	}
+	privKey := *entry.privKey
+
+	return &privKey
+}
+
+// masterRand returns a copy of the cached master secret randomness of the given key
+// and chain, or nil if it is not cached
+// A copy is returned so that evicting the entry does not affect the signing in progress,
+// and the caller should zeroize it once done
+func (kc *keyCache) masterRand(fpPk []byte, chainID []byte) fpkeyring.MasterSecretRand {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	entry := kc.get(fpPk)
+	if entry == nil {
+		return nil
+	}
+	msr, ok := entry.masterRands[string(chainID)]
+	if !ok {
+		return nil
+	}
+
+	return msr.Clone()
+}
+
+// addPrivKey caches a copy of the given private key
+func (kc *keyCache) addPrivKey(fpPk []byte, privKey *btcec.PrivateKey) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	k := hex.EncodeToString(fpPk)
+	if entry, ok := kc.keys[k]; ok {
+		entry.lastUsed = time.Now()
+		return
+	}
+
+	privKeyCopy := *privKey
+	kc.keys[k] = &unlockedKey{
+		privKey:     &privKeyCopy,
+		masterRands: make(map[string]fpkeyring.MasterSecretRand),
+		randWindows: make(map[string]*randWindow),
+		lastUsed:    time.Now(),
+	}
+}
+
+// addMasterRand caches the master secret randomness of the given chain
+// The cache takes over the master secret randomness and zeroizes it once the key
+// is evicted, so the caller should cache a copy of the one it keeps using
+// It is zeroized right away if the key is not unlocked
+func (kc *keyCache) addMasterRand(fpPk []byte, chainID []byte, msr fpkeyring.MasterSecretRand) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	entry := kc.get(fpPk)
+	if entry == nil {
+		msr.Zero()
+		return
+	}
+	if existing, ok := entry.masterRands[string(chainID)]; ok {
+		existing.Zero()
+	}
+	entry.masterRands[string(chainID)] = msr
+}
+
+// takeRandPair removes the randomness pair at the given height from the window
+// of the given key and chain and returns it, or returns false if it has not been derived
+func (kc *keyCache) takeRandPair(fpPk []byte, chainID []byte, height uint64) (*eots.PrivateRand, *eots.PublicRand, bool) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	entry := kc.get(fpPk)
+	if entry == nil {
+		return nil, nil, false
+	}
+	w, ok := entry.randWindows[string(chainID)]
+	if !ok {
+		return nil, nil, false
+	}
+	pair, ok := w.pairs[height]
+	if !ok {
+		return nil, nil, false
+	}
+	delete(w.pairs, height)
+
+	return pair.secretRand, pair.pubRand, true
+}
+
+// advanceRandWindow moves the window of the given key and chain to start after
+// the given signed height, and wakes up the precomputation loop to fill it
+// It is a no-op if the windows are disabled or the key is not unlocked
+func (kc *keyCache) advanceRandWindow(fpPk []byte, chainID []byte, signedHeight uint64) {
+	if kc.windowSize == 0 {
+		return
+	}
+
+	kc.mu.Lock()
+	entry := kc.get(fpPk)
+	if entry == nil {
+		kc.mu.Unlock()
+		return
+	}
+	w, ok := entry.randWindows[string(chainID)]
+	if !ok {
+		w = &randWindow{pairs: make(map[uint64]*randPair)}
+		entry.randWindows[string(chainID)] = w
+	}
+	if signedHeight >= w.start {
+		w.start = signedHeight + 1
+		for height, pair := range w.pairs {
+			if height < w.start {
+				pair.secretRand.Zero()
+				delete(w.pairs, height)
+			}
+		}
+	}
+	kc.mu.Unlock()
+
+	select {
+	case kc.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (kc *keyCache) precomputeLoop() {
+	defer kc.wg.Done()
+
+	for {
+		select {
+		case <-kc.refill:
+			kc.precompute()
+		case <-kc.quit:
+			return
+		}
+	}
+}
+
+// precomputeTask is the missing heights of a window to derive the randomness at
+type precomputeTask struct {
+	key     string
+	chainID string
+	window  *randWindow
+	msr     fpkeyring.MasterSecretRand
+	heights []uint64
+}
+
+// precompute fills the windows with the randomness pairs at their missing heights
+// The pairs are derived without holding the lock, and are dropped if the key has
+// been evicted or the window has moved past them in the meantime
+func (kc *keyCache) precompute() {
+	var tasks []*precomputeTask
+	kc.mu.Lock()
+	for k, entry := range kc.keys {
+		for chainID, w := range entry.randWindows {
+			msr, ok := entry.masterRands[chainID]
+			if !ok {
+				continue
+			}
+			task := &precomputeTask{key: k, chainID: chainID, window: w}
+			for height := w.start; height < w.start+kc.windowSize; height++ {
+				if _, ok := w.pairs[height]; !ok {
+					task.heights = append(task.heights, height)
+				}
+			}
+			if len(task.heights) != 0 {
+				// a copy is used so that evicting the key does not affect the derivation
+				task.msr = msr.Clone()
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	kc.mu.Unlock()
+
+	defer func() {
+		for _, task := range tasks {
+			task.msr.Zero()
+		}
+	}()
+
+	for _, task := range tasks {
+		pairs := make(map[uint64]*randPair, len(task.heights))
+		for _, height := range task.heights {
+			sr, pr, err := task.msr.DeriveRandPair(height)
+			if err != nil {
+				// the heights beyond the scheme are derived on signing, which reports the error
+				break
+			}
+			pairs[height] = &randPair{secretRand: sr, pubRand: pr}
+		}
+
+		kc.mu.Lock()
+		entry, ok := kc.keys[task.key]
+		valid := ok && entry.randWindows[task.chainID] == task.window
+		for height, pair := range pairs {
+			w := task.window
+			if !valid || height < w.start || height >= w.start+kc.windowSize || w.pairs[height] != nil {
+				pair.secretRand.Zero()
+				continue
+			}
+			w.pairs[height] = pair
+		}
+		kc.mu.Unlock()
+
+		select {
+		case <-kc.quit:
+			return
+		default:
+		}
+	}
+}
+
+// lock evicts the given key, or all the keys if fpPk is empty
+func (kc *keyCache) lock(fpPk []byte) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	if len(fpPk) == 0 {
+		for k, entry := range kc.keys {
+			kc.evict(k, entry)
+		}
+		return
+	}
+
+	k := hex.EncodeToString(fpPk)
+	if entry, ok := kc.keys[k]; ok {
+		kc.evict(k, entry)
+	}
+}
+
+// close stops the eviction loop and evicts all the keys
+func (kc *keyCache) close() {
+	close(kc.quit)
+	kc.wg.Wait()
+	kc.lock(nil)
+}
@@ -0,0 +1,145 @@
+package eotsmanager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
+)
+
+// TestRandWindow tests that the randomness is precomputed for the heights after
+// the last signed one, and that it is wiped once the key is locked
+func TestRandWindow(t *testing.T) {
+	kc := newKeyCache(time.Hour, 5)
+	defer kc.close()
+
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fpPk := schnorr.SerializePubKey(sk.PubKey())
+	chainID := []byte("chain-test")
+	msr, _, err := fpkeyring.GenerateMasterRandPairWithScheme(sk.Serialize(), chainID, fpkeyring.RandSchemeUint32)
+	require.NoError(t, err)
+	kc.addPrivKey(fpPk, sk)
+	kc.addMasterRand(fpPk, chainID, msr)
+
+	// nothing is precomputed before the first signing
+	_, _, ok := kc.takeRandPair(fpPk, chainID, 11)
+	require.False(t, ok)
+
+	kc.advanceRandWindow(fpPk, chainID, 10)
+	requireWindowHeights(t, kc, fpPk, chainID, []uint64{11, 12, 13, 14, 15})
+
+	sr, pr, ok := kc.takeRandPair(fpPk, chainID, 11)
+	require.True(t, ok)
+	expectedSr, expectedPr, err := msr.DeriveRandPair(11)
+	require.NoError(t, err)
+	require.True(t, expectedSr.Equals(sr))
+	require.True(t, expectedPr.Equals(pr))
+	_, _, ok = kc.takeRandPair(fpPk, chainID, 11)
+	require.False(t, ok)
+
+	// the window moves with the signed height
+	kc.advanceRandWindow(fpPk, chainID, 13)
+	requireWindowHeights(t, kc, fpPk, chainID, []uint64{14, 15, 16, 17, 18})
+	// and does not move back
+	kc.advanceRandWindow(fpPk, chainID, 12)
+	requireWindowHeights(t, kc, fpPk, chainID, []uint64{14, 15, 16, 17, 18})
+
+	kc.lock(fpPk)
+	_, _, ok = kc.takeRandPair(fpPk, chainID, 14)
+	require.False(t, ok)
+	// the master randomness is zeroized along with the key
+	_, _, err = msr.DeriveRandPair(14)
+	require.ErrorIs(t, err, fpkeyring.ErrMasterRandZeroed)
+}
+
+// TestEvictZeroizesMasterRand tests that the master secret randomness of
+// every scheme cannot be used once the key is evicted
+func TestEvictZeroizesMasterRand(t *testing.T) {
+	kc := newKeyCache(time.Hour, 0)
+	defer kc.close()
+
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fpPk := schnorr.SerializePubKey(sk.PubKey())
+	kc.addPrivKey(fpPk, sk)
+
+	var msrs []fpkeyring.MasterSecretRand
+	for _, scheme := range fpkeyring.RandSchemes() {
+		chainID := []byte("chain-" + scheme)
+		msr, _, err := fpkeyring.GenerateMasterRandPairWithScheme(sk.Serialize(), chainID, scheme)
+		require.NoError(t, err)
+		_, _, err = msr.DeriveRandPair(1)
+		require.NoError(t, err)
+		kc.addMasterRand(fpPk, chainID, msr)
+		msrs = append(msrs, msr)
+	}
+
+	kc.lock(fpPk)
+	for _, msr := range msrs {
+		_, _, err := msr.DeriveRandPair(1)
+		require.ErrorIs(t, err, fpkeyring.ErrMasterRandZeroed)
+	}
+}
+
+// TestMasterRandCopy tests that the copy of the cached master secret randomness
+// keeps deriving the same randomness after the key is evicted
+func TestMasterRandCopy(t *testing.T) {
+	kc := newKeyCache(time.Hour, 0)
+	defer kc.close()
+
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fpPk := schnorr.SerializePubKey(sk.PubKey())
+	kc.addPrivKey(fpPk, sk)
+
+	for _, scheme := range fpkeyring.RandSchemes() {
+		chainID := []byte("chain-" + scheme)
+		msr, _, err := fpkeyring.GenerateMasterRandPairWithScheme(sk.Serialize(), chainID, scheme)
+		require.NoError(t, err)
+		expectedSr, expectedPr, err := msr.DeriveRandPair(1)
+		require.NoError(t, err)
+		kc.addMasterRand(fpPk, chainID, msr.Clone())
+
+		msrCopy := kc.masterRand(fpPk, chainID)
+		require.NotNil(t, msrCopy)
+		kc.lock(fpPk)
+		sr, pr, err := msrCopy.DeriveRandPair(1)
+		require.NoError(t, err)
+		require.True(t, expectedSr.Equals(sr))
+		require.True(t, expectedPr.Equals(pr))
+
+		// zeroizing the copy does not affect the original either
+		msrCopy.Zero()
+		_, _, err = msrCopy.DeriveRandPair(1)
+		require.ErrorIs(t, err, fpkeyring.ErrMasterRandZeroed)
+		_, _, err = msr.DeriveRandPair(1)
+		require.NoError(t, err)
+
+		kc.addPrivKey(fpPk, sk)
+	}
+}
+
+func requireWindowHeights(t *testing.T, kc *keyCache, fpPk []byte, chainID []byte, expected []uint64) {
+	require.Eventually(t, func() bool {
+		kc.mu.Lock()
+		defer kc.mu.Unlock()
+
+		entry := kc.get(fpPk)
+		if entry == nil || entry.randWindows[string(chainID)] == nil {
+			return false
+		}
+		var heights []uint64
+		for height := range entry.randWindows[string(chainID)].pairs {
+			heights = append(heights, height)
+		}
+		slices.Sort(heights)
+
+		return slices.Equal(expected, heights)
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -1,9 +1,15 @@
 package eotsmanager
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/babylonchain/finality-provider/metrics"
 
@@ -31,12 +37,22 @@ const (
 var _ EOTSManager = &LocalEOTSManager{}
 
 type LocalEOTSManager struct {
-	kr     keyring.Keyring
-	es     *store.EOTSStore
-	logger *zap.Logger
+	kr             keyring.Keyring
+	keyringBackend string
+	homeDir        string
+	es             *store.EOTSStore
+	logger         *zap.Logger
 	// input is to send passphrase to kr
 	input   *strings.Reader
 	metrics *metrics.EotsMetrics
+	// keyCache is nil if the unlocked keys are not cached
+	keyCache *keyCache
+	// policies is nil if no signing policy is enforced
+	policies *policyEngine
+	// haltState caches the persisted halt state, it is nil unless the signing is halted
+	haltState atomic.Pointer[eotstypes.HaltState]
+	// adminCredHash is the SHA-256 hash of the admin credential that can resume the signing
+	adminCredHash []byte
 }
 
 func NewLocalEOTSManager(homeDir, keyringBackend string, dbbackend kvdb.Backend, logger *zap.Logger) (*LocalEOTSManager, error) {
@@ -52,15 +68,44 @@ func NewLocalEOTSManager(homeDir, keyringBackend string, dbbackend kvdb.Backend,
 		return nil, fmt.Errorf("failed to initialize keyring: %w", err)
 	}
 
+	haltState, err := es.GetHaltState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the halt state: %w", err)
+	}
+
 	eotsMetrics := metrics.NewEotsMetrics()
 
-	return &LocalEOTSManager{
-		kr:      kr,
-		es:      es,
-		logger:  logger,
-		input:   inputReader,
-		metrics: eotsMetrics,
-	}, nil
+	lm := &LocalEOTSManager{
+		kr:             kr,
+		keyringBackend: keyringBackend,
+		homeDir:        homeDir,
+		es:             es,
+		logger:         logger,
+		input:          inputReader,
+		metrics:        eotsMetrics,
+	}
+	if haltState != nil {
+		logger.Warn("the signing is halted", zap.String("reason", haltState.Reason))
+		lm.haltState.Store(haltState)
+	}
+
+	return lm, nil
+}
+
+// EnableKeyCache keeps the unlocked keys and the master secret randomness derived
+// from them in memory until they have not been used for the given TTL
+// The randomness at the next randWindowSize heights after the last signed height of
+// each unlocked key and chain is derived in the background, unless it is 0
+// The keys are not cached if the TTL is not positive
+// NOTE: it should be called before the manager starts serving requests
+func (lm *LocalEOTSManager) EnableKeyCache(ttl time.Duration, randWindowSize uint32) {
+	if lm.keyCache != nil {
+		lm.keyCache.close()
+		lm.keyCache = nil
+	}
+	if ttl > 0 {
+		lm.keyCache = newKeyCache(ttl, randWindowSize)
+	}
 }
 
 func initKeyring(homeDir, keyringBackend string, inputReader *strings.Reader) (keyring.Keyring, error) {
@@ -128,6 +173,13 @@ func (lm *LocalEOTSManager) CreateKeyWithMnemonic(name, passphrase, hdPath, mnem
 	}
 
 	if err := lm.es.AddEOTSKeyName(eotsPk.MustToBTCPK(), name); err != nil {
+		// remove the record so that the keyring stays consistent with the key names,
+		// e.g., if the same key has been imported under another name
+		lm.input.Reset(passphrase)
+		if delErr := lm.kr.Delete(name); delErr != nil {
+			lm.logger.Error("failed to remove the keyring record of the EOTS key",
+				zap.String("key name", name), zap.Error(delErr))
+		}
 		return nil, err
 	}
 
@@ -161,46 +213,481 @@ func loadBIP340PubKeyFromKeyringRecord(record *keyring.Record) (*bbntypes.BIP340
 	}
 }
 
+// ImportMnemonic imports the EOTS key from the given mnemonic, which is either the seed phrase
+// that the key is derived from with hdPath, or the key mnemonic returned by ExportMnemonic
+func (lm *LocalEOTSManager) ImportMnemonic(name, passphrase, hdPath, mnemonic string, isKeyMnemonic bool) ([]byte, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+
+	if !isKeyMnemonic {
+		eotsPk, err := lm.CreateKeyWithMnemonic(name, passphrase, hdPath, mnemonic)
+		if err != nil {
+			return nil, err
+		}
+		return eotsPk.MustMarshal(), nil
+	}
+
+	if lm.keyExists(name) {
+		return nil, eotstypes.ErrFinalityProviderAlreadyExisted
+	}
+
+	// the last byte is the checksum
+	keyBytesWithChecksum, err := bip39.MnemonicToByteArray(mnemonic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key mnemonic: %w", err)
+	}
+	keyBytes := keyBytesWithChecksum[:len(keyBytesWithChecksum)-1]
+	var k btcec.ModNScalar
+	if len(keyBytes) != btcec.PrivKeyBytesLen || k.SetByteSlice(keyBytes) || k.IsZero() {
+		return nil, fmt.Errorf("the key mnemonic does not encode a valid private key")
+	}
+	privKey := btcec.PrivKeyFromScalar(&k)
+	eotsPk := bbntypes.NewBIP340PubKeyFromBTCPK(privKey.PubKey())
+
+	lm.input.Reset(passphrase + "\n" + passphrase)
+	if err := lm.kr.ImportPrivKeyHex(name, hex.EncodeToString(keyBytes), secp256k1Type); err != nil {
+		return nil, err
+	}
+
+	if err := lm.es.AddEOTSKeyName(privKey.PubKey(), name); err != nil {
+		lm.input.Reset(passphrase)
+		if delErr := lm.kr.Delete(name); delErr != nil {
+			lm.logger.Error("failed to remove the keyring record of the EOTS key",
+				zap.String("key name", name), zap.Error(delErr))
+		}
+		return nil, err
+	}
+
+	lm.logger.Info(
+		"successfully imported an EOTS key",
+		zap.String("key name", name),
+		zap.String("pk", eotsPk.MarshalHex()),
+	)
+
+	return eotsPk.MustMarshal(), nil
+}
+
+// ExportMnemonic returns the key mnemonic of the EOTS key, which encodes the private key
+// as a BIP-39 mnemonic
+// NOTE: the seed phrase that the key is created from cannot be exported,
+// as it is not kept in the keyring
+func (lm *LocalEOTSManager) ExportMnemonic(fpPk []byte, passphrase string) (string, error) {
+	privKey, err := lm.getEOTSPrivKey(fpPk, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(privKey.Serialize())
+}
+
+// ListKeys returns all the EOTS keys
+func (lm *LocalEOTSManager) ListKeys() ([]*eotstypes.KeyInfo, error) {
+	keyNames, err := lm.es.GetAllEOTSKeyNames()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*eotstypes.KeyInfo, len(keyNames))
+	for i, kn := range keyNames {
+		keys[i] = &eotstypes.KeyInfo{Name: kn.KeyName, FpPk: kn.FpPk}
+	}
+
+	return keys, nil
+}
+
+// ShowKey returns the EOTS key, along with its master public randomness
+// for the given chain if chainID is not empty
+func (lm *LocalEOTSManager) ShowKey(fpPk []byte, chainID []byte, passphrase string) (*eotstypes.KeyInfo, error) {
+	name, err := lm.es.GetEOTSKeyName(fpPk)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := &eotstypes.KeyInfo{Name: name, FpPk: fpPk}
+	if len(chainID) == 0 {
+		return keyInfo, nil
+	}
+
+	randScheme, err := lm.getRandScheme(fpPk, chainID)
+	if err != nil {
+		return nil, err
+	}
+	msr, mpr, err := lm.getMasterRandPair(fpPk, chainID, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	msr.Zero()
+	keyInfo.MasterPubRand = mpr.MarshalBase58()
+	keyInfo.RandScheme = randScheme
+
+	return keyInfo, nil
+}
+
+// DeleteKey removes the EOTS key from the keyring along with its key name
+// The signing records of the key are kept, so that it cannot double sign if it is imported again
+func (lm *LocalEOTSManager) DeleteKey(fpPk []byte, passphrase string) error {
+	name, err := lm.es.GetEOTSKeyName(fpPk)
+	if err != nil {
+		return err
+	}
+	// the key cannot be deleted without the passphrase
+	privKey, err := lm.getEOTSPrivKey(fpPk, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if lm.keyCache != nil {
+		lm.keyCache.lock(fpPk)
+	}
+
+	if err := lm.es.DeleteEOTSKeyName(fpPk); err != nil {
+		return err
+	}
+	lm.input.Reset(passphrase)
+	if err := lm.kr.Delete(name); err != nil {
+		// restore the key name as the key is still in the keyring
+		if addErr := lm.es.AddEOTSKeyName(privKey.PubKey(), name); addErr != nil {
+			lm.logger.Error("failed to restore the name of the EOTS key",
+				zap.String("key name", name), zap.Error(addErr))
+		}
+		return fmt.Errorf("failed to delete the EOTS key from the keyring: %w", err)
+	}
+
+	lm.logger.Info(
+		"successfully deleted an EOTS key",
+		zap.String("key name", name),
+		zap.String("pk", hex.EncodeToString(fpPk)),
+	)
+
+	return nil
+}
+
 // CreateMasterRandPair creates a pair of master secret/public randomness deterministically
 // from the finality provider's secret key and chain ID
-func (lm *LocalEOTSManager) CreateMasterRandPair(fpPk []byte, chainID []byte, passphrase string) (string, error) {
-	_, mpr, err := lm.getMasterRandPair(fpPk, chainID, passphrase)
+// The randomness derivation scheme is recorded for the key and chain, so that the following
+// signatures derive the randomness in the same way as the returned master public randomness
+func (lm *LocalEOTSManager) CreateMasterRandPair(fpPk []byte, chainID []byte, randScheme string, passphrase string) (string, error) {
+	if randScheme == "" {
+		scheme, err := lm.getRandScheme(fpPk, chainID)
+		if err != nil {
+			return "", err
+		}
+		randScheme = scheme
+	}
+	if err := fpkeyring.ValidateRandScheme(randScheme); err != nil {
+		return "", err
+	}
+
+	// ensure the key exists before recording the scheme
+	if _, err := lm.es.GetEOTSKeyName(fpPk); err != nil {
+		return "", err
+	}
+	if err := lm.es.SetRandScheme(fpPk, chainID, randScheme); err != nil {
+		return "", fmt.Errorf("failed to record the randomness derivation scheme %s: %w", randScheme, err)
+	}
+
+	msr, mpr, err := lm.getMasterRandPair(fpPk, chainID, passphrase)
 	if err != nil {
 		return "", err
 	}
+	msr.Zero()
 
 	return mpr.MarshalBase58(), nil
 }
 
 func (lm *LocalEOTSManager) SignEOTS(fpPk []byte, chainID []byte, msg []byte, height uint64, passphrase string) (*btcec.ModNScalar, error) {
+	sigs, err := lm.SignEOTSBatch(fpPk, chainID, []*eotstypes.HeightMsg{{Height: height, Msg: msg}}, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return sigs[0], nil
+}
+
+func (lm *LocalEOTSManager) SignEOTSBatch(fpPk []byte, chainID []byte, msgs []*eotstypes.HeightMsg, passphrase string) ([]*btcec.ModNScalar, error) {
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("the batch of messages to sign is empty")
+	}
+
+	if err := lm.checkHalted(); err != nil {
+		return nil, err
+	}
+
+	if err := lm.checkChainAndRate(fpPk, chainID); err != nil {
+		return nil, err
+	}
+
+	sigs := make([]*btcec.ModNScalar, len(msgs))
+	msgHashes := make([][32]byte, len(msgs))
+	// indices of the messages that have not been signed before
+	toSign := make([]int, 0, len(msgs))
+	// heights that are signed within this batch, mapped to the index of the message
+	batchHeights := make(map[uint64]int, len(msgs))
+	for i, hm := range msgs {
+		if hm == nil {
+			return nil, fmt.Errorf("nil message at index %d of the batch", i)
+		}
+		msgHashes[i] = sha256.Sum256(hm.Msg)
+
+		// refuse to sign different messages at the same height within the batch
+		if j, ok := batchHeights[hm.Height]; ok {
+			if msgHashes[i] != msgHashes[j] {
+				return nil, fmt.Errorf("%w: height %d of chain %s appears twice in the batch",
+					eotstypes.ErrDoubleSign, hm.Height, string(chainID))
+			}
+			continue
+		}
+		batchHeights[hm.Height] = i
+
+		// refuse to sign a different message at a height that has been signed before
+		cachedSig, err := lm.checkSigningRecord(fpPk, chainID, hm.Height, msgHashes[i][:])
+		if err != nil {
+			return nil, err
+		}
+		if cachedSig != nil {
+			sigs[i] = cachedSig
+			continue
+		}
+		toSign = append(toSign, i)
+	}
+
+	if len(toSign) != 0 {
+		heights := make([]uint64, len(toSign))
+		for k, i := range toSign {
+			heights[k] = msgs[i].Height
+		}
+		if err := lm.checkHeights(fpPk, chainID, heights); err != nil {
+			return nil, err
+		}
+		if err := lm.checkSignedWatermark(fpPk, chainID, heights); err != nil {
+			return nil, err
+		}
+
+		if err := lm.signAndRecord(fpPk, chainID, msgs, msgHashes, toSign, sigs, passphrase); err != nil {
+			return nil, err
+		}
+
+		if lm.keyCache != nil {
+			lm.keyCache.advanceRandWindow(fpPk, chainID, slices.Max(heights))
+		}
+	}
+
+	// fill in the signatures of the repeated messages
+	for i, hm := range msgs {
+		if sigs[i] == nil {
+			sigs[i] = sigs[batchHeights[hm.Height]]
+		}
+	}
+
+	return sigs, nil
+}
+
+// signAndRecord signs the messages at the given indices and persists their signing records
+// before filling in the signatures
+func (lm *LocalEOTSManager) signAndRecord(
+	fpPk []byte,
+	chainID []byte,
+	msgs []*eotstypes.HeightMsg,
+	msgHashes [][32]byte,
+	toSign []int,
+	sigs []*btcec.ModNScalar,
+	passphrase string,
+) error {
 	// get master secret randomness
-	// TODO: instead of calculating master secret randomness everytime, is it possible
-	// to manage it in the keyring?
-	msr, _, err := lm.getMasterRandPair(fpPk, chainID, passphrase)
+	msr, err := lm.masterSecretRand(fpPk, chainID, passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get master secret randomness: %w", err)
+		return fmt.Errorf("failed to get master secret randomness: %w", err)
 	}
+	defer msr.Zero()
 
-	// derive secret randomness
-	sr, _, err := msr.DeriveRandPair(uint32(height)) // TODO: generalise to uint64
+	privKey, err := lm.unlockedPrivKey(fpPk, passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secret randomness: %w", err)
+		return fmt.Errorf("failed to get EOTS private key: %w", err)
 	}
 
-	privKey, err := lm.getEOTSPrivKey(fpPk, passphrase)
+	newSigs := make([]*btcec.ModNScalar, len(toSign))
+	records := make([]*eotstypes.SigningRecord, len(toSign))
+	now := time.Now().Unix()
+	for k, i := range toSign {
+		sr, err := lm.secretRand(fpPk, chainID, msr, msgs[i].Height)
+		if err != nil {
+			return fmt.Errorf("failed to get secret randomness: %w", err)
+		}
+
+		sig, err := eots.Sign(privKey, sr, msgs[i].Msg)
+		sr.Zero()
+		if err != nil {
+			return err
+		}
+
+		sigBytes := sig.Bytes()
+		newSigs[k] = sig
+		records[k] = &eotstypes.SigningRecord{
+			FpPk:      fpPk,
+			ChainID:   chainID,
+			Height:    msgs[i].Height,
+			MsgHash:   msgHashes[i][:],
+			Sig:       sigBytes[:],
+			Timestamp: now,
+		}
+	}
+
+	// the records have to be persisted before the signatures are released
+	err = lm.es.SaveSigningRecords(records)
+	if errors.Is(err, store.ErrDuplicateSigningRecord) {
+		// a concurrent request has signed at some of the heights,
+		// so save the records one by one and only release the signatures
+		// if the messages are the same
+		for k, i := range toSign {
+			sig, err := lm.saveSigningRecord(records[k], newSigs[k])
+			if err != nil {
+				return err
+			}
+			sigs[i] = sig
+		}
+		return nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get EOTS private key: %w", err)
+		return fmt.Errorf("failed to save the signing records: %w", err)
+	}
+
+	fpPkHex := hex.EncodeToString(fpPk)
+	for k, i := range toSign {
+		sigs[i] = newSigs[k]
+		// Update metrics
+		lm.metrics.IncrementEotsFpTotalEotsSignCounter(fpPkHex)
+		lm.metrics.SetEotsFpLastEotsSignHeight(fpPkHex, float64(msgs[i].Height))
+	}
+
+	return nil
+}
+
+// secretRand returns the secret randomness at the given height from the window
+// of the precomputed randomness, or derives it if it has not been precomputed
+func (lm *LocalEOTSManager) secretRand(fpPk []byte, chainID []byte, msr fpkeyring.MasterSecretRand, height uint64) (*eots.PrivateRand, error) {
+	if lm.keyCache != nil && lm.keyCache.windowSize > 0 {
+		fpPkHex := hex.EncodeToString(fpPk)
+		if sr, _, ok := lm.keyCache.takeRandPair(fpPk, chainID, height); ok {
+			lm.metrics.IncrementEotsRandWindowHitCounter(fpPkHex)
+			return sr, nil
+		}
+		lm.metrics.IncrementEotsRandWindowMissCounter(fpPkHex)
+	}
+
+	sr, _, err := msr.DeriveRandPair(height)
+
+	return sr, err
+}
+
+// saveSigningRecord saves a single signing record and returns the signature
+// that is safe to be released
+func (lm *LocalEOTSManager) saveSigningRecord(record *eotstypes.SigningRecord, sig *btcec.ModNScalar) (*btcec.ModNScalar, error) {
+	err := lm.es.SaveSigningRecord(record)
+	if errors.Is(err, store.ErrDuplicateSigningRecord) {
+		return lm.checkSigningRecord(record.FpPk, record.ChainID, record.Height, record.MsgHash)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the signing record: %w", err)
 	}
 
 	// Update metrics
-	lm.metrics.IncrementEotsFpTotalEotsSignCounter(hex.EncodeToString(fpPk))
-	lm.metrics.SetEotsFpLastEotsSignHeight(hex.EncodeToString(fpPk), float64(height))
+	lm.metrics.IncrementEotsFpTotalEotsSignCounter(hex.EncodeToString(record.FpPk))
+	lm.metrics.SetEotsFpLastEotsSignHeight(hex.EncodeToString(record.FpPk), float64(record.Height))
+
+	return sig, nil
+}
+
+// checkSigningRecord returns the recorded signature if the same message has been signed
+// at the given height, or ErrDoubleSign if a different message has been signed
+// nil is returned if there is no recorded signature at the given height
+func (lm *LocalEOTSManager) checkSigningRecord(fpPk []byte, chainID []byte, height uint64, msgHash []byte) (*btcec.ModNScalar, error) {
+	record, err := lm.es.GetSigningRecord(fpPk, chainID, height)
+	if errors.Is(err, store.ErrSigningRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the signing record: %w", err)
+	}
+
+	if !bytes.Equal(record.MsgHash, msgHash) {
+		lm.logger.Error(
+			"refused to sign a conflicting message",
+			zap.String("fp_pk", hex.EncodeToString(fpPk)),
+			zap.String("chain_id", string(chainID)),
+			zap.Uint64("height", height),
+			zap.String("signed_msg_hash", hex.EncodeToString(record.MsgHash)),
+			zap.String("conflicting_msg_hash", hex.EncodeToString(msgHash)),
+		)
+		return nil, fmt.Errorf("%w: height %d of chain %s", eotstypes.ErrDoubleSign, height, string(chainID))
+	}
+
+	// the record is imported without the signature, so it needs to be signed again
+	if len(record.Sig) == 0 {
+		return nil, nil
+	}
 
-	return eots.Sign(privKey, sr, msg)
+	var sig btcec.ModNScalar
+	sig.SetByteSlice(record.Sig)
+
+	return &sig, nil
+}
+
+// checkSignedWatermark returns ErrDoubleSign if any of the heights to be newly signed
+// is at or below the signed watermark without a signing record, as the record may
+// have been lost, e.g., by restoring an older database, and a different message may
+// have been signed at the height
+func (lm *LocalEOTSManager) checkSignedWatermark(fpPk []byte, chainID []byte, heights []uint64) error {
+	watermark, err := lm.es.GetSignedWatermark(fpPk, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to get the signed watermark: %w", err)
+	}
+	// nothing has been signed for the chain yet
+	if watermark == 0 {
+		return nil
+	}
+
+	for _, h := range heights {
+		if h > watermark {
+			continue
+		}
+		// a record imported without the signature matches the message
+		_, err := lm.es.GetSigningRecord(fpPk, chainID, h)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, store.ErrSigningRecordNotFound) {
+			return fmt.Errorf("failed to get the signing record: %w", err)
+		}
+
+		lm.logger.Error(
+			"refused to sign below the signed watermark without a signing record",
+			zap.String("fp_pk", hex.EncodeToString(fpPk)),
+			zap.String("chain_id", string(chainID)),
+			zap.Uint64("height", h),
+			zap.Uint64("signed_watermark", watermark),
+		)
+		return fmt.Errorf("%w: height %d of chain %s is not above the signed watermark %d and has no signing record",
+			eotstypes.ErrDoubleSign, h, string(chainID), watermark)
+	}
+
+	return nil
+}
+
+func (lm *LocalEOTSManager) SigningRecord(fpPk []byte, chainID []byte, height uint64) (*eotstypes.SigningRecord, error) {
+	return lm.es.GetSigningRecord(fpPk, chainID, height)
 }
 
 func (lm *LocalEOTSManager) SignSchnorrSig(fpPk []byte, msg []byte, passphrase string) (*schnorr.Signature, error) {
-	privKey, err := lm.getEOTSPrivKey(fpPk, passphrase)
+	if err := lm.checkHalted(); err != nil {
+		return nil, err
+	}
+
+	if err := lm.checkRate(fpPk); err != nil {
+		return nil, err
+	}
+
+	privKey, err := lm.unlockedPrivKey(fpPk, passphrase)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get EOTS private key: %w", err)
 	}
@@ -215,7 +702,41 @@ func (lm *LocalEOTSManager) signSchnorrSigFromPrivKey(privKey *btcec.PrivateKey,
 	return schnorr.Sign(privKey, msg)
 }
 
+// SignPoP signs the BTC half of the proof-of-possession that binds the EOTS key to the chain key,
+// i.e., a Schnorr signature over the SHA-256 hash of the chain signature
+// The chain signature is verified against the chain public key first, so that the EOTS key only
+// signs the binding to a chain key whose holder has signed the EOTS public key
+func (lm *LocalEOTSManager) SignPoP(fpPk []byte, chainPk []byte, chainSig []byte, passphrase string) (*schnorr.Signature, error) {
+	if len(chainPk) != secp256k1.PubKeySize {
+		return nil, fmt.Errorf("invalid chain public key size, expected %d, got %d", secp256k1.PubKeySize, len(chainPk))
+	}
+	if !(&secp256k1.PubKey{Key: chainPk}).VerifySignature(fpPk, chainSig) {
+		return nil, eotstypes.ErrInvalidChainSig
+	}
+
+	if err := lm.checkHalted(); err != nil {
+		return nil, err
+	}
+
+	if err := lm.checkRate(fpPk); err != nil {
+		return nil, err
+	}
+
+	privKey, err := lm.unlockedPrivKey(fpPk, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EOTS private key: %w", err)
+	}
+
+	chainSigHash := sha256.Sum256(chainSig)
+
+	return lm.signSchnorrSigFromPrivKey(privKey, fpPk, chainSigHash[:])
+}
+
 func (lm *LocalEOTSManager) SignSchnorrSigFromKeyname(keyName, passphrase string, msg []byte) (*schnorr.Signature, *bbntypes.BIP340PubKey, error) {
+	if err := lm.checkHalted(); err != nil {
+		return nil, nil, err
+	}
+
 	lm.input.Reset(passphrase)
 	k, err := lm.kr.Key(keyName)
 	if err != nil {
@@ -227,6 +748,10 @@ func (lm *LocalEOTSManager) SignSchnorrSigFromKeyname(keyName, passphrase string
 		return nil, nil, err
 	}
 
+	if err := lm.checkRate(*eotsPk); err != nil {
+		return nil, nil, err
+	}
+
 	privKey, err := eotsPrivKeyFromRecord(k)
 	if err != nil {
 		return nil, nil, err
@@ -239,17 +764,113 @@ func (lm *LocalEOTSManager) SignSchnorrSigFromKeyname(keyName, passphrase string
 	return signature, eotsPk, nil
 }
 
+func (lm *LocalEOTSManager) Unlock(fpPk []byte, passphrase string) error {
+	if lm.keyCache == nil {
+		return fmt.Errorf("the key cache is disabled")
+	}
+
+	privKey, err := lm.getEOTSPrivKey(fpPk, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to unlock the EOTS key: %w", err)
+	}
+	lm.keyCache.addPrivKey(fpPk, privKey)
+
+	lm.logger.Info("unlocked the EOTS key", zap.String("pk", hex.EncodeToString(fpPk)))
+
+	return nil
+}
+
+func (lm *LocalEOTSManager) Lock(fpPk []byte) error {
+	if lm.keyCache == nil {
+		return nil
+	}
+	lm.keyCache.lock(fpPk)
+
+	if len(fpPk) == 0 {
+		lm.logger.Info("locked all the EOTS keys")
+	} else {
+		lm.logger.Info("locked the EOTS key", zap.String("pk", hex.EncodeToString(fpPk)))
+	}
+
+	return nil
+}
+
 func (lm *LocalEOTSManager) Close() error {
+	if lm.keyCache != nil {
+		lm.keyCache.close()
+	}
+
 	return nil
 }
 
 // getMasterRandPair returns a randomness pair generated based on the given finality provider key, chainID and height
-func (lm *LocalEOTSManager) getMasterRandPair(fpPk []byte, chainID []byte, passphrase string) (*eots.MasterSecretRand, *eots.MasterPublicRand, error) {
-	record, err := lm.KeyRecord(fpPk, passphrase)
+// with the randomness derivation scheme of the key and chain
+func (lm *LocalEOTSManager) getMasterRandPair(fpPk []byte, chainID []byte, passphrase string) (fpkeyring.MasterSecretRand, fpkeyring.MasterPublicRand, error) {
+	randScheme, err := lm.getRandScheme(fpPk, chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	privKey, err := lm.unlockedPrivKey(fpPk, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	msr, mpr, err := fpkeyring.GenerateMasterRandPairWithScheme(privKey.Serialize(), chainID, randScheme)
 	if err != nil {
 		return nil, nil, err
 	}
-	return fpkeyring.GenerateMasterRandPair(record.PrivKey.Serialize(), chainID)
+	if lm.keyCache != nil {
+		lm.keyCache.addMasterRand(fpPk, chainID, msr.Clone())
+	}
+
+	return msr, mpr, nil
+}
+
+// masterSecretRand returns a copy of the master secret randomness of the given finality provider key
+// and chainID from the key cache, or derives it if it is not cached
+// The caller owns the returned master secret randomness, and should zeroize it once done
+func (lm *LocalEOTSManager) masterSecretRand(fpPk []byte, chainID []byte, passphrase string) (fpkeyring.MasterSecretRand, error) {
+	if lm.keyCache != nil {
+		if msr := lm.keyCache.masterRand(fpPk, chainID); msr != nil {
+			return msr, nil
+		}
+	}
+
+	msr, _, err := lm.getMasterRandPair(fpPk, chainID, passphrase)
+	return msr, err
+}
+
+// getRandScheme returns the randomness derivation scheme recorded for the given key and chain
+// The keys and chains without a recorded scheme predate the schemes, so they use RandSchemeUint32
+func (lm *LocalEOTSManager) getRandScheme(fpPk []byte, chainID []byte) (string, error) {
+	randScheme, err := lm.es.GetRandScheme(fpPk, chainID)
+	if errors.Is(err, store.ErrRandSchemeNotFound) {
+		return fpkeyring.RandSchemeUint32, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get the randomness derivation scheme: %w", err)
+	}
+
+	return randScheme, nil
+}
+
+// unlockedPrivKey returns the EOTS private key from the key cache, or loads it
+// from the keyring if it is not cached
+func (lm *LocalEOTSManager) unlockedPrivKey(fpPk []byte, passphrase string) (*btcec.PrivateKey, error) {
+	if lm.keyCache == nil {
+		return lm.getEOTSPrivKey(fpPk, passphrase)
+	}
+
+	if privKey := lm.keyCache.privKey(fpPk); privKey != nil {
+		return privKey, nil
+	}
+
+	privKey, err := lm.getEOTSPrivKey(fpPk, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	lm.keyCache.addPrivKey(fpPk, privKey)
+
+	return privKey, nil
 }
 
 // TODO: we ignore passPhrase in local implementation for now
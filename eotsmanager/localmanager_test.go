@@ -1,6 +1,9 @@
 package eotsmanager_test
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -11,8 +14,11 @@ import (
 	bbn "github.com/babylonchain/babylon/types"
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	eotscfg "github.com/babylonchain/finality-provider/eotsmanager/config"
+	"github.com/babylonchain/finality-provider/eotsmanager/store"
 	"github.com/babylonchain/finality-provider/eotsmanager/types"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
 	"github.com/babylonchain/finality-provider/testutil"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -58,6 +64,74 @@ func FuzzCreateKey(f *testing.F) {
 	})
 }
 
+// FuzzKeyManagement tests listing, showing, exporting, importing and deleting EOTS keys
+func FuzzKeyManagement(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		mnemonic, err := eotsmanager.NewMnemonic()
+		require.NoError(t, err)
+		fpPk, err := lm.ImportMnemonic(fpName, passphrase, hdPath, mnemonic, false)
+		require.NoError(t, err)
+
+		keys, err := lm.ListKeys()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, fpName, keys[0].Name)
+		require.Equal(t, fpPk, keys[0].FpPk)
+
+		chainID := datagen.GenRandomByteArray(r, 10)
+		mpr, err := lm.CreateMasterRandPair(fpPk, chainID, "", passphrase)
+		require.NoError(t, err)
+		key, err := lm.ShowKey(fpPk, chainID, passphrase)
+		require.NoError(t, err)
+		require.Equal(t, fpName, key.Name)
+		require.Equal(t, mpr, key.MasterPubRand)
+		require.Equal(t, fpkeyring.RandSchemeUint32, key.RandScheme)
+
+		// importing the same key under another name does not leave it in the keyring
+		otherName := fpName + "-other"
+		_, err = lm.ImportMnemonic(otherName, passphrase, hdPath, mnemonic, false)
+		require.Error(t, err)
+		keyMnemonic, err := lm.ExportMnemonic(fpPk, passphrase)
+		require.NoError(t, err)
+		_, err = lm.ImportMnemonic(otherName, passphrase, hdPath, keyMnemonic, true)
+		require.Error(t, err)
+
+		err = lm.DeleteKey(fpPk, passphrase)
+		require.NoError(t, err)
+		keys, err = lm.ListKeys()
+		require.NoError(t, err)
+		require.Empty(t, keys)
+		_, err = lm.ShowKey(fpPk, nil, passphrase)
+		require.ErrorIs(t, err, store.ErrEOTSKeyNameNotFound)
+
+		// the exported key mnemonic recovers the same key
+		importedPk, err := lm.ImportMnemonic(otherName, passphrase, hdPath, keyMnemonic, true)
+		require.NoError(t, err)
+		require.Equal(t, fpPk, importedPk)
+		key, err = lm.ShowKey(fpPk, chainID, passphrase)
+		require.NoError(t, err)
+		require.Equal(t, otherName, key.Name)
+		require.Equal(t, mpr, key.MasterPubRand)
+	})
+}
+
 func FuzzCreateMasterRandPair(f *testing.F) {
 	testutil.AddRandomSeedsToFuzzer(f, 10)
 	f.Fuzz(func(t *testing.T, seed int64) {
@@ -83,7 +157,7 @@ func FuzzCreateMasterRandPair(f *testing.F) {
 
 		chainID := datagen.GenRandomByteArray(r, 10)
 
-		mprStr, err := lm.CreateMasterRandPair(fpPk, chainID, passphrase)
+		mprStr, err := lm.CreateMasterRandPair(fpPk, chainID, "", passphrase)
 		require.NoError(t, err)
 		mpr, err := eots.NewMasterPublicRandFromBase58(mprStr)
 		require.NoError(t, err)
@@ -108,3 +182,582 @@ func FuzzCreateMasterRandPair(f *testing.F) {
 		}
 	})
 }
+
+// FuzzSignEOTSDoubleSign tests that signing the same message at a height
+// returns the recorded signature while signing a different one, or signing
+// at or below the signed watermark without a record, is refused
+func FuzzSignEOTSDoubleSign(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+
+		chainID := datagen.GenRandomByteArray(r, 10)
+		height := datagen.RandomInt(r, 100)
+		msg := datagen.GenRandomByteArray(r, 32)
+
+		_, err = lm.SigningRecord(fpPk, chainID, height)
+		require.ErrorIs(t, err, store.ErrSigningRecordNotFound)
+
+		sig, err := lm.SignEOTS(fpPk, chainID, msg, height, passphrase)
+		require.NoError(t, err)
+
+		record, err := lm.SigningRecord(fpPk, chainID, height)
+		require.NoError(t, err)
+		require.Equal(t, height, record.Height)
+		sigBytes := sig.Bytes()
+		require.Equal(t, sigBytes[:], record.Sig)
+
+		// signing the same message again returns the same signature
+		cachedSig, err := lm.SignEOTS(fpPk, chainID, msg, height, passphrase)
+		require.NoError(t, err)
+		require.True(t, sig.Equals(cachedSig))
+
+		// signing a conflicting message is refused
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), height, passphrase)
+		require.ErrorIs(t, err, types.ErrDoubleSign)
+
+		// the same height of a different chain is not affected
+		_, err = lm.SignEOTS(fpPk, datagen.GenRandomByteArray(r, 10), msg, height, passphrase)
+		require.NoError(t, err)
+
+		// signing at or below the signed watermark without a record is refused
+		watermark := height + datagen.RandomInt(r, 100) + 1
+		err = lm.ImportSigningHistory(eotsmanager.NewSigningHistoryInterchange([]*types.SigningHistory{{
+			FpPk:            fpPk,
+			ChainID:         chainID,
+			SignedWatermark: watermark,
+		}}), passphrase)
+		require.NoError(t, err)
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), height+1+r.Uint64()%(watermark-height), passphrase)
+		require.ErrorIs(t, err, types.ErrDoubleSign)
+		cachedSig, err = lm.SignEOTS(fpPk, chainID, msg, height, passphrase)
+		require.NoError(t, err)
+		require.True(t, sig.Equals(cachedSig))
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), watermark+1, passphrase)
+		require.NoError(t, err)
+	})
+}
+
+// FuzzSigningPolicies tests that the signing requests violating the policy
+// of the key are refused before signing
+func FuzzSigningPolicies(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		fpPk, err := lm.CreateKey(testutil.GenRandomHexStr(r, 4), passphrase, hdPath)
+		require.NoError(t, err)
+		otherFpPk, err := lm.CreateKey(testutil.GenRandomHexStr(r, 4), passphrase, hdPath)
+		require.NoError(t, err)
+
+		chainID := "chain-test"
+		maxJump := datagen.RandomInt(r, 10) + 1
+		eotsCfg.SigningPolicy = []string{
+			fmt.Sprintf("fp=%x;chainids=%s,other-chain;maxheightjump=%d;monotonic=true", fpPk, chainID, maxJump),
+			"fp=*;rps=1",
+		}
+		policies, err := eotsCfg.ParseSigningPolicies()
+		require.NoError(t, err)
+		lm.SetSigningPolicies(policies)
+
+		// the chain is not allowed
+		_, err = lm.SignEOTS(fpPk, []byte("unknown-chain"), datagen.GenRandomByteArray(r, 32), 1, passphrase)
+		require.ErrorIs(t, err, types.ErrChainNotAllowed)
+		require.ErrorIs(t, err, types.ErrPolicyViolation)
+
+		// the first height is not restricted
+		height := datagen.RandomInt(r, 100) + 100
+		msg := datagen.GenRandomByteArray(r, 32)
+		_, err = lm.SignEOTS(fpPk, []byte(chainID), msg, height, passphrase)
+		require.NoError(t, err)
+
+		// a height too far above the last signed height is refused
+		_, err = lm.SignEOTS(fpPk, []byte(chainID), datagen.GenRandomByteArray(r, 32), height+maxJump+1, passphrase)
+		require.ErrorIs(t, err, types.ErrHeightJumpTooLarge)
+		_, err = lm.SigningRecord(fpPk, []byte(chainID), height+maxJump+1)
+		require.ErrorIs(t, err, store.ErrSigningRecordNotFound)
+
+		// a batch within the max jump one after another is allowed
+		batch := []*types.HeightMsg{
+			{Height: height + 2*maxJump, Msg: datagen.GenRandomByteArray(r, 32)},
+			{Height: height + maxJump, Msg: datagen.GenRandomByteArray(r, 32)},
+		}
+		_, err = lm.SignEOTSBatch(fpPk, []byte(chainID), batch, passphrase)
+		require.NoError(t, err)
+
+		// a height below the last signed height is refused,
+		// but the signature of a signed height is still returned
+		_, err = lm.SignEOTS(fpPk, []byte(chainID), datagen.GenRandomByteArray(r, 32), height-1, passphrase)
+		require.ErrorIs(t, err, types.ErrNonMonotonicHeight)
+		_, err = lm.SignEOTS(fpPk, []byte(chainID), msg, height, passphrase)
+		require.NoError(t, err)
+
+		// the requests of the other key are limited by the policy of any key
+		_, err = lm.SignSchnorrSig(otherFpPk, datagen.GenRandomByteArray(r, 32), passphrase)
+		require.NoError(t, err)
+		_, err = lm.SignEOTS(otherFpPk, datagen.GenRandomByteArray(r, 10), datagen.GenRandomByteArray(r, 32), height, passphrase)
+		require.ErrorIs(t, err, types.ErrRateLimited)
+	})
+}
+
+// FuzzHaltAndResume tests that the halted signing is refused across restarts
+// until it is resumed with either a key passphrase or the admin credential, and
+// that the key passphrase is refused by the test keyring backend, which does not verify it
+func FuzzHaltAndResume(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+		keyringBackend := keyring.BackendTest
+		if r.Intn(2) == 0 {
+			keyringBackend = keyring.BackendFile
+		}
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, keyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		fpPk, err := lm.CreateKey(testutil.GenRandomHexStr(r, 4), passphrase, hdPath)
+		require.NoError(t, err)
+		chainID := datagen.GenRandomByteArray(r, 10)
+
+		reason := testutil.GenRandomHexStr(r, 8)
+		err = lm.Halt(reason)
+		require.NoError(t, err)
+		require.Equal(t, reason, lm.HaltState().Reason)
+
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), datagen.RandomInt(r, 100), passphrase)
+		require.ErrorIs(t, err, types.ErrSigningHalted)
+		_, err = lm.SignSchnorrSig(fpPk, datagen.GenRandomByteArray(r, 32), passphrase)
+		require.ErrorIs(t, err, types.ErrSigningHalted)
+
+		// the halt survives the restart
+		lm, err = eotsmanager.NewLocalEOTSManager(homeDir, keyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, reason, lm.HaltState().Reason)
+		_, err = lm.SignSchnorrSig(fpPk, datagen.GenRandomByteArray(r, 32), passphrase)
+		require.ErrorIs(t, err, types.ErrSigningHalted)
+
+		// resuming is refused without a valid credential
+		err = lm.Resume(nil, "", "")
+		require.ErrorIs(t, err, types.ErrResumeUnauthorized)
+		err = lm.Resume(datagen.GenRandomByteArray(r, 32), passphrase, "")
+		require.ErrorIs(t, err, types.ErrResumeUnauthorized)
+		adminCredential := testutil.GenRandomHexStr(r, 16)
+		err = lm.Resume(nil, "", adminCredential)
+		require.ErrorIs(t, err, types.ErrResumeUnauthorized)
+		credHash := sha256.Sum256([]byte(adminCredential))
+		lm.SetAdminCredentialHash(credHash[:])
+		err = lm.Resume(nil, "", testutil.GenRandomHexStr(r, 16))
+		require.ErrorIs(t, err, types.ErrResumeUnauthorized)
+		err = lm.Resume(fpPk, testutil.GenRandomHexStr(r, 8), "")
+		require.ErrorIs(t, err, types.ErrResumeUnauthorized)
+		if keyringBackend == keyring.BackendTest {
+			// the test backend accepts any passphrase, so it cannot authorize resuming
+			err = lm.Resume(fpPk, passphrase, "")
+			require.ErrorIs(t, err, types.ErrResumeUnauthorized)
+		}
+		require.NotNil(t, lm.HaltState())
+
+		// resume with either the key passphrase or the admin credential
+		if keyringBackend == keyring.BackendFile && r.Intn(2) == 0 {
+			err = lm.Resume(fpPk, passphrase, "")
+		} else {
+			err = lm.Resume(nil, "", adminCredential)
+		}
+		require.NoError(t, err)
+		require.Nil(t, lm.HaltState())
+
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), datagen.RandomInt(r, 100), passphrase)
+		require.NoError(t, err)
+
+		lm, err = eotsmanager.NewLocalEOTSManager(homeDir, keyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+		require.Nil(t, lm.HaltState())
+	})
+}
+
+// FuzzBackupAndRestore tests that a backup restores the keys, the randomness derivation
+// schemes and the signing history to another home, and that it does not overwrite a newer database
+func FuzzBackupAndRestore(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 5)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		newManager := func(homeDir string) *eotsmanager.LocalEOTSManager {
+			eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+			dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				dbBackend.Close()
+			})
+			lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+			require.NoError(t, err)
+			return lm
+		}
+		lm := newManager(filepath.Join(t.TempDir(), "eots-home"))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+		chainID := []byte(testutil.GenRandomHexStr(r, 10))
+		mpr, err := lm.CreateMasterRandPair(fpPk, chainID, fpkeyring.RandSchemeUint64, passphrase)
+		require.NoError(t, err)
+
+		height := datagen.RandomInt(r, 100) + 1
+		msg := datagen.GenRandomByteArray(r, 32)
+		sig, err := lm.SignEOTS(fpPk, chainID, msg, height, passphrase)
+		require.NoError(t, err)
+
+		backupPath := filepath.Join(t.TempDir(), "eotsd.backup")
+		backupPassphrase := testutil.GenRandomHexStr(r, 8)
+		err = lm.Backup(backupPath, backupPassphrase)
+		require.NoError(t, err)
+
+		// restore to another home
+		restoredLm := newManager(filepath.Join(t.TempDir(), "eots-home"))
+		err = restoredLm.Restore(backupPath, testutil.GenRandomHexStr(r, 8))
+		require.Error(t, err)
+		err = restoredLm.Restore(backupPath, backupPassphrase)
+		require.NoError(t, err)
+
+		keys, err := restoredLm.ListKeys()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, fpName, keys[0].Name)
+		require.Equal(t, fpPk, keys[0].FpPk)
+
+		key, err := restoredLm.ShowKey(fpPk, chainID, passphrase)
+		require.NoError(t, err)
+		require.Equal(t, mpr, key.MasterPubRand)
+		require.Equal(t, fpkeyring.RandSchemeUint64, key.RandScheme)
+
+		restoredSig, err := restoredLm.SignEOTS(fpPk, chainID, msg, height, passphrase)
+		require.NoError(t, err)
+		require.True(t, sig.Equals(restoredSig))
+		_, err = restoredLm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), height, passphrase)
+		require.ErrorIs(t, err, types.ErrDoubleSign)
+
+		// the original database has signed above the backup
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), height+1, passphrase)
+		require.NoError(t, err)
+		err = lm.Restore(backupPath, backupPassphrase)
+		require.ErrorIs(t, err, eotsmanager.ErrOutdatedBackup)
+	})
+}
+
+// FuzzSignEOTSBatch tests that a batch of EOTS signatures is signed and recorded
+// in order, and that a batch containing a conflicting message is refused as a whole
+func FuzzSignEOTSBatch(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+
+		chainID := datagen.GenRandomByteArray(r, 10)
+		startHeight := datagen.RandomInt(r, 100)
+		batchSize := datagen.RandomInt(r, 10) + 2
+		msgs := make([]*types.HeightMsg, batchSize)
+		for i := range msgs {
+			msgs[i] = &types.HeightMsg{
+				Height: startHeight + uint64(i),
+				Msg:    datagen.GenRandomByteArray(r, 32),
+			}
+		}
+
+		// sign the first message individually so that the batch
+		// contains a height that has been signed before
+		firstSig, err := lm.SignEOTS(fpPk, chainID, msgs[0].Msg, msgs[0].Height, passphrase)
+		require.NoError(t, err)
+
+		sigs, err := lm.SignEOTSBatch(fpPk, chainID, msgs, passphrase)
+		require.NoError(t, err)
+		require.Len(t, sigs, len(msgs))
+		require.True(t, firstSig.Equals(sigs[0]))
+
+		mprStr, err := lm.CreateMasterRandPair(fpPk, chainID, "", passphrase)
+		require.NoError(t, err)
+		mpr, err := eots.NewMasterPublicRandFromBase58(mprStr)
+		require.NoError(t, err)
+		fpBTCPK, err := bbn.NewBIP340PubKey(fpPk)
+		require.NoError(t, err)
+		for i, m := range msgs {
+			// the signatures are returned in the same order as the messages
+			pr, err := mpr.DerivePubRand(uint32(m.Height))
+			require.NoError(t, err)
+			err = eots.Verify(fpBTCPK.MustToBTCPK(), pr, m.Msg, sigs[i])
+			require.NoError(t, err)
+
+			record, err := lm.SigningRecord(fpPk, chainID, m.Height)
+			require.NoError(t, err)
+			sigBytes := sigs[i].Bytes()
+			require.Equal(t, sigBytes[:], record.Sig)
+		}
+
+		// a batch with a conflicting message is refused as a whole
+		conflictingMsgs := []*types.HeightMsg{
+			{Height: startHeight + batchSize, Msg: datagen.GenRandomByteArray(r, 32)},
+			{Height: startHeight, Msg: datagen.GenRandomByteArray(r, 32)},
+		}
+		_, err = lm.SignEOTSBatch(fpPk, chainID, conflictingMsgs, passphrase)
+		require.ErrorIs(t, err, types.ErrDoubleSign)
+		_, err = lm.SigningRecord(fpPk, chainID, startHeight+batchSize)
+		require.ErrorIs(t, err, store.ErrSigningRecordNotFound)
+	})
+}
+
+// FuzzUnlockAndLock tests that an unlocked key signs the same as a key loaded
+// from the keyring, and that unlocking requires the key cache to be enabled
+func FuzzUnlockAndLock(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+		defer lm.Close()
+
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+
+		// the key cache is disabled by default
+		err = lm.Unlock(fpPk, passphrase)
+		require.Error(t, err)
+
+		lm.EnableKeyCache(eotsCfg.UnlockedKeyTTL, eotsCfg.RandWindowSize)
+		err = lm.Unlock(fpPk, passphrase)
+		require.NoError(t, err)
+		err = lm.Unlock(datagen.GenRandomByteArray(r, 32), passphrase)
+		require.Error(t, err)
+
+		chainID := datagen.GenRandomByteArray(r, 10)
+		mprStr, err := lm.CreateMasterRandPair(fpPk, chainID, "", passphrase)
+		require.NoError(t, err)
+		mpr, err := eots.NewMasterPublicRandFromBase58(mprStr)
+		require.NoError(t, err)
+		fpBTCPK, err := bbn.NewBIP340PubKey(fpPk)
+		require.NoError(t, err)
+
+		startHeight := datagen.RandomInt(r, 100)
+		for i := uint64(0); i < 3; i++ {
+			// lock the key before the last signing so that it is loaded from the keyring again
+			if i == 2 {
+				err = lm.Lock(nil)
+				require.NoError(t, err)
+			}
+
+			height := startHeight + i
+			msg := datagen.GenRandomByteArray(r, 32)
+			sig, err := lm.SignEOTS(fpPk, chainID, msg, height, passphrase)
+			require.NoError(t, err)
+
+			pr, err := mpr.DerivePubRand(uint32(height))
+			require.NoError(t, err)
+			err = eots.Verify(fpBTCPK.MustToBTCPK(), pr, msg, sig)
+			require.NoError(t, err)
+		}
+	})
+}
+
+// FuzzLockWhileSigning tests that locking the key while batches are being signed
+// does not affect the signing in progress
+func FuzzLockWhileSigning(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 5)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+		defer lm.Close()
+		lm.EnableKeyCache(eotsCfg.UnlockedKeyTTL, eotsCfg.RandWindowSize)
+
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+		fpBTCPK, err := bbn.NewBIP340PubKey(fpPk)
+		require.NoError(t, err)
+		err = lm.Unlock(fpPk, passphrase)
+		require.NoError(t, err)
+
+		randSchemes := fpkeyring.RandSchemes()
+		randScheme := randSchemes[r.Intn(len(randSchemes))]
+		chainID := datagen.GenRandomByteArray(r, 10)
+		mprStr, err := lm.CreateMasterRandPair(fpPk, chainID, randScheme, passphrase)
+		require.NoError(t, err)
+		mpr, err := fpkeyring.NewMasterPublicRandFromBase58(mprStr, randScheme)
+		require.NoError(t, err)
+
+		// lock the key over and over until all the batches are signed
+		done := make(chan struct{})
+		locked := make(chan struct{})
+		go func() {
+			defer close(locked)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					_ = lm.Lock(fpPk)
+				}
+			}
+		}()
+		defer func() {
+			close(done)
+			<-locked
+		}()
+
+		height := datagen.RandomInt(r, 100) + 1
+		for i := 0; i < 5; i++ {
+			msgs := make([]*types.HeightMsg, 50)
+			for j := range msgs {
+				msgs[j] = &types.HeightMsg{Height: height, Msg: datagen.GenRandomByteArray(r, 32)}
+				height++
+			}
+
+			sigs, err := lm.SignEOTSBatch(fpPk, chainID, msgs, passphrase)
+			require.NoError(t, err)
+			for j, hm := range msgs {
+				pr, err := mpr.DerivePubRand(hm.Height)
+				require.NoError(t, err)
+				err = eots.Verify(fpBTCPK.MustToBTCPK(), pr, hm.Msg, sigs[j])
+				require.NoError(t, err)
+			}
+		}
+	})
+}
+
+// FuzzRandSchemes tests that the EOTS signatures can be verified with the master public
+// randomness of the recorded scheme, and that heights overflowing the uint32 scheme are refused
+func FuzzRandSchemes(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+			err := os.RemoveAll(homeDir)
+			require.NoError(t, err)
+		}()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+		fpBTCPK, err := bbn.NewBIP340PubKey(fpPk)
+		require.NoError(t, err)
+
+		uint32ChainID := datagen.GenRandomByteArray(r, 10)
+		uint64ChainID := datagen.GenRandomByteArray(r, 10)
+
+		// the scheme defaults to uint32 and cannot be changed once recorded
+		uint32MprStr, err := lm.CreateMasterRandPair(fpPk, uint32ChainID, "", passphrase)
+		require.NoError(t, err)
+		_, err = lm.CreateMasterRandPair(fpPk, uint32ChainID, fpkeyring.RandSchemeUint64, passphrase)
+		require.ErrorIs(t, err, store.ErrRandSchemeMismatch)
+		uint32Mpr, err := fpkeyring.NewMasterPublicRandFromBase58(uint32MprStr, fpkeyring.RandSchemeUint32)
+		require.NoError(t, err)
+
+		uint64MprStr, err := lm.CreateMasterRandPair(fpPk, uint64ChainID, fpkeyring.RandSchemeUint64, passphrase)
+		require.NoError(t, err)
+		uint64Mpr, err := fpkeyring.NewMasterPublicRandFromBase58(uint64MprStr, fpkeyring.RandSchemeUint64)
+		require.NoError(t, err)
+
+		// heights overflowing uint32 are refused by the uint32 scheme only
+		height := uint64(math.MaxUint32) + datagen.RandomInt(r, math.MaxUint32)
+		msg := datagen.GenRandomByteArray(r, 32)
+		_, err = lm.SignEOTS(fpPk, uint32ChainID, msg, height, passphrase)
+		require.ErrorIs(t, err, fpkeyring.ErrHeightOverflow)
+		_, err = lm.SigningRecord(fpPk, uint32ChainID, height)
+		require.ErrorIs(t, err, store.ErrSigningRecordNotFound)
+
+		sig, err := lm.SignEOTS(fpPk, uint64ChainID, msg, height, passphrase)
+		require.NoError(t, err)
+		pr, err := uint64Mpr.DerivePubRand(height)
+		require.NoError(t, err)
+		err = eots.Verify(fpBTCPK.MustToBTCPK(), pr, msg, sig)
+		require.NoError(t, err)
+
+		// the uint32 scheme derives the same randomness as before
+		height = datagen.RandomInt(r, 100)
+		sig, err = lm.SignEOTS(fpPk, uint32ChainID, msg, height, passphrase)
+		require.NoError(t, err)
+		pr, err = uint32Mpr.DerivePubRand(height)
+		require.NoError(t, err)
+		err = eots.Verify(fpBTCPK.MustToBTCPK(), pr, msg, sig)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,234 @@
+package eotsmanager
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+// the rules of the signing policy, used as the label of the violation counter
+const (
+	ruleChainID       = "chain_id"
+	ruleMaxHeightJump = "max_height_jump"
+	ruleMonotonic     = "monotonic"
+	ruleRateLimit     = "rate_limit"
+)
+
+// policyEngine enforces the signing policies of the EOTS keys
+type policyEngine struct {
+	mu sync.Mutex
+	// anyKeyPolicy applies to the keys without their own policies, it is nil if not set
+	anyKeyPolicy *config.SigningPolicy
+	// fp pk hex -> policy
+	policies map[string]*config.SigningPolicy
+	// fp pk hex -> rate limiter of the key
+	limiters map[string]*rateLimiter
+}
+
+func newPolicyEngine(policies []*config.SigningPolicy) *policyEngine {
+	pe := &policyEngine{
+		policies: make(map[string]*config.SigningPolicy, len(policies)),
+		limiters: make(map[string]*rateLimiter),
+	}
+	for _, p := range policies {
+		if p.FpPk == config.AnyKey {
+			pe.anyKeyPolicy = p
+			continue
+		}
+		pe.policies[p.FpPk] = p
+	}
+
+	return pe
+}
+
+// policy returns the signing policy of the given key, or nil if there is no restriction
+func (pe *policyEngine) policy(fpPkHex string) *config.SigningPolicy {
+	if p, ok := pe.policies[fpPkHex]; ok {
+		return p
+	}
+
+	return pe.anyKeyPolicy
+}
+
+// allowRequest consumes a token from the rate limiter of the given key
+// NOTE: the caller must hold the lock
+func (pe *policyEngine) allowRequest(fpPkHex string, p *config.SigningPolicy) bool {
+	if p.RequestsPerSecond == 0 {
+		return true
+	}
+
+	rl, ok := pe.limiters[fpPkHex]
+	if !ok {
+		rl = newRateLimiter(p.RequestsPerSecond)
+		pe.limiters[fpPkHex] = rl
+	}
+
+	return rl.allow(time.Now())
+}
+
+// rateLimiter is a token bucket that is refilled at the given rate
+// and holds at most a second worth of tokens
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps float64) *rateLimiter {
+	burst := math.Max(1, math.Ceil(rps))
+	return &rateLimiter{
+		rate:   rps,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (rl *rateLimiter) allow(now time.Time) bool {
+	rl.tokens = math.Min(rl.burst, rl.tokens+now.Sub(rl.last).Seconds()*rl.rate)
+	rl.last = now
+	if rl.tokens < 1 {
+		return false
+	}
+	rl.tokens--
+
+	return true
+}
+
+// SetSigningPolicies replaces the signing policies enforced before signing
+// NOTE: it should be called before the manager starts serving requests
+func (lm *LocalEOTSManager) SetSigningPolicies(policies []*config.SigningPolicy) {
+	lm.policies = newPolicyEngine(policies)
+}
+
+// checkChainAndRate checks whether the key is allowed to sign for the given chain
+// and consumes a request from its rate limit
+func (lm *LocalEOTSManager) checkChainAndRate(fpPk []byte, chainID []byte) error {
+	if lm.policies == nil {
+		return nil
+	}
+	fpPkHex := hex.EncodeToString(fpPk)
+
+	lm.policies.mu.Lock()
+	defer lm.policies.mu.Unlock()
+
+	p := lm.policies.policy(fpPkHex)
+	if p == nil {
+		return nil
+	}
+
+	if len(p.ChainIDs) != 0 && !containsChainID(p.ChainIDs, string(chainID)) {
+		return lm.policyViolation(fpPkHex, ruleChainID,
+			fmt.Errorf("%w: %s", eotstypes.ErrChainNotAllowed, string(chainID)))
+	}
+
+	if !lm.policies.allowRequest(fpPkHex, p) {
+		return lm.policyViolation(fpPkHex, ruleRateLimit,
+			fmt.Errorf("%w: the limit is %v requests per second", eotstypes.ErrRateLimited, p.RequestsPerSecond))
+	}
+
+	return nil
+}
+
+// checkRate consumes a request from the rate limit of the key
+// It is used by the signing requests that are not bound to a chain
+func (lm *LocalEOTSManager) checkRate(fpPk []byte) error {
+	if lm.policies == nil {
+		return nil
+	}
+	fpPkHex := hex.EncodeToString(fpPk)
+
+	lm.policies.mu.Lock()
+	defer lm.policies.mu.Unlock()
+
+	p := lm.policies.policy(fpPkHex)
+	if p == nil {
+		return nil
+	}
+
+	if !lm.policies.allowRequest(fpPkHex, p) {
+		return lm.policyViolation(fpPkHex, ruleRateLimit,
+			fmt.Errorf("%w: the limit is %v requests per second", eotstypes.ErrRateLimited, p.RequestsPerSecond))
+	}
+
+	return nil
+}
+
+// checkHeights checks the heights to be newly signed against the height rules of the policy,
+// relative to the highest height that has been signed for the given chain
+// The heights are checked in ascending order, as if they were signed one by one
+func (lm *LocalEOTSManager) checkHeights(fpPk []byte, chainID []byte, heights []uint64) error {
+	if lm.policies == nil {
+		return nil
+	}
+	fpPkHex := hex.EncodeToString(fpPk)
+
+	lm.policies.mu.Lock()
+	p := lm.policies.policy(fpPkHex)
+	lm.policies.mu.Unlock()
+	if p == nil || (!p.Monotonic && p.MaxHeightJump == 0) {
+		return nil
+	}
+
+	lastHeight, err := lm.es.GetSignedWatermark(fpPk, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to get the last signed height: %w", err)
+	}
+
+	sorted := make([]uint64, len(heights))
+	copy(sorted, heights)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for _, h := range sorted {
+		// nothing has been signed for the chain yet
+		if lastHeight == 0 {
+			lastHeight = h
+			continue
+		}
+		if p.Monotonic && h < lastHeight {
+			return lm.policyViolation(fpPkHex, ruleMonotonic,
+				fmt.Errorf("%w: height %d, last signed height %d", eotstypes.ErrNonMonotonicHeight, h, lastHeight))
+		}
+		if p.MaxHeightJump != 0 && h > lastHeight && h-lastHeight > p.MaxHeightJump {
+			return lm.policyViolation(fpPkHex, ruleMaxHeightJump,
+				fmt.Errorf("%w: height %d, last signed height %d, max jump %d",
+					eotstypes.ErrHeightJumpTooLarge, h, lastHeight, p.MaxHeightJump))
+		}
+		if h > lastHeight {
+			lastHeight = h
+		}
+	}
+
+	return nil
+}
+
+func (lm *LocalEOTSManager) policyViolation(fpPkHex, rule string, err error) error {
+	lm.logger.Warn(
+		"refused the signing request by the signing policy",
+		zap.String("fp_pk", fpPkHex),
+		zap.String("rule", rule),
+		zap.Error(err),
+	)
+	lm.metrics.IncrementEotsFpPolicyViolationCounter(fpPkHex, rule)
+
+	return err
+}
+
+func containsChainID(chainIDs []string, chainID string) bool {
+	for _, id := range chainIDs {
+		if id == chainID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -20,11 +20,25 @@ const _ = grpc.SupportPackageIsVersion7
 
 const (
 	EOTSManager_Ping_FullMethodName                 = "/proto.EOTSManager/Ping"
+	EOTSManager_GetVersion_FullMethodName           = "/proto.EOTSManager/GetVersion"
 	EOTSManager_CreateKey_FullMethodName            = "/proto.EOTSManager/CreateKey"
+	EOTSManager_ImportMnemonic_FullMethodName       = "/proto.EOTSManager/ImportMnemonic"
+	EOTSManager_ExportMnemonic_FullMethodName       = "/proto.EOTSManager/ExportMnemonic"
+	EOTSManager_ListKeys_FullMethodName             = "/proto.EOTSManager/ListKeys"
+	EOTSManager_ShowKey_FullMethodName              = "/proto.EOTSManager/ShowKey"
+	EOTSManager_DeleteKey_FullMethodName            = "/proto.EOTSManager/DeleteKey"
 	EOTSManager_CreateMasterRandPair_FullMethodName = "/proto.EOTSManager/CreateMasterRandPair"
 	EOTSManager_KeyRecord_FullMethodName            = "/proto.EOTSManager/KeyRecord"
 	EOTSManager_SignEOTS_FullMethodName             = "/proto.EOTSManager/SignEOTS"
+	EOTSManager_SignEOTSBatch_FullMethodName        = "/proto.EOTSManager/SignEOTSBatch"
 	EOTSManager_SignSchnorrSig_FullMethodName       = "/proto.EOTSManager/SignSchnorrSig"
+	EOTSManager_SignPoP_FullMethodName              = "/proto.EOTSManager/SignPoP"
+	EOTSManager_SigningRecord_FullMethodName        = "/proto.EOTSManager/SigningRecord"
+	EOTSManager_Unlock_FullMethodName               = "/proto.EOTSManager/Unlock"
+	EOTSManager_Lock_FullMethodName                 = "/proto.EOTSManager/Lock"
+	EOTSManager_Halt_FullMethodName                 = "/proto.EOTSManager/Halt"
+	EOTSManager_Resume_FullMethodName               = "/proto.EOTSManager/Resume"
+	EOTSManager_Backup_FullMethodName               = "/proto.EOTSManager/Backup"
 )
 
 // EOTSManagerClient is the client API for EOTSManager service.
@@ -32,16 +46,53 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EOTSManagerClient interface {
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	// GetVersion returns the build info and the capabilities of the EOTS manager,
+	// which the clients check to refuse an incompatible EOTS manager
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
 	// CreateKey generates and saves an EOTS key
 	CreateKey(ctx context.Context, in *CreateKeyRequest, opts ...grpc.CallOption) (*CreateKeyResponse, error)
+	// ImportMnemonic imports an EOTS key from a mnemonic
+	ImportMnemonic(ctx context.Context, in *ImportMnemonicRequest, opts ...grpc.CallOption) (*ImportMnemonicResponse, error)
+	// ExportMnemonic exports the key mnemonic of an EOTS key
+	// It is refused unless the key export is allowed in the config
+	ExportMnemonic(ctx context.Context, in *ExportMnemonicRequest, opts ...grpc.CallOption) (*ExportMnemonicResponse, error)
+	// ListKeys returns all the EOTS keys
+	ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
+	// ShowKey returns an EOTS key and its master public randomness for a chain
+	ShowKey(ctx context.Context, in *ShowKeyRequest, opts ...grpc.CallOption) (*ShowKeyResponse, error)
+	// DeleteKey deletes an EOTS key
+	DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*DeleteKeyResponse, error)
 	// CreateMasterRandPair creates a pair of master secret/public randomness
 	CreateMasterRandPair(ctx context.Context, in *CreateMasterRandPairRequest, opts ...grpc.CallOption) (*CreateMasterRandPairResponse, error)
 	// KeyRecord returns the key record
+	// It is refused unless the key export is allowed in the config
 	KeyRecord(ctx context.Context, in *KeyRecordRequest, opts ...grpc.CallOption) (*KeyRecordResponse, error)
 	// SignEOTS signs an EOTS with the EOTS private key and the relevant randomness
 	SignEOTS(ctx context.Context, in *SignEOTSRequest, opts ...grpc.CallOption) (*SignEOTSResponse, error)
+	// SignEOTSBatch signs a batch of EOTS over messages at different heights
+	// with the same EOTS private key
+	SignEOTSBatch(ctx context.Context, in *SignEOTSBatchRequest, opts ...grpc.CallOption) (*SignEOTSBatchResponse, error)
 	// SignSchnorrSig signs a Schnorr sig with the EOTS private key
 	SignSchnorrSig(ctx context.Context, in *SignSchnorrSigRequest, opts ...grpc.CallOption) (*SignSchnorrSigResponse, error)
+	// SignPoP signs the BTC part of the proof-of-possession that binds
+	// the EOTS key to the chain key with the EOTS private key
+	SignPoP(ctx context.Context, in *SignPoPRequest, opts ...grpc.CallOption) (*SignPoPResponse, error)
+	// SigningRecord returns the record of the EOTS signature made at the given height
+	SigningRecord(ctx context.Context, in *SigningRecordRequest, opts ...grpc.CallOption) (*SigningRecordResponse, error)
+	// Unlock keeps the EOTS private key in memory so that the following
+	// requests can be served without the passphrase
+	Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error)
+	// Lock removes the unlocked EOTS private key from memory
+	Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error)
+	// Halt refuses all the signing requests until Resume is called,
+	// which persists across restarts
+	Halt(ctx context.Context, in *HaltRequest, opts ...grpc.CallOption) (*HaltResponse, error)
+	// Resume resumes the halted signing, which requires the passphrase
+	// of an EOTS key or the admin credential
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error)
+	// Backup writes an encrypted archive of the keyring records and the
+	// database to a path on the host of the EOTS manager
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error)
 }
 
 type eOTSManagerClient struct {
@@ -61,6 +112,15 @@ func (c *eOTSManagerClient) Ping(ctx context.Context, in *PingRequest, opts ...g
 	return out, nil
 }
 
+func (c *eOTSManagerClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
+	out := new(GetVersionResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_GetVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eOTSManagerClient) CreateKey(ctx context.Context, in *CreateKeyRequest, opts ...grpc.CallOption) (*CreateKeyResponse, error) {
 	out := new(CreateKeyResponse)
 	err := c.cc.Invoke(ctx, EOTSManager_CreateKey_FullMethodName, in, out, opts...)
@@ -70,6 +130,51 @@ func (c *eOTSManagerClient) CreateKey(ctx context.Context, in *CreateKeyRequest,
 	return out, nil
 }
 
+func (c *eOTSManagerClient) ImportMnemonic(ctx context.Context, in *ImportMnemonicRequest, opts ...grpc.CallOption) (*ImportMnemonicResponse, error) {
+	out := new(ImportMnemonicResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_ImportMnemonic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) ExportMnemonic(ctx context.Context, in *ExportMnemonicRequest, opts ...grpc.CallOption) (*ExportMnemonicResponse, error) {
+	out := new(ExportMnemonicResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_ExportMnemonic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_ListKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) ShowKey(ctx context.Context, in *ShowKeyRequest, opts ...grpc.CallOption) (*ShowKeyResponse, error) {
+	out := new(ShowKeyResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_ShowKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*DeleteKeyResponse, error) {
+	out := new(DeleteKeyResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_DeleteKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eOTSManagerClient) CreateMasterRandPair(ctx context.Context, in *CreateMasterRandPairRequest, opts ...grpc.CallOption) (*CreateMasterRandPairResponse, error) {
 	out := new(CreateMasterRandPairResponse)
 	err := c.cc.Invoke(ctx, EOTSManager_CreateMasterRandPair_FullMethodName, in, out, opts...)
@@ -97,6 +202,15 @@ func (c *eOTSManagerClient) SignEOTS(ctx context.Context, in *SignEOTSRequest, o
 	return out, nil
 }
 
+func (c *eOTSManagerClient) SignEOTSBatch(ctx context.Context, in *SignEOTSBatchRequest, opts ...grpc.CallOption) (*SignEOTSBatchResponse, error) {
+	out := new(SignEOTSBatchResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_SignEOTSBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eOTSManagerClient) SignSchnorrSig(ctx context.Context, in *SignSchnorrSigRequest, opts ...grpc.CallOption) (*SignSchnorrSigResponse, error) {
 	out := new(SignSchnorrSigResponse)
 	err := c.cc.Invoke(ctx, EOTSManager_SignSchnorrSig_FullMethodName, in, out, opts...)
@@ -106,21 +220,121 @@ func (c *eOTSManagerClient) SignSchnorrSig(ctx context.Context, in *SignSchnorrS
 	return out, nil
 }
 
+func (c *eOTSManagerClient) SignPoP(ctx context.Context, in *SignPoPRequest, opts ...grpc.CallOption) (*SignPoPResponse, error) {
+	out := new(SignPoPResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_SignPoP_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) SigningRecord(ctx context.Context, in *SigningRecordRequest, opts ...grpc.CallOption) (*SigningRecordResponse, error) {
+	out := new(SigningRecordResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_SigningRecord_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error) {
+	out := new(UnlockResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_Unlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error) {
+	out := new(LockResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_Lock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) Halt(ctx context.Context, in *HaltRequest, opts ...grpc.CallOption) (*HaltResponse, error) {
+	out := new(HaltResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_Halt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error) {
+	out := new(ResumeResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_Resume_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error) {
+	out := new(BackupResponse)
+	err := c.cc.Invoke(ctx, EOTSManager_Backup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EOTSManagerServer is the server API for EOTSManager service.
 // All implementations must embed UnimplementedEOTSManagerServer
 // for forward compatibility
 type EOTSManagerServer interface {
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	// GetVersion returns the build info and the capabilities of the EOTS manager,
+	// which the clients check to refuse an incompatible EOTS manager
+	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
 	// CreateKey generates and saves an EOTS key
 	CreateKey(context.Context, *CreateKeyRequest) (*CreateKeyResponse, error)
+	// ImportMnemonic imports an EOTS key from a mnemonic
+	ImportMnemonic(context.Context, *ImportMnemonicRequest) (*ImportMnemonicResponse, error)
+	// ExportMnemonic exports the key mnemonic of an EOTS key
+	// It is refused unless the key export is allowed in the config
+	ExportMnemonic(context.Context, *ExportMnemonicRequest) (*ExportMnemonicResponse, error)
+	// ListKeys returns all the EOTS keys
+	ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error)
+	// ShowKey returns an EOTS key and its master public randomness for a chain
+	ShowKey(context.Context, *ShowKeyRequest) (*ShowKeyResponse, error)
+	// DeleteKey deletes an EOTS key
+	DeleteKey(context.Context, *DeleteKeyRequest) (*DeleteKeyResponse, error)
 	// CreateMasterRandPair creates a pair of master secret/public randomness
 	CreateMasterRandPair(context.Context, *CreateMasterRandPairRequest) (*CreateMasterRandPairResponse, error)
 	// KeyRecord returns the key record
+	// It is refused unless the key export is allowed in the config
 	KeyRecord(context.Context, *KeyRecordRequest) (*KeyRecordResponse, error)
 	// SignEOTS signs an EOTS with the EOTS private key and the relevant randomness
 	SignEOTS(context.Context, *SignEOTSRequest) (*SignEOTSResponse, error)
+	// SignEOTSBatch signs a batch of EOTS over messages at different heights
+	// with the same EOTS private key
+	SignEOTSBatch(context.Context, *SignEOTSBatchRequest) (*SignEOTSBatchResponse, error)
 	// SignSchnorrSig signs a Schnorr sig with the EOTS private key
 	SignSchnorrSig(context.Context, *SignSchnorrSigRequest) (*SignSchnorrSigResponse, error)
+	// SignPoP signs the BTC part of the proof-of-possession that binds
+	// the EOTS key to the chain key with the EOTS private key
+	SignPoP(context.Context, *SignPoPRequest) (*SignPoPResponse, error)
+	// SigningRecord returns the record of the EOTS signature made at the given height
+	SigningRecord(context.Context, *SigningRecordRequest) (*SigningRecordResponse, error)
+	// Unlock keeps the EOTS private key in memory so that the following
+	// requests can be served without the passphrase
+	Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error)
+	// Lock removes the unlocked EOTS private key from memory
+	Lock(context.Context, *LockRequest) (*LockResponse, error)
+	// Halt refuses all the signing requests until Resume is called,
+	// which persists across restarts
+	Halt(context.Context, *HaltRequest) (*HaltResponse, error)
+	// Resume resumes the halted signing, which requires the passphrase
+	// of an EOTS key or the admin credential
+	Resume(context.Context, *ResumeRequest) (*ResumeResponse, error)
+	// Backup writes an encrypted archive of the keyring records and the
+	// database to a path on the host of the EOTS manager
+	Backup(context.Context, *BackupRequest) (*BackupResponse, error)
 	mustEmbedUnimplementedEOTSManagerServer()
 }
 
@@ -131,9 +345,27 @@ type UnimplementedEOTSManagerServer struct {
 func (UnimplementedEOTSManagerServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
+func (UnimplementedEOTSManagerServer) GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
 func (UnimplementedEOTSManagerServer) CreateKey(context.Context, *CreateKeyRequest) (*CreateKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateKey not implemented")
 }
+func (UnimplementedEOTSManagerServer) ImportMnemonic(context.Context, *ImportMnemonicRequest) (*ImportMnemonicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMnemonic not implemented")
+}
+func (UnimplementedEOTSManagerServer) ExportMnemonic(context.Context, *ExportMnemonicRequest) (*ExportMnemonicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMnemonic not implemented")
+}
+func (UnimplementedEOTSManagerServer) ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedEOTSManagerServer) ShowKey(context.Context, *ShowKeyRequest) (*ShowKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShowKey not implemented")
+}
+func (UnimplementedEOTSManagerServer) DeleteKey(context.Context, *DeleteKeyRequest) (*DeleteKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteKey not implemented")
+}
 func (UnimplementedEOTSManagerServer) CreateMasterRandPair(context.Context, *CreateMasterRandPairRequest) (*CreateMasterRandPairResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateMasterRandPair not implemented")
 }
@@ -143,9 +375,33 @@ func (UnimplementedEOTSManagerServer) KeyRecord(context.Context, *KeyRecordReque
 func (UnimplementedEOTSManagerServer) SignEOTS(context.Context, *SignEOTSRequest) (*SignEOTSResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignEOTS not implemented")
 }
+func (UnimplementedEOTSManagerServer) SignEOTSBatch(context.Context, *SignEOTSBatchRequest) (*SignEOTSBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignEOTSBatch not implemented")
+}
 func (UnimplementedEOTSManagerServer) SignSchnorrSig(context.Context, *SignSchnorrSigRequest) (*SignSchnorrSigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignSchnorrSig not implemented")
 }
+func (UnimplementedEOTSManagerServer) SignPoP(context.Context, *SignPoPRequest) (*SignPoPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignPoP not implemented")
+}
+func (UnimplementedEOTSManagerServer) SigningRecord(context.Context, *SigningRecordRequest) (*SigningRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SigningRecord not implemented")
+}
+func (UnimplementedEOTSManagerServer) Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlock not implemented")
+}
+func (UnimplementedEOTSManagerServer) Lock(context.Context, *LockRequest) (*LockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lock not implemented")
+}
+func (UnimplementedEOTSManagerServer) Halt(context.Context, *HaltRequest) (*HaltResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Halt not implemented")
+}
+func (UnimplementedEOTSManagerServer) Resume(context.Context, *ResumeRequest) (*ResumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedEOTSManagerServer) Backup(context.Context, *BackupRequest) (*BackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
 func (UnimplementedEOTSManagerServer) mustEmbedUnimplementedEOTSManagerServer() {}
 
 // UnsafeEOTSManagerServer may be embedded to opt out of forward compatibility for this service.
@@ -177,6 +433,24 @@ func _EOTSManager_Ping_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EOTSManager_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_GetVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EOTSManager_CreateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateKeyRequest)
 	if err := dec(in); err != nil {
@@ -195,6 +469,96 @@ func _EOTSManager_CreateKey_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EOTSManager_ImportMnemonic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMnemonicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).ImportMnemonic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_ImportMnemonic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).ImportMnemonic(ctx, req.(*ImportMnemonicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_ExportMnemonic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportMnemonicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).ExportMnemonic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_ExportMnemonic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).ExportMnemonic(ctx, req.(*ExportMnemonicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_ListKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).ListKeys(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_ShowKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShowKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).ShowKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_ShowKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).ShowKey(ctx, req.(*ShowKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_DeleteKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).DeleteKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_DeleteKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).DeleteKey(ctx, req.(*DeleteKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EOTSManager_CreateMasterRandPair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateMasterRandPairRequest)
 	if err := dec(in); err != nil {
@@ -249,6 +613,24 @@ func _EOTSManager_SignEOTS_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EOTSManager_SignEOTSBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignEOTSBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).SignEOTSBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_SignEOTSBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).SignEOTSBatch(ctx, req.(*SignEOTSBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EOTSManager_SignSchnorrSig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SignSchnorrSigRequest)
 	if err := dec(in); err != nil {
@@ -267,6 +649,132 @@ func _EOTSManager_SignSchnorrSig_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EOTSManager_SignPoP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignPoPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).SignPoP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_SignPoP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).SignPoP(ctx, req.(*SignPoPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_SigningRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SigningRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).SigningRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_SigningRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).SigningRecord(ctx, req.(*SigningRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_Unlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).Unlock(ctx, req.(*UnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).Lock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_Lock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).Lock(ctx, req.(*LockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_Halt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HaltRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).Halt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_Halt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).Halt(ctx, req.(*HaltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_Resume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).Backup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EOTSManager_Backup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).Backup(ctx, req.(*BackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EOTSManager_ServiceDesc is the grpc.ServiceDesc for EOTSManager service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -278,10 +786,34 @@ var EOTSManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Ping",
 			Handler:    _EOTSManager_Ping_Handler,
 		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _EOTSManager_GetVersion_Handler,
+		},
 		{
 			MethodName: "CreateKey",
 			Handler:    _EOTSManager_CreateKey_Handler,
 		},
+		{
+			MethodName: "ImportMnemonic",
+			Handler:    _EOTSManager_ImportMnemonic_Handler,
+		},
+		{
+			MethodName: "ExportMnemonic",
+			Handler:    _EOTSManager_ExportMnemonic_Handler,
+		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _EOTSManager_ListKeys_Handler,
+		},
+		{
+			MethodName: "ShowKey",
+			Handler:    _EOTSManager_ShowKey_Handler,
+		},
+		{
+			MethodName: "DeleteKey",
+			Handler:    _EOTSManager_DeleteKey_Handler,
+		},
 		{
 			MethodName: "CreateMasterRandPair",
 			Handler:    _EOTSManager_CreateMasterRandPair_Handler,
@@ -294,10 +826,42 @@ var EOTSManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SignEOTS",
 			Handler:    _EOTSManager_SignEOTS_Handler,
 		},
+		{
+			MethodName: "SignEOTSBatch",
+			Handler:    _EOTSManager_SignEOTSBatch_Handler,
+		},
 		{
 			MethodName: "SignSchnorrSig",
 			Handler:    _EOTSManager_SignSchnorrSig_Handler,
 		},
+		{
+			MethodName: "SignPoP",
+			Handler:    _EOTSManager_SignPoP_Handler,
+		},
+		{
+			MethodName: "SigningRecord",
+			Handler:    _EOTSManager_SigningRecord_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _EOTSManager_Unlock_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _EOTSManager_Lock_Handler,
+		},
+		{
+			MethodName: "Halt",
+			Handler:    _EOTSManager_Halt_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _EOTSManager_Resume_Handler,
+		},
+		{
+			MethodName: "Backup",
+			Handler:    _EOTSManager_Backup_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "eotsmanager.proto",
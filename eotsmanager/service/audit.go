@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/audit"
+)
+
+// audit appends the signing request and its result to the audit log
+// The error is returned if the request cannot be recorded, in which case
+// the signature should not be released
+func (r *rpcServer) audit(
+	ctx context.Context,
+	method string,
+	fpPk, chainID []byte,
+	height uint64,
+	msg []byte,
+	signErr error,
+) error {
+	if r.auditLog == nil {
+		return nil
+	}
+
+	entry := audit.NewEntry(callerFromContext(ctx), method, fpPk, chainID, height, msg, signErr)
+	if err := r.auditLog.Append(entry); err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("failed to record the request in the audit log: %v", err))
+	}
+
+	return nil
+}
+
+// callerFromContext identifies the caller by its address, prefixed by
+// the common name of its certificate if the client certificate is verified
+func callerFromContext(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+
+	caller := p.Addr.String()
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		chains := tlsInfo.State.VerifiedChains
+		if len(chains) > 0 && len(chains[0]) > 0 {
+			caller = chains[0][0].Subject.CommonName + "@" + caller
+		}
+	}
+
+	return caller
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+)
+
+// listenRPC listens on the RPC listener in the config, which is either a TCP address
+// or a unix domain socket whose file mode and peer UIDs are restricted by the config
+func listenRPC(cfg *config.Config, logger *zap.Logger) (net.Listener, error) {
+	network, address, err := config.ParseRPCAddress(cfg.RpcListener)
+	if err != nil {
+		return nil, err
+	}
+	if network != "unix" {
+		return net.Listen(network, address)
+	}
+
+	mode, err := cfg.SocketMode()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := removeStaleSocket(address); err != nil {
+		return nil, err
+	}
+
+	lis, err := listenUnixSocket(address, fs.FileMode(mode))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.RpcSocketUIDs) == 0 {
+		return lis, nil
+	}
+
+	allowedUIDs := make(map[uint32]struct{}, len(cfg.RpcSocketUIDs))
+	for _, uid := range cfg.RpcSocketUIDs {
+		allowedUIDs[uid] = struct{}{}
+	}
+
+	return &peerCredListener{
+		Listener:    lis,
+		allowedUIDs: allowedUIDs,
+		logger:      logger,
+	}, nil
+}
+
+// removeStaleSocket removes the socket file left by a daemon that did not shut down cleanly
+// It fails if the file is not a socket or another process is still listening on it
+func removeStaleSocket(socketPath string) error {
+	fi, err := os.Lstat(socketPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%s exists and is not a unix socket", socketPath)
+	}
+
+	if conn, err := net.DialTimeout("unix", socketPath, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("the unix socket %s is in use", socketPath)
+	}
+
+	return os.Remove(socketPath)
+}
+
+// peerCredListener only accepts the connections from the processes of the allowed UIDs
+type peerCredListener struct {
+	net.Listener
+
+	allowedUIDs map[uint32]struct{}
+	logger      *zap.Logger
+}
+
+func (l *peerCredListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		uid, err := peerUID(conn)
+		if err != nil {
+			l.logger.Warn("refused the RPC connection with unknown peer credentials", zap.Error(err))
+			conn.Close()
+			continue
+		}
+		if _, ok := l.allowedUIDs[uid]; !ok {
+			l.logger.Warn("refused the RPC connection from a UID that is not allowed", zap.Uint32("uid", uid))
+			conn.Close()
+			continue
+		}
+
+		return conn, nil
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !unix
+
+package service
+
+import (
+	"io/fs"
+	"net"
+	"os"
+)
+
+// listenUnixSocket listens on the unix socket and sets its mode
+func listenUnixSocket(socketPath string, mode fs.FileMode) (net.Listener, error) {
+	lis, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(socketPath, mode); err != nil {
+		lis.Close()
+		return nil, err
+	}
+
+	return lis, nil
+}
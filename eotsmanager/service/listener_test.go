@@ -0,0 +1,87 @@
+package service
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+)
+
+// TestListenUnixSocket tests that the unix socket is created with the configured
+// mode and only accepts the connections from the allowed UIDs
+func TestListenUnixSocket(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_PEERCRED is only supported on Linux")
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "eotsd.sock")
+	uid := uint32(os.Getuid())
+
+	listen := func(allowedUIDs ...uint32) net.Listener {
+		cfg := config.DefaultConfigWithHomePath(t.TempDir())
+		cfg.RpcListener = config.UnixSocketScheme + socketPath
+		cfg.RpcSocketUIDs = allowedUIDs
+		require.NoError(t, cfg.Validate())
+
+		lis, err := listenRPC(cfg, zap.NewNop())
+		require.NoError(t, err)
+
+		fi, err := os.Stat(socketPath)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+		return lis
+	}
+
+	// the connection from the allowed UID is accepted
+	lis := listen(uid)
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	select {
+	case serverConn := <-accepted:
+		serverConn.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection from the allowed UID is not accepted")
+	}
+	conn.Close()
+
+	// the socket is in use
+	_, err = listenRPC(&config.Config{
+		RpcListener:   config.UnixSocketScheme + socketPath,
+		RpcSocketMode: "0600",
+	}, zap.NewNop())
+	require.Error(t, err)
+	require.NoError(t, lis.Close())
+
+	// the connection from any other UID is closed without being accepted
+	lis = listen(uid + 1)
+	defer lis.Close()
+	go func() {
+		conn, err := lis.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	conn, err = net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+	require.Empty(t, accepted)
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package service
+
+import (
+	"io/fs"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenUnixSocket listens on the unix socket, which is created with the
+// given mode, so that it is never accessible with looser permissions
+func listenUnixSocket(socketPath string, mode fs.FileMode) (net.Listener, error) {
+	// the umask is process-wide, but the daemon does not create other files meanwhile
+	oldMask := syscall.Umask(int(^mode & 0777))
+	lis, err := net.Listen("unix", socketPath)
+	syscall.Umask(oldMask)
+	if err != nil {
+		return nil, err
+	}
+
+	// the umask could only have removed permissions
+	if err := os.Chmod(socketPath, mode); err != nil {
+		lis.Close()
+		return nil, err
+	}
+
+	return lis, nil
+}
@@ -0,0 +1,39 @@
+//go:build linux
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// peerUID returns the UID of the process on the other end of the unix socket
+// connection, which is read with SO_PEERCRED
+func peerUID(conn net.Conn) (uint32, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, fmt.Errorf("not a unix socket connection")
+	}
+
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		cred    *syscall.Ucred
+		credErr error
+	)
+	err = rawConn.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+
+	return cred.Uid, nil
+}
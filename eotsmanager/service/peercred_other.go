@@ -0,0 +1,14 @@
+//go:build !linux
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// peerUID is only supported on Linux, the config refuses the allowed UIDs elsewhere
+func peerUID(_ net.Conn) (uint32, error) {
+	return 0, fmt.Errorf("SO_PEERCRED is not supported on %s", runtime.GOOS)
+}
@@ -2,28 +2,48 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/audit"
 	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+	"github.com/babylonchain/finality-provider/eotsmanager/store"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
+	"github.com/babylonchain/finality-provider/version"
 )
 
+var errKeyExportDisabled = status.Error(codes.PermissionDenied,
+	"exporting the EOTS private key is disabled, set allow-key-export in the config to enable it")
+
 // rpcServer is the main RPC server for the EOTS daemon that handles
 // gRPC incoming requests.
 type rpcServer struct {
 	proto.UnimplementedEOTSManagerServer
 
 	em eotsmanager.EOTSManager
+
+	// allowKeyExport indicates whether KeyRecord, ExportMnemonic and Backup can
+	// export the EOTS private keys
+	allowKeyExport bool
+
+	// auditLog records every signing request, it is nil if auditing is disabled
+	auditLog *audit.Log
 }
 
 // newRPCServer creates a new RPC sever from the set of input dependencies.
 func newRPCServer(
 	em eotsmanager.EOTSManager,
+	allowKeyExport bool,
 ) *rpcServer {
 
 	return &rpcServer{
-		em: em,
+		em:             em,
+		allowKeyExport: allowKeyExport,
 	}
 }
 
@@ -39,6 +59,23 @@ func (r *rpcServer) Ping(ctx context.Context, req *proto.PingRequest) (*proto.Pi
 	return &proto.PingResponse{}, nil
 }
 
+// GetVersion returns the build info and the capabilities of the EOTS manager
+func (r *rpcServer) GetVersion(ctx context.Context, req *proto.GetVersionRequest) (*proto.GetVersionResponse, error) {
+	methods := make([]string, len(proto.EOTSManager_ServiceDesc.Methods))
+	for i, m := range proto.EOTSManager_ServiceDesc.Methods {
+		methods[i] = m.MethodName
+	}
+
+	return &proto.GetVersionResponse{
+		Version:         version.Version(),
+		CommitHash:      version.CommitHash,
+		GoVersion:       version.GoVersion,
+		ProtocolVersion: types.ProtocolVersion,
+		RpcMethods:      methods,
+		RandSchemes:     fpkeyring.RandSchemes(),
+	}, nil
+}
+
 // CreateKey generates and saves an EOTS key
 func (r *rpcServer) CreateKey(ctx context.Context, req *proto.CreateKeyRequest) (
 	*proto.CreateKeyResponse, error) {
@@ -52,10 +89,94 @@ func (r *rpcServer) CreateKey(ctx context.Context, req *proto.CreateKeyRequest)
 	return &proto.CreateKeyResponse{Pk: pk}, nil
 }
 
+// ImportMnemonic imports an EOTS key from a mnemonic
+func (r *rpcServer) ImportMnemonic(ctx context.Context, req *proto.ImportMnemonicRequest) (
+	*proto.ImportMnemonicResponse, error) {
+
+	pk, err := r.em.ImportMnemonic(req.Name, req.Passphrase, req.HdPath, req.Mnemonic, req.KeyMnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ImportMnemonicResponse{Pk: pk}, nil
+}
+
+// ExportMnemonic exports the key mnemonic of an EOTS key
+func (r *rpcServer) ExportMnemonic(ctx context.Context, req *proto.ExportMnemonicRequest) (
+	*proto.ExportMnemonicResponse, error) {
+
+	if !r.allowKeyExport {
+		return nil, errKeyExportDisabled
+	}
+
+	keyMnemonic, err := r.em.ExportMnemonic(req.Uid, req.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ExportMnemonicResponse{KeyMnemonic: keyMnemonic}, nil
+}
+
+// ListKeys returns all the EOTS keys
+func (r *rpcServer) ListKeys(ctx context.Context, req *proto.ListKeysRequest) (
+	*proto.ListKeysResponse, error) {
+
+	keys, err := r.em.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.ListKeysResponse{Keys: make([]*proto.KeyInfo, len(keys))}
+	for i, k := range keys {
+		res.Keys[i] = toProtoKeyInfo(k)
+	}
+
+	return res, nil
+}
+
+// ShowKey returns an EOTS key and its master public randomness for a chain
+func (r *rpcServer) ShowKey(ctx context.Context, req *proto.ShowKeyRequest) (
+	*proto.ShowKeyResponse, error) {
+
+	key, err := r.em.ShowKey(req.Uid, req.ChainId, req.Passphrase)
+	if errors.Is(err, store.ErrEOTSKeyNameNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ShowKeyResponse{Key: toProtoKeyInfo(key)}, nil
+}
+
+// DeleteKey deletes an EOTS key
+func (r *rpcServer) DeleteKey(ctx context.Context, req *proto.DeleteKeyRequest) (
+	*proto.DeleteKeyResponse, error) {
+
+	err := r.em.DeleteKey(req.Uid, req.Passphrase)
+	if errors.Is(err, store.ErrEOTSKeyNameNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteKeyResponse{}, nil
+}
+
+func toProtoKeyInfo(k *types.KeyInfo) *proto.KeyInfo {
+	return &proto.KeyInfo{
+		Name:          k.Name,
+		Pk:            k.FpPk,
+		MasterPubRand: k.MasterPubRand,
+		RandScheme:    k.RandScheme,
+	}
+}
+
 // CreateMasterRandPair returns a list of Schnorr randomness pairs
 func (r *rpcServer) CreateMasterRandPair(ctx context.Context, req *proto.CreateMasterRandPairRequest) (*proto.CreateMasterRandPairResponse, error) {
 
-	mpr, err := r.em.CreateMasterRandPair(req.Uid, req.ChainId, req.Passphrase)
+	mpr, err := r.em.CreateMasterRandPair(req.Uid, req.ChainId, req.RandScheme, req.Passphrase)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +190,10 @@ func (r *rpcServer) CreateMasterRandPair(ctx context.Context, req *proto.CreateM
 func (r *rpcServer) KeyRecord(ctx context.Context, req *proto.KeyRecordRequest) (
 	*proto.KeyRecordResponse, error) {
 
+	if !r.allowKeyExport {
+		return nil, errKeyExportDisabled
+	}
+
 	record, err := r.em.KeyRecord(req.Uid, req.Passphrase)
 	if err != nil {
 		return nil, err
@@ -87,8 +212,11 @@ func (r *rpcServer) SignEOTS(ctx context.Context, req *proto.SignEOTSRequest) (
 	*proto.SignEOTSResponse, error) {
 
 	sig, err := r.em.SignEOTS(req.Uid, req.ChainId, req.Msg, req.Height, req.Passphrase)
+	if auditErr := r.audit(ctx, "SignEOTS", req.Uid, req.ChainId, req.Height, req.Msg, err); auditErr != nil {
+		return nil, auditErr
+	}
 	if err != nil {
-		return nil, err
+		return nil, toSigningStatusErr(err)
 	}
 
 	sigBytes := sig.Bytes()
@@ -96,14 +224,167 @@ func (r *rpcServer) SignEOTS(ctx context.Context, req *proto.SignEOTSRequest) (
 	return &proto.SignEOTSResponse{Sig: sigBytes[:]}, nil
 }
 
+// SignEOTSBatch signs a batch of EOTS with the EOTS private key and the relevant randomness
+func (r *rpcServer) SignEOTSBatch(ctx context.Context, req *proto.SignEOTSBatchRequest) (
+	*proto.SignEOTSBatchResponse, error) {
+
+	msgs := make([]*types.HeightMsg, len(req.Msgs))
+	for i, m := range req.Msgs {
+		msgs[i] = &types.HeightMsg{Height: m.Height, Msg: m.Msg}
+	}
+
+	sigs, err := r.em.SignEOTSBatch(req.Uid, req.ChainId, msgs, req.Passphrase)
+	for _, m := range msgs {
+		if auditErr := r.audit(ctx, "SignEOTSBatch", req.Uid, req.ChainId, m.Height, m.Msg, err); auditErr != nil {
+			return nil, auditErr
+		}
+	}
+	if err != nil {
+		return nil, toSigningStatusErr(err)
+	}
+
+	sigsBytes := make([][]byte, len(sigs))
+	for i, sig := range sigs {
+		sigBytes := sig.Bytes()
+		sigsBytes[i] = sigBytes[:]
+	}
+
+	return &proto.SignEOTSBatchResponse{Sigs: sigsBytes}, nil
+}
+
 // SignSchnorrSig signs a Schnorr sig with the EOTS private key
 func (r *rpcServer) SignSchnorrSig(ctx context.Context, req *proto.SignSchnorrSigRequest) (
 	*proto.SignSchnorrSigResponse, error) {
 
 	sig, err := r.em.SignSchnorrSig(req.Uid, req.Msg, req.Passphrase)
+	if auditErr := r.audit(ctx, "SignSchnorrSig", req.Uid, nil, 0, req.Msg, err); auditErr != nil {
+		return nil, auditErr
+	}
 	if err != nil {
-		return nil, err
+		return nil, toSigningStatusErr(err)
 	}
 
 	return &proto.SignSchnorrSigResponse{Sig: sig.Serialize()}, nil
 }
+
+// SignPoP signs the BTC part of the proof-of-possession with the EOTS private key
+func (r *rpcServer) SignPoP(ctx context.Context, req *proto.SignPoPRequest) (
+	*proto.SignPoPResponse, error) {
+
+	sig, err := r.em.SignPoP(req.Uid, req.ChainPk, req.ChainSig, req.Passphrase)
+	if auditErr := r.audit(ctx, "SignPoP", req.Uid, nil, 0, req.ChainSig, err); auditErr != nil {
+		return nil, auditErr
+	}
+	if err != nil {
+		return nil, toSigningStatusErr(err)
+	}
+
+	return &proto.SignPoPResponse{BtcSig: sig.Serialize()}, nil
+}
+
+// toSigningStatusErr converts the errors of the refused signing requests
+// to gRPC status errors, so that the client can tell them from other failures
+func toSigningStatusErr(err error) error {
+	switch {
+	case errors.Is(err, types.ErrDoubleSign):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, types.ErrSigningHalted):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, types.ErrRateLimited):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, types.ErrPolicyViolation):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, types.ErrInvalidChainSig):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
+
+// SigningRecord returns the record of the EOTS signature made at the given height
+func (r *rpcServer) SigningRecord(ctx context.Context, req *proto.SigningRecordRequest) (
+	*proto.SigningRecordResponse, error) {
+
+	record, err := r.em.SigningRecord(req.Uid, req.ChainId, req.Height)
+	if errors.Is(err, store.ErrSigningRecordNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SigningRecordResponse{
+		Record: &proto.SigningRecord{
+			FpPk:      record.FpPk,
+			ChainId:   record.ChainID,
+			Height:    record.Height,
+			MsgHash:   record.MsgHash,
+			Sig:       record.Sig,
+			Timestamp: record.Timestamp,
+		},
+	}, nil
+}
+
+// Unlock keeps the EOTS private key in memory
+func (r *rpcServer) Unlock(ctx context.Context, req *proto.UnlockRequest) (
+	*proto.UnlockResponse, error) {
+
+	if err := r.em.Unlock(req.Uid, req.Passphrase); err != nil {
+		return nil, err
+	}
+
+	return &proto.UnlockResponse{}, nil
+}
+
+// Lock removes the unlocked EOTS private key from memory
+func (r *rpcServer) Lock(ctx context.Context, req *proto.LockRequest) (
+	*proto.LockResponse, error) {
+
+	if err := r.em.Lock(req.Uid); err != nil {
+		return nil, err
+	}
+
+	return &proto.LockResponse{}, nil
+}
+
+// Halt refuses all the signing requests until Resume is called
+func (r *rpcServer) Halt(ctx context.Context, req *proto.HaltRequest) (
+	*proto.HaltResponse, error) {
+
+	if err := r.em.Halt(req.Reason); err != nil {
+		return nil, err
+	}
+
+	return &proto.HaltResponse{}, nil
+}
+
+// Resume resumes the halted signing
+func (r *rpcServer) Resume(ctx context.Context, req *proto.ResumeRequest) (
+	*proto.ResumeResponse, error) {
+
+	err := r.em.Resume(req.Uid, req.Passphrase, req.AdminCredential)
+	if errors.Is(err, types.ErrResumeUnauthorized) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ResumeResponse{}, nil
+}
+
+// Backup writes an encrypted archive of the keyring records and the database
+// As the archive contains the keys, it is only allowed if the key export is enabled
+func (r *rpcServer) Backup(ctx context.Context, req *proto.BackupRequest) (
+	*proto.BackupResponse, error) {
+
+	if !r.allowKeyExport {
+		return nil, errKeyExportDisabled
+	}
+
+	if err := r.em.Backup(req.Path, req.Passphrase); err != nil {
+		return nil, err
+	}
+
+	return &proto.BackupResponse{}, nil
+}
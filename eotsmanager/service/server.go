@@ -8,13 +8,16 @@ import (
 	"sync/atomic"
 
 	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/rpcinterceptor"
 
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/signal"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/audit"
 	"github.com/babylonchain/finality-provider/eotsmanager/config"
 )
 
@@ -39,7 +42,7 @@ func NewEOTSManagerServer(cfg *config.Config, l *zap.Logger, em eotsmanager.EOTS
 	return &Server{
 		cfg:         cfg,
 		logger:      l,
-		rpcServer:   newRPCServer(em),
+		rpcServer:   newRPCServer(em, cfg.AllowKeyExport),
 		db:          db,
 		interceptor: sig,
 		quit:        make(chan struct{}, 1),
@@ -65,6 +68,9 @@ func (s *Server) RunUntilShutdown() error {
 	}()
 
 	defer func() {
+		if err := s.rpcServer.em.Close(); err != nil {
+			s.logger.Error("failed to close the EOTS manager", zap.Error(err))
+		}
 		s.logger.Info("Closing database...")
 		s.db.Close()
 		s.logger.Info("Database closed")
@@ -72,16 +78,38 @@ func (s *Server) RunUntilShutdown() error {
 		s.logger.Info("Metrics server stopped")
 	}()
 
+	auditLog, err := audit.Open(s.cfg.AuditLogFile())
+	if err != nil {
+		return fmt.Errorf("failed to open the audit log: %w", err)
+	}
+	defer auditLog.Close()
+	s.rpcServer.auditLog = auditLog
+
 	listenAddr := s.cfg.RpcListener
 	// we create listeners from the RPCListeners defined
 	// in the config.
-	lis, err := net.Listen("tcp", listenAddr)
+	lis, err := listenRPC(s.cfg, s.logger)
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
 	}
 	defer lis.Close()
 
-	grpcServer := grpc.NewServer()
+	serverOpts := []grpc.ServerOption{
+		rpcinterceptor.ServerOption("eotsd", s.logger, metrics.NewRPCMetrics()),
+	}
+	if s.cfg.TLS.Enabled() {
+		tlsCfg, err := s.cfg.TLS.ServerTLSConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load the TLS config: %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+		s.logger.Info("RPC server serves over TLS",
+			zap.Bool("client_cert_required", tlsCfg.ClientCAs != nil))
+	} else {
+		s.logger.Warn("RPC server serves without TLS, anyone who can reach it can request signatures")
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	defer grpcServer.Stop()
 
 	if err := s.rpcServer.RegisterWithGrpcServer(grpcServer); err != nil {
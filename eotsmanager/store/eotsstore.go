@@ -11,30 +11,32 @@ import (
 
 var (
 	eotsBucketName = []byte("fpKeyNames")
+	// mapping pk -> chain id -> height -> proto.SigningRecord
+	signingRecordBucketName = []byte("signingRecords")
+	// mapping pk -> chain id -> the highest signed height
+	signedWatermarkBucketName = []byte("signedWatermarks")
+	// mapping pk -> chain id -> the randomness derivation scheme
+	randSchemeBucketName = []byte("randSchemes")
+	// mapping haltStateKey -> the halt state, which is absent unless signing is halted
+	haltBucketName = []byte("halt")
 )
 
+// EOTSKeyName is the name of the keyring record of an EOTS key
+type EOTSKeyName struct {
+	FpPk    []byte
+	KeyName string
+}
+
 type EOTSStore struct {
 	db kvdb.Backend
 }
 
 func NewEOTSStore(db kvdb.Backend) (*EOTSStore, error) {
-	s := &EOTSStore{db}
-	if err := s.initBuckets(); err != nil {
-		return nil, err
+	if _, err := Migrate(db, false); err != nil {
+		return nil, fmt.Errorf("failed to migrate the database: %w", err)
 	}
 
-	return s, nil
-}
-
-func (s *EOTSStore) initBuckets() error {
-	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
-		_, err := tx.CreateTopLevelBucket(eotsBucketName)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	})
+	return &EOTSStore{db}, nil
 }
 
 func (s *EOTSStore) AddEOTSKeyName(
@@ -93,3 +95,60 @@ func (s *EOTSStore) GetEOTSKeyName(pk []byte) (string, error) {
 
 	return keyName, nil
 }
+
+// GetAllEOTSKeyNames returns the names of all the EOTS keys ordered by their public keys
+func (s *EOTSStore) GetAllEOTSKeyNames() ([]*EOTSKeyName, error) {
+	var keyNames []*EOTSKeyName
+	err := s.db.View(func(tx kvdb.RTx) error {
+		var err error
+		keyNames, err = getAllEOTSKeyNames(tx)
+		return err
+	}, func() {
+		keyNames = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keyNames, nil
+}
+
+func getAllEOTSKeyNames(tx kvdb.RTx) ([]*EOTSKeyName, error) {
+	eotsBucket := tx.ReadBucket(eotsBucketName)
+	if eotsBucket == nil {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	var keyNames []*EOTSKeyName
+	err := eotsBucket.ForEach(func(k, v []byte) error {
+		keyNames = append(keyNames, &EOTSKeyName{
+			FpPk:    append([]byte{}, k...),
+			KeyName: string(v),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keyNames, nil
+}
+
+// DeleteEOTSKeyName removes the key name of the given EOTS public key
+// The signing records of the key are kept to protect it from double signing
+// if it is imported again
+func (s *EOTSStore) DeleteEOTSKeyName(pk []byte) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		eotsBucket := tx.ReadWriteBucket(eotsBucketName)
+		if eotsBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		if eotsBucket.Get(pk) == nil {
+			return ErrEOTSKeyNameNotFound
+		}
+
+		return eotsBucket.Delete(pk)
+	})
+}
@@ -61,5 +61,22 @@ func FuzzEOTSStore(f *testing.F) {
 		require.NoError(t, err)
 		_, err = vs.GetEOTSKeyName(schnorr.SerializePubKey(randomBtcPk))
 		require.ErrorIs(t, err, store.ErrEOTSKeyNameNotFound)
+
+		keyNames, err := vs.GetAllEOTSKeyNames()
+		require.NoError(t, err)
+		require.Len(t, keyNames, 1)
+		require.Equal(t, schnorr.SerializePubKey(btcPk), keyNames[0].FpPk)
+		require.Equal(t, expectedKeyName, keyNames[0].KeyName)
+
+		// delete the key name
+		err = vs.DeleteEOTSKeyName(schnorr.SerializePubKey(btcPk))
+		require.NoError(t, err)
+		_, err = vs.GetEOTSKeyName(schnorr.SerializePubKey(btcPk))
+		require.ErrorIs(t, err, store.ErrEOTSKeyNameNotFound)
+		err = vs.DeleteEOTSKeyName(schnorr.SerializePubKey(btcPk))
+		require.ErrorIs(t, err, store.ErrEOTSKeyNameNotFound)
+		keyNames, err = vs.GetAllEOTSKeyNames()
+		require.NoError(t, err)
+		require.Empty(t, keyNames)
 	})
 }
@@ -11,4 +11,19 @@ var (
 
 	// ErrEOTSKeyNameNotFound The EOTS key name we try to fetch is not found in db
 	ErrEOTSKeyNameNotFound = errors.New("EOTS key name not found")
+
+	// ErrDuplicateSigningRecord The signing record we try to add already exists in db
+	ErrDuplicateSigningRecord = errors.New("signing record already exists")
+
+	// ErrSigningRecordNotFound The signing record we try to fetch is not found in db
+	ErrSigningRecordNotFound = errors.New("signing record not found")
+
+	// ErrConflictingSigningRecord The signing record we try to merge is for a different message than the one in db
+	ErrConflictingSigningRecord = errors.New("signing record conflicts with the existing one")
+
+	// ErrRandSchemeNotFound The randomness derivation scheme we try to fetch is not found in db
+	ErrRandSchemeNotFound = errors.New("randomness derivation scheme not found")
+
+	// ErrRandSchemeMismatch The randomness derivation scheme we try to set is different from the one in db
+	ErrRandSchemeMismatch = errors.New("randomness derivation scheme is different from the existing one")
 )
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"encoding/binary"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+var haltStateKey = []byte("haltState")
+
+// SetHaltState persists the halt state, so that the signing stays halted
+// across restarts until the state is cleared
+// The existing state is kept if the signing has already been halted
+func (s *EOTSStore) SetHaltState(state *types.HaltState) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		haltBucket := tx.ReadWriteBucket(haltBucketName)
+		if haltBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		if haltBucket.Get(haltStateKey) != nil {
+			return nil
+		}
+
+		// the halt state is encoded as the 8-byte timestamp followed by the reason
+		stateBytes := make([]byte, 8, 8+len(state.Reason))
+		binary.BigEndian.PutUint64(stateBytes, uint64(state.HaltedAt))
+		stateBytes = append(stateBytes, state.Reason...)
+
+		return haltBucket.Put(haltStateKey, stateBytes)
+	})
+}
+
+// ClearHaltState removes the halt state so that the signing is resumed
+func (s *EOTSStore) ClearHaltState() error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		haltBucket := tx.ReadWriteBucket(haltBucketName)
+		if haltBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		return haltBucket.Delete(haltStateKey)
+	})
+}
+
+// GetHaltState returns the halt state, or nil if the signing is not halted
+func (s *EOTSStore) GetHaltState() (*types.HaltState, error) {
+	var state *types.HaltState
+	err := s.db.View(func(tx kvdb.RTx) error {
+		haltBucket := tx.ReadBucket(haltBucketName)
+		if haltBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		stateBytes := haltBucket.Get(haltStateKey)
+		if stateBytes == nil {
+			return nil
+		}
+		if len(stateBytes) < 8 {
+			return ErrCorruptedEOTSDb
+		}
+
+		state = &types.HaltState{
+			Reason:   string(stateBytes[8:]),
+			HaltedAt: int64(binary.BigEndian.Uint64(stateBytes[:8])),
+		}
+		return nil
+	}, func() {
+		state = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
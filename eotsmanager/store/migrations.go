@@ -0,0 +1,29 @@
+package store
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/migration"
+)
+
+// migrations are the ordered schema migrations of the EOTS manager db
+// NOTE: a released migration must never be changed, append a new one instead
+var migrations = []*migration.Migration{
+	{
+		Version:     1,
+		Description: "create the buckets of key names, signing records, signed watermarks, randomness derivation schemes and the halt state",
+		Migrate: migration.CreateTopLevelBuckets(
+			eotsBucketName,
+			signingRecordBucketName,
+			signedWatermarkBucketName,
+			randSchemeBucketName,
+			haltBucketName,
+		),
+	},
+}
+
+// Migrate applies the pending schema migrations of the EOTS manager db
+// If dryRun is true, nothing is written and the report tells what would change
+func Migrate(db kvdb.Backend, dryRun bool) (*migration.Report, error) {
+	return migration.Run(db, migrations, dryRun)
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// SetRandScheme records the randomness derivation scheme of the given key and chain
+// ErrRandSchemeMismatch is returned if a different scheme has been recorded,
+// as the randomness committed with it would not match
+func (s *EOTSStore) SetRandScheme(fpPk, chainID []byte, scheme string) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		schemeBucket := tx.ReadWriteBucket(randSchemeBucketName)
+		if schemeBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		fpBucket, err := schemeBucket.CreateBucketIfNotExists(fpPk)
+		if err != nil {
+			return err
+		}
+
+		if existing := fpBucket.Get(chainID); existing != nil {
+			if string(existing) != scheme {
+				return ErrRandSchemeMismatch
+			}
+			return nil
+		}
+
+		return fpBucket.Put(chainID, []byte(scheme))
+	})
+}
+
+// GetRandScheme returns the randomness derivation scheme of the given key and chain
+func (s *EOTSStore) GetRandScheme(fpPk, chainID []byte) (string, error) {
+	var scheme string
+	err := s.db.View(func(tx kvdb.RTx) error {
+		schemeBucket := tx.ReadBucket(randSchemeBucketName)
+		if schemeBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		fpBucket := schemeBucket.NestedReadBucket(fpPk)
+		if fpBucket == nil {
+			return ErrRandSchemeNotFound
+		}
+
+		schemeBytes := fpBucket.Get(chainID)
+		if schemeBytes == nil {
+			return ErrRandSchemeNotFound
+		}
+
+		scheme = string(schemeBytes)
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return "", err
+	}
+
+	return scheme, nil
+}
@@ -0,0 +1,355 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+// SaveSigningRecord saves the record of an EOTS signature and raises the signed
+// watermark of the key and chain if needed
+// ErrDuplicateSigningRecord is returned if there is already a record at
+// the same height for the given key and chain, unless the record is imported
+// without signature and is for the same message
+func (s *EOTSStore) SaveSigningRecord(record *types.SigningRecord) error {
+	return s.SaveSigningRecords([]*types.SigningRecord{record})
+}
+
+// SaveSigningRecords saves the records of EOTS signatures in a single transaction
+// nothing is saved if ErrDuplicateSigningRecord is returned for any of the records
+func (s *EOTSStore) SaveSigningRecords(records []*types.SigningRecord) error {
+	for _, record := range records {
+		if record == nil {
+			return fmt.Errorf("cannot save nil signing record")
+		}
+	}
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		for _, record := range records {
+			if err := saveSigningRecord(tx, record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func saveSigningRecord(tx kvdb.RwTx, record *types.SigningRecord) error {
+	chainBucket, err := signingRecordRwBucket(tx, record.FpPk, record.ChainID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := getSigningRecord(chainBucket, record.Height)
+	if err != nil {
+		return err
+	}
+	// a record imported without the signature can be completed
+	if existing != nil && (len(existing.Sig) != 0 || !bytes.Equal(existing.MsgHash, record.MsgHash)) {
+		return ErrDuplicateSigningRecord
+	}
+
+	if err := putSigningRecord(chainBucket, record); err != nil {
+		return err
+	}
+
+	return raiseSignedWatermark(tx, record.FpPk, record.ChainID, record.Height)
+}
+
+// GetSigningRecord returns the record of the EOTS signature made
+// at the given height for the given key and chain
+func (s *EOTSStore) GetSigningRecord(fpPk, chainID []byte, height uint64) (*types.SigningRecord, error) {
+	var record *types.SigningRecord
+	err := s.db.View(func(tx kvdb.RTx) error {
+		recordBucket := tx.ReadBucket(signingRecordBucketName)
+		if recordBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		chainBucket := nestedReadBucket(recordBucket, fpPk, chainID)
+		if chainBucket == nil {
+			return ErrSigningRecordNotFound
+		}
+
+		var err error
+		record, err = getSigningRecord(chainBucket, height)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return ErrSigningRecordNotFound
+		}
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// GetSignedWatermark returns the highest height that has been signed
+// for the given key and chain, or zero if nothing has been signed
+func (s *EOTSStore) GetSignedWatermark(fpPk, chainID []byte) (uint64, error) {
+	var watermark uint64
+	err := s.db.View(func(tx kvdb.RTx) error {
+		watermarkBucket := tx.ReadBucket(signedWatermarkBucketName)
+		if watermarkBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		fpBucket := watermarkBucket.NestedReadBucket(fpPk)
+		if fpBucket == nil {
+			return nil
+		}
+
+		watermarkBytes := fpBucket.Get(chainID)
+		if watermarkBytes == nil {
+			return nil
+		}
+
+		watermark = binary.BigEndian.Uint64(watermarkBytes)
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return watermark, nil
+}
+
+// GetAllSigningHistories returns the signing history of every key and chain
+// with the records in the ascending order of height
+func (s *EOTSStore) GetAllSigningHistories() ([]*types.SigningHistory, error) {
+	var histories []*types.SigningHistory
+	err := s.db.View(func(tx kvdb.RTx) error {
+		var err error
+		histories, err = getAllSigningHistories(tx)
+		return err
+	}, func() {
+		histories = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+func getAllSigningHistories(tx kvdb.RTx) ([]*types.SigningHistory, error) {
+	recordBucket := tx.ReadBucket(signingRecordBucketName)
+	if recordBucket == nil {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	watermarkBucket := tx.ReadBucket(signedWatermarkBucketName)
+	if watermarkBucket == nil {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	// the watermark bucket is iterated as every signed key and chain has
+	// a watermark, while the records could be missing if only the watermark
+	// is imported
+	var histories []*types.SigningHistory
+	err := watermarkBucket.ForEach(func(fpPk, _ []byte) error {
+		fpBucket := watermarkBucket.NestedReadBucket(fpPk)
+		if fpBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		return fpBucket.ForEach(func(chainID, watermarkBytes []byte) error {
+			history := &types.SigningHistory{
+				FpPk:            copyBytes(fpPk),
+				ChainID:         copyBytes(chainID),
+				SignedWatermark: binary.BigEndian.Uint64(watermarkBytes),
+			}
+
+			chainBucket := nestedReadBucket(recordBucket, fpPk, chainID)
+			if chainBucket != nil {
+				err := chainBucket.ForEach(func(_, recordBytes []byte) error {
+					record, err := unmarshalSigningRecord(recordBytes)
+					if err != nil {
+						return err
+					}
+					history.Records = append(history.Records, record)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			histories = append(histories, history)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// MergeSigningHistories merges the given signing histories with the existing ones
+// in a single transaction. Records that do not exist are added and the signed
+// watermarks are never lowered. ErrConflictingSigningRecord is returned and nothing
+// is written if any record is for a different message than the existing one at the
+// same height
+func (s *EOTSStore) MergeSigningHistories(histories []*types.SigningHistory) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		return mergeSigningHistories(tx, histories)
+	})
+}
+
+func mergeSigningHistories(tx kvdb.RwTx, histories []*types.SigningHistory) error {
+	for _, history := range histories {
+		chainBucket, err := signingRecordRwBucket(tx, history.FpPk, history.ChainID)
+		if err != nil {
+			return err
+		}
+
+		watermark := history.SignedWatermark
+		for _, record := range history.Records {
+			existing, err := getSigningRecord(chainBucket, record.Height)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case existing == nil:
+				if err := putSigningRecord(chainBucket, record); err != nil {
+					return err
+				}
+			case !bytes.Equal(existing.MsgHash, record.MsgHash):
+				return fmt.Errorf("%w: height %d of chain %s", ErrConflictingSigningRecord,
+					record.Height, string(history.ChainID))
+			case len(existing.Sig) == 0 && len(record.Sig) != 0:
+				if err := putSigningRecord(chainBucket, record); err != nil {
+					return err
+				}
+			}
+
+			if record.Height > watermark {
+				watermark = record.Height
+			}
+		}
+
+		if err := raiseSignedWatermark(tx, history.FpPk, history.ChainID, watermark); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func signingRecordRwBucket(tx kvdb.RwTx, fpPk, chainID []byte) (walletdb.ReadWriteBucket, error) {
+	recordBucket := tx.ReadWriteBucket(signingRecordBucketName)
+	if recordBucket == nil {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	fpBucket, err := recordBucket.CreateBucketIfNotExists(fpPk)
+	if err != nil {
+		return nil, err
+	}
+
+	return fpBucket.CreateBucketIfNotExists(chainID)
+}
+
+func nestedReadBucket(bucket walletdb.ReadBucket, fpPk, chainID []byte) walletdb.ReadBucket {
+	fpBucket := bucket.NestedReadBucket(fpPk)
+	if fpBucket == nil {
+		return nil
+	}
+
+	return fpBucket.NestedReadBucket(chainID)
+}
+
+// raiseSignedWatermark sets the signed watermark of the key and chain
+// to the given height if it is higher than the current one
+func raiseSignedWatermark(tx kvdb.RwTx, fpPk, chainID []byte, height uint64) error {
+	watermarkBucket := tx.ReadWriteBucket(signedWatermarkBucketName)
+	if watermarkBucket == nil {
+		return ErrCorruptedEOTSDb
+	}
+
+	fpBucket, err := watermarkBucket.CreateBucketIfNotExists(fpPk)
+	if err != nil {
+		return err
+	}
+
+	if watermarkBytes := fpBucket.Get(chainID); watermarkBytes != nil {
+		if binary.BigEndian.Uint64(watermarkBytes) >= height {
+			return nil
+		}
+	}
+
+	return fpBucket.Put(chainID, heightToKey(height))
+}
+
+func getSigningRecord(chainBucket walletdb.ReadBucket, height uint64) (*types.SigningRecord, error) {
+	recordBytes := chainBucket.Get(heightToKey(height))
+	if recordBytes == nil {
+		return nil, nil
+	}
+
+	return unmarshalSigningRecord(recordBytes)
+}
+
+func putSigningRecord(chainBucket walletdb.ReadWriteBucket, record *types.SigningRecord) error {
+	marshalled, err := pm.Marshal(&proto.SigningRecord{
+		FpPk:      record.FpPk,
+		ChainId:   record.ChainID,
+		Height:    record.Height,
+		MsgHash:   record.MsgHash,
+		Sig:       record.Sig,
+		Timestamp: record.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	return chainBucket.Put(heightToKey(record.Height), marshalled)
+}
+
+func unmarshalSigningRecord(recordBytes []byte) (*types.SigningRecord, error) {
+	var record proto.SigningRecord
+	if err := pm.Unmarshal(recordBytes, &record); err != nil {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	return &types.SigningRecord{
+		FpPk:      record.FpPk,
+		ChainID:   record.ChainId,
+		Height:    record.Height,
+		MsgHash:   record.MsgHash,
+		Sig:       record.Sig,
+		Timestamp: record.Timestamp,
+	}, nil
+}
+
+// heightToKey encodes the height in big endian so that
+// the records are iterated in the ascending order of height
+func heightToKey(height uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+	return key
+}
+
+// copyBytes copies the bytes as the ones returned by the db
+// are only valid during the transaction
+func copyBytes(b []byte) []byte {
+	return append([]byte{}, b...)
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+// RandScheme is the randomness derivation scheme recorded for an EOTS key and chain
+type RandScheme struct {
+	FpPk    []byte
+	ChainID []byte
+	Scheme  string
+}
+
+// Snapshot is a consistent copy of the EOTS store for backups
+type Snapshot struct {
+	KeyNames         []*EOTSKeyName
+	RandSchemes      []*RandScheme
+	SigningHistories []*types.SigningHistory
+}
+
+// Snapshot reads the key names, the randomness derivation schemes and the signing
+// histories in a single read transaction, so that they are consistent with each
+// other while the store keeps serving requests
+// If whileHeld is not nil, it is called within the read transaction after the
+// snapshot is taken, e.g., to read the data kept outside the database, such as the
+// keyring files, consistently with the snapshot. It may be called more than once
+// if the transaction is retried
+func (s *EOTSStore) Snapshot(whileHeld func() error) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	err := s.db.View(func(tx kvdb.RTx) error {
+		var err error
+		if snapshot.KeyNames, err = getAllEOTSKeyNames(tx); err != nil {
+			return err
+		}
+		if snapshot.RandSchemes, err = getAllRandSchemes(tx); err != nil {
+			return err
+		}
+		if snapshot.SigningHistories, err = getAllSigningHistories(tx); err != nil {
+			return err
+		}
+		if whileHeld == nil {
+			return nil
+		}
+		return whileHeld()
+	}, func() {
+		snapshot = &Snapshot{}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Restore rebuilds the store from the snapshot in a single transaction
+// The key names and the randomness derivation schemes are replaced, while the
+// signing histories are merged with the existing ones so that no record is lost
+func (s *EOTSStore) Restore(snapshot *Snapshot) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		for _, bucketName := range [][]byte{eotsBucketName, randSchemeBucketName} {
+			if err := tx.DeleteTopLevelBucket(bucketName); err != nil {
+				return err
+			}
+			if _, err := tx.CreateTopLevelBucket(bucketName); err != nil {
+				return err
+			}
+		}
+
+		eotsBucket := tx.ReadWriteBucket(eotsBucketName)
+		for _, kn := range snapshot.KeyNames {
+			if err := saveEOTSKeyName(eotsBucket, kn.FpPk, kn.KeyName); err != nil {
+				return err
+			}
+		}
+
+		schemeBucket := tx.ReadWriteBucket(randSchemeBucketName)
+		for _, rs := range snapshot.RandSchemes {
+			fpBucket, err := schemeBucket.CreateBucketIfNotExists(rs.FpPk)
+			if err != nil {
+				return err
+			}
+			if err := fpBucket.Put(rs.ChainID, []byte(rs.Scheme)); err != nil {
+				return err
+			}
+		}
+
+		return mergeSigningHistories(tx, snapshot.SigningHistories)
+	})
+}
+
+func getAllRandSchemes(tx kvdb.RTx) ([]*RandScheme, error) {
+	schemeBucket := tx.ReadBucket(randSchemeBucketName)
+	if schemeBucket == nil {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	var schemes []*RandScheme
+	err := schemeBucket.ForEach(func(fpPk, _ []byte) error {
+		fpBucket := schemeBucket.NestedReadBucket(fpPk)
+		if fpBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		return fpBucket.ForEach(func(chainID, scheme []byte) error {
+			schemes = append(schemes, &RandScheme{
+				FpPk:    copyBytes(fpPk),
+				ChainID: copyBytes(chainID),
+				Scheme:  string(scheme),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schemes, nil
+}
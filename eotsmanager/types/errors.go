@@ -1,7 +1,25 @@
 package types
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrFinalityProviderAlreadyExisted = errors.New("the finality provider has already existed")
+	ErrDoubleSign                     = errors.New("double sign refused: a different message has already been signed at the same height")
+	ErrInvalidChainSig                = errors.New("the chain signature does not sign the EOTS public key with the chain key")
+	ErrSigningHalted                  = errors.New("signing is halted")
+	ErrResumeUnauthorized             = errors.New("resuming the signing requires the passphrase of an EOTS key or the admin credential")
+	ErrIncompatibleEOTSManager        = errors.New("the EOTS manager is incompatible")
+
+	// ErrPolicyViolation is wrapped by the errors of the requests refused by the signing policy
+	ErrPolicyViolation    = errors.New("signing policy violation")
+	ErrChainNotAllowed    = fmt.Errorf("%w: the chain is not allowed", ErrPolicyViolation)
+	ErrHeightJumpTooLarge = fmt.Errorf("%w: the height is too far above the last signed height", ErrPolicyViolation)
+	ErrNonMonotonicHeight = fmt.Errorf("%w: the height is below the last signed height", ErrPolicyViolation)
+	ErrRateLimited        = fmt.Errorf("%w: too many signing requests", ErrPolicyViolation)
+
+	// PolicyViolationErrs are the errors of the signing policy rules
+	PolicyViolationErrs = []error{ErrChainNotAllowed, ErrHeightJumpTooLarge, ErrNonMonotonicHeight, ErrRateLimited}
 )
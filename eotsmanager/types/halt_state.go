@@ -0,0 +1,8 @@
+package types
+
+// HaltState describes why and since when all the signing is halted
+type HaltState struct {
+	Reason string
+	// HaltedAt is the unix timestamp in seconds when the signing was halted
+	HaltedAt int64
+}
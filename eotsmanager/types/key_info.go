@@ -0,0 +1,11 @@
+package types
+
+// KeyInfo is the public information of an EOTS key
+type KeyInfo struct {
+	Name string
+	FpPk []byte
+	// MasterPubRand is the master public randomness of the key for a chain,
+	// which is derived with RandScheme; both are empty if no chain is given
+	MasterPubRand string
+	RandScheme    string
+}
@@ -0,0 +1,23 @@
+package types
+
+// SigningRecord is the record of an EOTS signature made by the EOTS manager
+type SigningRecord struct {
+	FpPk    []byte
+	ChainID []byte
+	Height  uint64
+	// MsgHash is the SHA-256 hash of the signed message
+	MsgHash []byte
+	// Sig is the EOTS signature, which can be empty if the record is imported
+	Sig []byte
+	// Timestamp is the unix timestamp in seconds when the signature is made
+	Timestamp int64
+}
+
+// SigningHistory is the signing history of an EOTS key on a consumer chain
+type SigningHistory struct {
+	FpPk    []byte
+	ChainID []byte
+	// SignedWatermark is the highest height that has been signed
+	SignedWatermark uint64
+	Records         []*SigningRecord
+}
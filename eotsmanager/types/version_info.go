@@ -0,0 +1,31 @@
+package types
+
+import "slices"
+
+// ProtocolVersion is the version of the RPC protocol of the EOTS manager
+// It should be bumped whenever the protocol changes in a way that the existing
+// clients cannot work with, e.g., a changed meaning of an existing field
+// Additions are advertised by the capabilities in VersionInfo instead
+const ProtocolVersion uint32 = 1
+
+// VersionInfo describes the build and the capabilities of an EOTS manager
+type VersionInfo struct {
+	Version         string
+	CommitHash      string
+	GoVersion       string
+	ProtocolVersion uint32
+	// RPCMethods are the names of the supported RPC methods
+	RPCMethods []string
+	// RandSchemes are the supported randomness derivation schemes
+	RandSchemes []string
+}
+
+// SupportsRPCMethod returns whether the EOTS manager serves the given RPC method
+func (v *VersionInfo) SupportsRPCMethod(method string) bool {
+	return slices.Contains(v.RPCMethods, method)
+}
+
+// SupportsRandScheme returns whether the EOTS manager derives the randomness with the given scheme
+func (v *VersionInfo) SupportsRandScheme(scheme string) bool {
+	return slices.Contains(v.RandSchemes, scheme)
+}
@@ -14,6 +14,7 @@ import (
 
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
 )
 
 var (
@@ -88,6 +89,11 @@ var CreateFpDaemonCmd = cli.Command{
 			Usage: "The hd path used to derive the private key",
 			Value: defaultHdPath,
 		},
+		cli.StringFlag{
+			Name:  randSchemeFlag,
+			Usage: "The scheme to derive the EOTS randomness at each height from the master randomness, i.e., uint32 or uint64",
+			Value: fpkeyring.RandSchemeUint32,
+		},
 		cli.StringFlag{
 			Name:  commissionRateFlag,
 			Usage: "The commission rate for the finality provider, e.g., 0.05",
@@ -152,6 +158,7 @@ func createFpDaemon(ctx *cli.Context) error {
 		ctx.String(chainIdFlag),
 		ctx.String(passphraseFlag),
 		ctx.String(hdPathFlag),
+		ctx.String(randSchemeFlag),
 		description,
 		&commissionRate,
 	)
@@ -307,7 +314,10 @@ var AddFinalitySigDaemonCmd = cli.Command{
 	Name:      "add-finality-sig",
 	ShortName: "afs",
 	Usage:     "Send a finality signature to the consumer chain. This command should only be used for presentation/testing purposes",
-	UsageText: fmt.Sprintf("add-finality-sig --%s [btc_pk_hex]", fpBTCPkFlag),
+	UsageText: fmt.Sprintf("add-finality-sig --%s [btc_pk_hex] --%s", fpBTCPkFlag, unsafeFlag),
+	Description: `The signature is not checked against the blocks that the finality provider
+	has voted for, so that signing a different block at a voted height gets it slashed.
+	The --unsafe flag is required to acknowledge it.`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  fpdDaemonAddressFlag,
@@ -329,6 +339,10 @@ var AddFinalitySigDaemonCmd = cli.Command{
 			Usage: "The last commit hash of the chain block",
 			Value: defaultAppHashStr,
 		},
+		cli.BoolFlag{
+			Name:  unsafeFlag,
+			Usage: "Acknowledge that the signature bypasses the double-vote guard and could get the finality provider slashed",
+		},
 	},
 	Action: addFinalitySig,
 }
@@ -352,7 +366,7 @@ func addFinalitySig(ctx *cli.Context) error {
 	}
 
 	res, err := rpcClient.AddFinalitySignature(
-		context.Background(), fpPk.MarshalHex(), ctx.Uint64(blockHeightFlag), appHash)
+		context.Background(), fpPk.MarshalHex(), ctx.Uint64(blockHeightFlag), appHash, ctx.Bool(unsafeFlag))
 	if err != nil {
 		return err
 	}
@@ -11,6 +11,8 @@ const (
 	hdPathFlag           = "hd-path"
 	chainIdFlag          = "chain-id"
 	signedFlag           = "signed"
+	randSchemeFlag       = "rand-scheme"
+	unsafeFlag           = "unsafe"
 	defaultPassphrase    = ""
 	defaultHdPath        = ""
 
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/urfave/cli"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/util"
+)
+
+var DBCommands = []cli.Command{
+	{
+		Name:     "db",
+		Usage:    "Command sets of managing the database.",
+		Category: "Database",
+		Subcommands: []cli.Command{
+			MigrateDBCmd,
+		},
+	},
+}
+
+var MigrateDBCmd = cli.Command{
+	Name:  "migrate",
+	Usage: "Migrate the database to the latest schema version.",
+	Description: `All the pending migrations are applied in a single transaction.
+	fpd start migrates the database as well, so this command is for reviewing or applying
+	the migrations before starting. Use --dry-run to only report the pending migrations`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "The path to the finality-provider home directory",
+			Value: fpcfg.DefaultFpdDir,
+		},
+		cli.BoolFlag{
+			Name:  dryRunFlag,
+			Usage: "Report the pending migrations without applying them",
+		},
+	},
+	Action: migrateDB,
+}
+
+func migrateDB(ctx *cli.Context) error {
+	homePath, err := filepath.Abs(ctx.String(homeFlag))
+	if err != nil {
+		return err
+	}
+	homePath = util.CleanAndExpandPath(homePath)
+
+	cfg, err := fpcfg.LoadConfig(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	if err != nil {
+		return fmt.Errorf("failed to create db backend: %w", err)
+	}
+	defer dbBackend.Close()
+
+	report, err := store.Migrate(dbBackend, ctx.Bool(dryRunFlag))
+	if err != nil {
+		return fmt.Errorf("failed to migrate the database: %w", err)
+	}
+
+	fmt.Print(report.String())
+	return nil
+}
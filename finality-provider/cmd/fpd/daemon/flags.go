@@ -13,6 +13,7 @@ const (
 	keyringBackendFlag = "keyring-backend"
 	rpcListenerFlag    = "rpc-listener"
 	recoverFlag        = "recover"
+	dryRunFlag         = "dry-run"
 
 	defaultKeyringBackend = keyring.BackendTest
 	defaultHdPath         = ""
@@ -19,6 +19,7 @@ func main() {
 	app.Usage = "Finality Provider Daemon (fpd)."
 	app.Commands = append(app.Commands, dcli.StartCommand, dcli.InitCommand)
 	app.Commands = append(app.Commands, dcli.KeysCommands...)
+	app.Commands = append(app.Commands, dcli.DBCommands...)
 
 	if err := app.Run(os.Args); err != nil {
 		fatal(err)
@@ -5,6 +5,7 @@ import (
 	"net"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
@@ -33,10 +34,15 @@ const (
 	defaultFastSyncInterval        = 10 * time.Second
 	defaultFastSyncLimit           = 10
 	defaultFastSyncGap             = 3
+	defaultVoteJournalRetention    = 100000
+	defaultParanoidScanWindow      = 100
 	defaultMaxSubmissionRetries    = 20
 	defaultBitcoinNetwork          = "signet"
 	defaultDataDirname             = "data"
 	defaultMaxNumFinalityProviders = 3
+	defaultEOTSManagerTimeout      = 10 * time.Second
+	defaultEOTSManagerMaxRetries   = 3
+	defaultEOTSHealthCheckInterval = 10 * time.Second
 )
 
 var (
@@ -66,7 +72,18 @@ type Config struct {
 	FastSyncInterval         time.Duration `long:"fastsyncinterval" description:"The interval between each try of fast sync, which is disabled if the value is 0"`
 	FastSyncLimit            uint64        `long:"fastsynclimit" description:"The maximum number of blocks to catch up for each fast sync"`
 	FastSyncGap              uint64        `long:"fastsyncgap" description:"The block gap that will trigger the fast sync"`
-	EOTSManagerAddress       string        `long:"eotsmanageraddress" description:"The address of the remote EOTS manager; Empty if the EOTS manager is running locally"`
+	VoteJournalRetention     uint64        `long:"votejournalretention" description:"The number of heights below the latest journaled block whose vote records are kept, which are kept forever if the value is 0"`
+	ParanoidMode             bool          `long:"paranoidmode" description:"Whether to check that the consumer chain does not have the finality signature at a height before voting, e.g., in case the db is restored from an old backup or another daemon runs with the same key"`
+	ParanoidScanWindow       uint64        `long:"paranoidscanwindow" description:"The number of the latest heights scanned on startup in the paranoid mode for the finality signatures not known to the db"`
+	EOTSManagerAddress       string        `long:"eotsmanageraddress" description:"The address of the remote EOTS manager, either host:port or unix:///path/to/socket if it runs on the same host"`
+	EOTSManagerTLSCACert     string        `long:"eotsmanagertlscacert" description:"Path to the CA certificate to verify the EOTS manager with; TLS is disabled if empty"`
+	EOTSManagerTLSCert       string        `long:"eotsmanagertlscert" description:"Path to the client certificate to authenticate to the EOTS manager with"`
+	EOTSManagerTLSKey        string        `long:"eotsmanagertlskey" description:"Path to the private key of the client certificate to authenticate to the EOTS manager with"`
+	EOTSManagerSharedAddress []string      `long:"eotsmanagersharedaddress" description:"The address of another EOTS manager sharing the keys and the slashing protection database with the one at EOTSManagerAddress, which the signing requests can fail over to; it can be set multiple times"`
+	EOTSManagerReadAddress   []string      `long:"eotsmanagerreadaddress" description:"The address of another EOTS manager not sharing the slashing protection database, which only the read-only requests can fail over to; it can be set multiple times"`
+	EOTSManagerTimeout       time.Duration `long:"eotsmanagertimeout" description:"The timeout of each attempt of a request to the EOTS manager"`
+	EOTSManagerMaxRetries    uint32        `long:"eotsmanagermaxretries" description:"The maximum number of retries of a request to the EOTS manager failing with a transient error"`
+	EOTSHealthCheckInterval  time.Duration `long:"eotshealthcheckinterval" description:"The interval between each health check of the EOTS managers, which is disabled if the value is 0"`
 	MaxNumFinalityProviders  uint32        `long:"maxnumfinalityproviders" description:"The maximum number of finality-provider instances running concurrently within the daemon"`
 
 	BitcoinNetwork string `long:"bitcoinnetwork" description:"Bitcoin network to run on" choise:"mainnet" choice:"regtest" choice:"testnet" choice:"simnet" choice:"signet"`
@@ -104,10 +121,15 @@ func DefaultConfigWithHome(homePath string) Config {
 		FastSyncInterval:         defaultFastSyncInterval,
 		FastSyncLimit:            defaultFastSyncLimit,
 		FastSyncGap:              defaultFastSyncGap,
+		VoteJournalRetention:     defaultVoteJournalRetention,
+		ParanoidScanWindow:       defaultParanoidScanWindow,
 		MaxSubmissionRetries:     defaultMaxSubmissionRetries,
 		BitcoinNetwork:           defaultBitcoinNetwork,
 		BTCNetParams:             defaultBTCNetParams,
 		EOTSManagerAddress:       defaultEOTSManagerAddress,
+		EOTSManagerTimeout:       defaultEOTSManagerTimeout,
+		EOTSManagerMaxRetries:    defaultEOTSManagerMaxRetries,
+		EOTSHealthCheckInterval:  defaultEOTSHealthCheckInterval,
 		RpcListener:              DefaultRpcListener,
 		MaxNumFinalityProviders:  defaultMaxNumFinalityProviders,
 		Metrics:                  metrics.DefaultFpConfig(),
@@ -173,6 +195,13 @@ func LoadConfig(homePath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// EOTSManagerAddresses returns the addresses of all the EOTS managers, starting with the primary one
+func (cfg *Config) EOTSManagerAddresses() []string {
+	addrs := []string{cfg.EOTSManagerAddress}
+	addrs = append(addrs, cfg.EOTSManagerSharedAddress...)
+	return append(addrs, cfg.EOTSManagerReadAddress...)
+}
+
 // Validate checks the given configuration to be sane. This makes sure no
 // illegal values or combination of values are set. All file system paths are
 // normalized. The cleaned up config is returned on success.
@@ -180,6 +209,29 @@ func (cfg *Config) Validate() error {
 	if cfg.EOTSManagerAddress == "" {
 		return fmt.Errorf("EOTS manager address not specified")
 	}
+	for _, addr := range cfg.EOTSManagerAddresses() {
+		if strings.HasPrefix(addr, eotscfg.UnixSocketScheme) {
+			if _, _, err := eotscfg.ParseRPCAddress(addr); err != nil {
+				return fmt.Errorf("invalid EOTS manager address %s: %w", addr, err)
+			}
+		}
+	}
+	// the configs written before the timeout was introduced do not have it
+	if cfg.EOTSManagerTimeout == 0 {
+		cfg.EOTSManagerTimeout = defaultEOTSManagerTimeout
+	}
+	if cfg.EOTSManagerTimeout < 0 {
+		return fmt.Errorf("the EOTS manager timeout should not be negative")
+	}
+	if cfg.EOTSHealthCheckInterval < 0 {
+		return fmt.Errorf("the EOTS manager health check interval should not be negative")
+	}
+	if cfg.EOTSManagerTLSCACert == "" && (cfg.EOTSManagerTLSCert != "" || cfg.EOTSManagerTLSKey != "") {
+		return fmt.Errorf("the EOTS manager client certificate requires the CA certificate to be set")
+	}
+	if (cfg.EOTSManagerTLSCert == "") != (cfg.EOTSManagerTLSKey == "") {
+		return fmt.Errorf("the EOTS manager client certificate and key should be set together")
+	}
 	// Multiple networks can't be selected simultaneously.  Count number of
 	// network flags passed; assign active network params
 	// while we're at it.
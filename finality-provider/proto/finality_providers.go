@@ -49,9 +49,10 @@ func NewFinalityProviderInfo(sfp *FinalityProvider) (*FinalityProviderInfo, erro
 			SecurityContact: des.SecurityContact,
 			Details:         des.Details,
 		},
-		RegisteredEpoch: sfp.RegisteredEpoch,
-		MasterPubRand:   sfp.MasterPubRand,
-		LastVotedHeight: sfp.LastVotedHeight,
-		Status:          sfp.Status.String(),
+		RegisteredEpoch:     sfp.RegisteredEpoch,
+		MasterPubRand:       sfp.MasterPubRand,
+		LastVotedHeight:     sfp.LastVotedHeight,
+		MasterPubRandScheme: sfp.MasterPubRandScheme,
+		Status:              sfp.Status.String(),
 	}, nil
 }
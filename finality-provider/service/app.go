@@ -11,6 +11,7 @@ import (
 	bbntypes "github.com/babylonchain/babylon/types"
 	bstypes "github.com/babylonchain/babylon/x/btcstaking/types"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
@@ -20,6 +21,7 @@ import (
 	"github.com/babylonchain/finality-provider/clientcontroller"
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	"github.com/babylonchain/finality-provider/eotsmanager/client"
+	eotscfg "github.com/babylonchain/finality-provider/eotsmanager/config"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/store"
@@ -64,12 +66,29 @@ func NewFinalityProviderAppFromConfig(
 
 	// if the EOTSManagerAddress is empty, run a local EOTS manager;
 	// otherwise connect a remote one with a gRPC client
-	em, err := client.NewEOTSManagerGRpcClient(cfg.EOTSManagerAddress)
+	tlsCfg, err := eotscfg.NewClientTLSConfig(cfg.EOTSManagerTLSCACert, cfg.EOTSManagerTLSCert, cfg.EOTSManagerTLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS config of the EOTS manager client: %w", err)
+	}
+	endpoints := []*client.Endpoint{{Address: cfg.EOTSManagerAddress}}
+	for _, addr := range cfg.EOTSManagerSharedAddress {
+		endpoints = append(endpoints, &client.Endpoint{Address: addr, SharedState: true})
+	}
+	for _, addr := range cfg.EOTSManagerReadAddress {
+		endpoints = append(endpoints, &client.Endpoint{Address: addr})
+	}
+	opts := client.DefaultOptions()
+	opts.RequestTimeout = cfg.EOTSManagerTimeout
+	opts.MaxRetries = int(cfg.EOTSManagerMaxRetries)
+	opts.HealthCheckInterval = cfg.EOTSHealthCheckInterval
+	opts.CheckVersion = checkEOTSManagerVersion
+	em, err := client.NewEOTSManagerGRpcClientWithEndpoints(endpoints, tlsCfg, opts, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create EOTS manager client: %w", err)
 	}
 
-	logger.Info("successfully connected to a remote EOTS manager", zap.String("address", cfg.EOTSManagerAddress))
+	logger.Info("successfully connected to a remote EOTS manager",
+		zap.String("address", cfg.EOTSManagerAddress), zap.Int("num_failover_endpoints", len(endpoints)-1))
 
 	return NewFinalityProviderApp(cfg, cc, em, db, logger)
 }
@@ -214,6 +233,7 @@ func (app *FinalityProviderApp) StartHandlingAll() error {
 }
 
 // NOTE: this is not safe in production, so only used for testing purpose
+// It requires the EOTS manager to allow exporting the keys
 func (app *FinalityProviderApp) getFpPrivKey(fpPk []byte) (*btcec.PrivateKey, error) {
 	record, err := app.eotsManager.KeyRecord(fpPk, "")
 	if err != nil {
@@ -314,7 +334,7 @@ func (app *FinalityProviderApp) Stop() error {
 }
 
 func (app *FinalityProviderApp) CreateFinalityProvider(
-	keyName, chainID, passPhrase, hdPath string,
+	keyName, chainID, passPhrase, hdPath, randScheme string,
 	description *stakingtypes.Description,
 	commission *sdkmath.LegacyDec,
 ) (*CreateFinalityProviderResult, error) {
@@ -324,6 +344,7 @@ func (app *FinalityProviderApp) CreateFinalityProvider(
 		chainID:         chainID,
 		passPhrase:      passPhrase,
 		hdPath:          hdPath,
+		randScheme:      randScheme,
 		description:     description,
 		commission:      commission,
 		errResponse:     make(chan error, 1),
@@ -345,7 +366,7 @@ func (app *FinalityProviderApp) CreateFinalityProvider(
 }
 
 func (app *FinalityProviderApp) handleCreateFinalityProviderRequest(req *createFinalityProviderRequest) (*createFinalityProviderResponse, error) {
-	storedFp, err := app.StoreFinalityProvider(req.keyName, req.passPhrase, req.hdPath, req.chainID, req.description, req.commission)
+	storedFp, err := app.StoreFinalityProvider(req.keyName, req.passPhrase, req.hdPath, req.chainID, req.randScheme, req.description, req.commission)
 	if err != nil {
 		return nil, err
 	}
@@ -390,11 +411,19 @@ func (app *FinalityProviderApp) loadChainKeyring(
 }
 
 // StoreFinalityProvider stores a new finality provider in the fp store.
+// The EOTS randomness is derived with RandSchemeUint32 if randScheme is empty
 func (app *FinalityProviderApp) StoreFinalityProvider(
-	keyName, passPhrase, hdPath, chainID string,
+	keyName, passPhrase, hdPath, chainID, randScheme string,
 	description *stakingtypes.Description,
 	commission *sdkmath.LegacyDec,
 ) (*store.StoredFinalityProvider, error) {
+	if randScheme == "" {
+		randScheme = fpkr.RandSchemeUint32
+	}
+	if err := fpkr.ValidateRandScheme(randScheme); err != nil {
+		return nil, err
+	}
+
 	// 1. check if the chain key exists
 	kr, chainSk, err := app.loadChainKeyring(keyName, passPhrase, hdPath)
 	if err != nil {
@@ -411,24 +440,24 @@ func (app *FinalityProviderApp) StoreFinalityProvider(
 	if err != nil {
 		return nil, err
 	}
-	fpRecord, err := app.eotsManager.KeyRecord(fpPk.MustMarshal(), passPhrase)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get finality-provider record: %w", err)
-	}
 
 	// 3. create proof-of-possession
-	pop, err := kr.CreatePop(fpRecord.PrivKey, passPhrase)
+	// the BTC signature is made by the EOTS manager so that the EOTS private key is not exposed
+	pop, err := kr.CreatePopWithSigner(fpPk, passPhrase, func(chainPk, chainSig []byte) (*schnorr.Signature, error) {
+		return app.eotsManager.SignPoP(fpPk.MustMarshal(), chainPk, chainSig, passPhrase)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create proof-of-possession of the finality provider: %w", err)
 	}
 
 	// 4. Create derive master public randomness
-	_, mpr, err := fpkr.GenerateMasterRandPair(fpRecord.PrivKey.Serialize(), types.MarshalChainID(chainID))
+	// the EOTS manager records the scheme so that it signs with the randomness derived in the same way
+	mpr, err := app.eotsManager.CreateMasterRandPair(fpPk.MustMarshal(), types.MarshalChainID(chainID), randScheme, passPhrase)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get master public randomness of the finality provider: %w", err)
 	}
 
-	if err := app.fps.CreateFinalityProvider(chainPk, fpPk.MustToBTCPK(), description, commission, mpr.MarshalBase58(), keyName, chainID, pop.BabylonSig, pop.BtcSig); err != nil {
+	if err := app.fps.CreateFinalityProvider(chainPk, fpPk.MustToBTCPK(), description, commission, mpr, randScheme, keyName, chainID, pop.BabylonSig, pop.BtcSig); err != nil {
 		return nil, fmt.Errorf("failed to save finality-provider: %w", err)
 	}
 	app.fpManager.metrics.RecordFpStatus(fpPk.MarshalHex(), proto.FinalityProviderStatus_CREATED)
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -28,27 +29,37 @@ const (
 	maxFailedCycles = 20
 )
 
-type skipHeightRequest struct {
-	height uint64
-	resp   chan *skipHeightResponse
-}
-
-type skipHeightResponse struct {
-	err error
-}
-
+// ChainPoller polls the blocks of the consumer chain once for all the finality-provider
+// instances of the daemon, and fans them out to the subscriptions of the instances
 type ChainPoller struct {
 	isStarted *atomic.Bool
 	wg        sync.WaitGroup
 	quit      chan struct{}
 
-	cc             clientcontroller.ClientController
-	cfg            *cfg.ChainPollerConfig
-	metrics        *metrics.FpMetrics
-	blockInfoChan  chan *types.BlockInfo
-	skipHeightChan chan *skipHeightRequest
-	nextHeight     uint64
-	logger         *zap.Logger
+	cc      clientcontroller.ClientController
+	cfg     *cfg.ChainPollerConfig
+	metrics *metrics.FpMetrics
+	logger  *zap.Logger
+
+	// mu guards the subscriptions, including their next heights,
+	// and the activated height
+	mu              sync.Mutex
+	subscriptions   map[*BlockSubscription]struct{}
+	activatedHeight uint64
+
+	// wakeUpChan makes the poller retrieve the blocks without waiting for the
+	// poll interval, e.g., once a subscription is added or skips heights
+	wakeUpChan chan struct{}
+}
+
+// BlockSubscription is a consumer of the blocks polled by the ChainPoller,
+// which receives the blocks in sequence from its own next height through
+// its own buffer, so that a slow consumer does not hold back the others
+type BlockSubscription struct {
+	cp            *ChainPoller
+	blockInfoChan chan *types.BlockInfo
+	// nextHeight is the height of the next block to send to the subscription
+	nextHeight uint64
 }
 
 func NewChainPoller(
@@ -58,36 +69,28 @@ func NewChainPoller(
 	metrics *metrics.FpMetrics,
 ) *ChainPoller {
 	return &ChainPoller{
-		isStarted:      atomic.NewBool(false),
-		logger:         logger,
-		cfg:            cfg,
-		cc:             cc,
-		metrics:        metrics,
-		blockInfoChan:  make(chan *types.BlockInfo, cfg.BufferSize),
-		skipHeightChan: make(chan *skipHeightRequest),
-		quit:           make(chan struct{}),
+		isStarted:     atomic.NewBool(false),
+		logger:        logger,
+		cfg:           cfg,
+		cc:            cc,
+		metrics:       metrics,
+		subscriptions: make(map[*BlockSubscription]struct{}),
+		wakeUpChan:    make(chan struct{}, 1),
+		quit:          make(chan struct{}),
 	}
 }
 
-func (cp *ChainPoller) Start(startHeight uint64) error {
+func (cp *ChainPoller) Start() error {
 	if cp.isStarted.Swap(true) {
 		return fmt.Errorf("the poller is already started")
 	}
 
 	cp.logger.Info("starting the chain poller")
 
-	err := cp.validateStartHeight(startHeight)
-	if err != nil {
-		return fmt.Errorf("invalid starting height %d: %w", startHeight, err)
-	}
-
-	cp.nextHeight = startHeight
-
 	cp.wg.Add(1)
 
 	go cp.pollChain()
 
-	cp.metrics.RecordPollerStartingHeight(startHeight)
 	cp.logger.Info("the chain poller is successfully started")
 
 	return nil
@@ -115,11 +118,33 @@ func (cp *ChainPoller) IsRunning() bool {
 	return cp.isStarted.Load()
 }
 
-// Return read only channel for incoming blocks
-// TODO: Handle the case when there is more than one consumer. Currently with more than
-// one consumer blocks most probably will be received out of order to those consumers.
-func (cp *ChainPoller) GetBlockInfoChan() <-chan *types.BlockInfo {
-	return cp.blockInfoChan
+// Subscribe returns a subscription receiving the blocks from the given height,
+// or from the activated height if it is higher
+func (cp *ChainPoller) Subscribe(startHeight uint64) (*BlockSubscription, error) {
+	err := cp.validateStartHeight(startHeight)
+	if err != nil {
+		return nil, fmt.Errorf("invalid starting height %d: %w", startHeight, err)
+	}
+
+	sub := &BlockSubscription{
+		cp:            cp,
+		blockInfoChan: make(chan *types.BlockInfo, cp.cfg.BufferSize),
+		nextHeight:    startHeight,
+	}
+
+	cp.mu.Lock()
+	if sub.nextHeight < cp.activatedHeight {
+		sub.nextHeight = cp.activatedHeight
+	}
+	cp.subscriptions[sub] = struct{}{}
+	cp.mu.Unlock()
+
+	cp.wakeUp()
+
+	cp.metrics.RecordPollerStartingHeight(startHeight)
+	cp.logger.Info("a new subscription to the chain poller is added", zap.Uint64("start_height", startHeight))
+
+	return sub, nil
 }
 
 func (cp *ChainPoller) latestBlockWithRetry() (*types.BlockInfo, error) {
@@ -203,15 +228,20 @@ func (cp *ChainPoller) validateStartHeight(startHeight uint64) error {
 
 // waitForActivation waits until BTC staking is activated
 func (cp *ChainPoller) waitForActivation() {
-	// ensure that the startHeight is no lower than the activated height
+	// ensure that the next heights are no lower than the activated height
 	for {
 		activatedHeight, err := cp.cc.QueryActivatedHeight()
 		if err != nil {
 			cp.logger.Debug("failed to query the consumer chain for the activated height", zap.Error(err))
 		} else {
-			if cp.nextHeight < activatedHeight {
-				cp.nextHeight = activatedHeight
+			cp.mu.Lock()
+			cp.activatedHeight = activatedHeight
+			for sub := range cp.subscriptions {
+				if sub.nextHeight < activatedHeight {
+					sub.nextHeight = activatedHeight
+				}
 			}
+			cp.mu.Unlock()
 			return
 		}
 
@@ -233,32 +263,44 @@ func (cp *ChainPoller) pollChain() {
 	var failedCycles uint32
 
 	for {
+		// the wake-ups before the cycle are served by the cycle
+		select {
+		case <-cp.wakeUpChan:
+		default:
+		}
+
 		// TODO: Handlig of request cancellation, as otherwise shutdown will be blocked
 		// until request is finished
-		blockToRetrieve := cp.nextHeight
-		block, err := cp.blockWithRetry(blockToRetrieve)
-		if err != nil {
-			failedCycles++
-			cp.logger.Debug(
-				"failed to query the consumer chain for the block",
-				zap.Uint32("current_failures", failedCycles),
-				zap.Uint64("block_to_retrieve", blockToRetrieve),
-				zap.Error(err),
-			)
-		} else {
-			// no error and we got the header we wanted to get, bump the state and push
-			// notification about data
-			cp.nextHeight = blockToRetrieve + 1
-			failedCycles = 0
+		heightsToRetrieve := cp.heightsToRetrieve()
+		var retrieved bool
+		for _, blockToRetrieve := range heightsToRetrieve {
+			block, err := cp.blockWithRetry(blockToRetrieve)
+			if err != nil {
+				cp.logger.Debug(
+					"failed to query the consumer chain for the block",
+					zap.Uint32("current_failures", failedCycles),
+					zap.Uint64("block_to_retrieve", blockToRetrieve),
+					zap.Error(err),
+				)
+				continue
+			}
+
+			retrieved = true
 			cp.metrics.RecordLastPolledHeight(block.Height)
 
 			cp.logger.Info("the poller retrieved the block from the consumer chain",
 				zap.Uint64("height", block.Height))
 
-			// push the data to the channel
-			// Note: if the consumer is too slow -- the buffer is full
-			// the channel will block, and we will stop retrieving data from the node
-			cp.blockInfoChan <- block
+			cp.dispatchBlock(blockToRetrieve, block)
+		}
+
+		// the cycle fails if none of the blocks to retrieve is retrieved
+		if len(heightsToRetrieve) > 0 {
+			if retrieved {
+				failedCycles = 0
+			} else {
+				failedCycles++
+			}
 		}
 
 		if failedCycles > maxFailedCycles {
@@ -268,70 +310,116 @@ func (cp *ChainPoller) pollChain() {
 		select {
 		case <-time.After(cp.cfg.PollInterval):
 
-		case req := <-cp.skipHeightChan:
-			// no need to skip heights if the target height is not higher
-			// than the next height to retrieve
-			targetHeight := req.height
-			if targetHeight <= cp.nextHeight {
-				resp := &skipHeightResponse{
-					err: fmt.Errorf(
-						"the target height %d is not higher than the next height %d to retrieve",
-						targetHeight, cp.nextHeight)}
-				req.resp <- resp
-				continue
-			}
+		case <-cp.wakeUpChan:
 
-			// drain blocks that can be skipped from blockInfoChan
-			cp.clearChanBufferUpToHeight(targetHeight)
+		case <-cp.quit:
+			return
+		}
+	}
+}
 
-			// set the next height to the skip height
-			cp.nextHeight = targetHeight
+// wakeUp makes the poller start the next cycle without waiting for the poll interval
+func (cp *ChainPoller) wakeUp() {
+	select {
+	case cp.wakeUpChan <- struct{}{}:
+	default:
+	}
+}
 
-			cp.logger.Debug("the poller has skipped height(s)",
-				zap.Uint64("next_height", req.height))
+// heightsToRetrieve returns the distinct next heights of the subscriptions
+// in ascending order, so that the subscriptions at the same height share the
+// retrieved block, and a subscription lagging behind does not hold back the others
+// The subscriptions whose buffers are full are left until they have room again
+func (cp *ChainPoller) heightsToRetrieve() []uint64 {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	var heights []uint64
+	for sub := range cp.subscriptions {
+		if len(sub.blockInfoChan) == cap(sub.blockInfoChan) || slices.Contains(heights, sub.nextHeight) {
+			continue
+		}
+		heights = append(heights, sub.nextHeight)
+	}
+	slices.Sort(heights)
 
-			req.resp <- &skipHeightResponse{}
+	return heights
+}
 
-		case <-cp.quit:
-			return
+// dispatchBlock sends the block retrieved at the given height to the subscriptions
+// expecting it and bumps their next heights
+func (cp *ChainPoller) dispatchBlock(height uint64, block *types.BlockInfo) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	for sub := range cp.subscriptions {
+		if sub.nextHeight != height {
+			continue
+		}
+		// the poller is the only sender, so there is no need to
+		// block as long as the buffer is checked under the lock
+		if len(sub.blockInfoChan) == cap(sub.blockInfoChan) {
+			continue
 		}
+		sub.blockInfoChan <- block
+		sub.nextHeight = height + 1
 	}
 }
 
-func (cp *ChainPoller) SkipToHeight(height uint64) error {
-	if !cp.IsRunning() {
+// Return read only channel for incoming blocks
+func (sub *BlockSubscription) GetBlockInfoChan() <-chan *types.BlockInfo {
+	return sub.blockInfoChan
+}
+
+// SkipToHeight sets the next height of the subscription to the given height and
+// drops the buffered blocks, which are all lower than it
+func (sub *BlockSubscription) SkipToHeight(height uint64) error {
+	if !sub.cp.IsRunning() {
 		return fmt.Errorf("the chain poller is stopped")
 	}
 
-	respChan := make(chan *skipHeightResponse, 1)
+	sub.cp.mu.Lock()
+	defer sub.cp.mu.Unlock()
 
-	// this handles the case when the poller is stopped before the
-	// skip height request is sent
-	select {
-	case <-cp.quit:
-		return fmt.Errorf("the chain poller is stopped")
-	case cp.skipHeightChan <- &skipHeightRequest{height: height, resp: respChan}:
+	if _, ok := sub.cp.subscriptions[sub]; !ok {
+		return fmt.Errorf("the subscription is cancelled")
 	}
 
-	// this handles the case when the poller is stopped before
-	// the skip height request is returned
-	select {
-	case <-cp.quit:
-		return fmt.Errorf("the chain poller is stopped")
-	case resp := <-respChan:
-		return resp.err
+	// no need to skip heights if the target height is not higher
+	// than the next height to retrieve
+	if height <= sub.nextHeight {
+		return fmt.Errorf(
+			"the target height %d is not higher than the next height %d to retrieve",
+			height, sub.nextHeight)
+	}
+
+	// drain blocks that can be skipped from blockInfoChan
+	for len(sub.blockInfoChan) > 0 {
+		<-sub.blockInfoChan
 	}
+
+	// set the next height to the skip height
+	sub.nextHeight = height
+
+	sub.cp.logger.Debug("the poller has skipped height(s)",
+		zap.Uint64("next_height", height))
+
+	sub.cp.wakeUp()
+
+	return nil
 }
 
-func (cp *ChainPoller) NextHeight() uint64 {
-	return cp.nextHeight
+func (sub *BlockSubscription) NextHeight() uint64 {
+	sub.cp.mu.Lock()
+	defer sub.cp.mu.Unlock()
+
+	return sub.nextHeight
 }
 
-func (cp *ChainPoller) clearChanBufferUpToHeight(upToHeight uint64) {
-	for len(cp.blockInfoChan) > 0 {
-		block := <-cp.blockInfoChan
-		if block.Height+1 >= upToHeight {
-			break
-		}
-	}
+// Unsubscribe stops sending blocks to the subscription
+func (sub *BlockSubscription) Unsubscribe() {
+	sub.cp.mu.Lock()
+	defer sub.cp.mu.Unlock()
+
+	delete(sub.cp.subscriptions, sub)
 }
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
@@ -19,7 +20,7 @@ import (
 )
 
 // FuzzChainPoller_Start tests the poller polling blocks
-// in sequence
+// in sequence, each of them once for all the subscriptions
 func FuzzChainPoller_Start(f *testing.F) {
 	testutil.AddRandomSeedsToFuzzer(f, 10)
 	f.Fuzz(func(t *testing.T, seed int64) {
@@ -39,33 +40,118 @@ func FuzzChainPoller_Start(f *testing.F) {
 		}
 		mockClientController.EXPECT().QueryBestBlock().Return(currentBlockRes, nil).AnyTimes()
 
+		var mu sync.Mutex
+		queriedNum := make(map[uint64]int)
+		mockClientController.EXPECT().QueryBlock(gomock.Any()).DoAndReturn(func(height uint64) (*types.BlockInfo, error) {
+			if height > endHeight {
+				return nil, fmt.Errorf("the block %d is not produced yet", height)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			queriedNum[height]++
+			return &types.BlockInfo{Height: height}, nil
+		}).AnyTimes()
+
+		// TODO: use mock metrics
+		m := metrics.NewFpMetrics()
+		pollerCfg := fpcfg.DefaultChainPollerConfig()
+		pollerCfg.PollInterval = 10 * time.Millisecond
+		poller := service.NewChainPoller(zap.NewNop(), &pollerCfg, mockClientController, m)
+		subs := make([]*service.BlockSubscription, r.Intn(3)+2)
+		for i := range subs {
+			sub, err := poller.Subscribe(startHeight)
+			require.NoError(t, err)
+			subs[i] = sub
+		}
+		err := poller.Start()
+		require.NoError(t, err)
+		defer func() {
+			err := poller.Stop()
+			require.NoError(t, err)
+		}()
+
 		for i := startHeight; i <= endHeight; i++ {
-			resBlock := &types.BlockInfo{
-				Height: i,
+			for _, sub := range subs {
+				select {
+				case info := <-sub.GetBlockInfoChan():
+					require.Equal(t, i, info.Height)
+				case <-time.After(10 * time.Second):
+					t.Fatalf("Failed to get block info")
+				}
 			}
-			mockClientController.EXPECT().QueryBlock(i).Return(resBlock, nil).AnyTimes()
 		}
 
+		mu.Lock()
+		defer mu.Unlock()
+		for i := startHeight; i <= endHeight; i++ {
+			require.Equal(t, 1, queriedNum[i])
+		}
+	})
+}
+
+// FuzzChainPoller_SlowSubscription tests that a subscription whose buffer
+// is full does not hold back the other subscriptions
+func FuzzChainPoller_SlowSubscription(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		currentHeight := uint64(r.Int63n(100) + 1)
+		startHeight := currentHeight + 1
+		endHeight := startHeight + uint64(r.Int63n(10)+1)
+
+		ctl := gomock.NewController(t)
+		mockClientController := mocks.NewMockClientController(ctl)
+		mockClientController.EXPECT().Close().Return(nil).AnyTimes()
+		mockClientController.EXPECT().QueryActivatedHeight().Return(uint64(1), nil).AnyTimes()
+
+		currentBlockRes := &types.BlockInfo{
+			Height: currentHeight,
+		}
+		mockClientController.EXPECT().QueryBestBlock().Return(currentBlockRes, nil).AnyTimes()
+		mockClientController.EXPECT().QueryBlock(gomock.Any()).DoAndReturn(func(height uint64) (*types.BlockInfo, error) {
+			return &types.BlockInfo{Height: height}, nil
+		}).AnyTimes()
+
 		// TODO: use mock metrics
 		m := metrics.NewFpMetrics()
 		pollerCfg := fpcfg.DefaultChainPollerConfig()
 		pollerCfg.PollInterval = 10 * time.Millisecond
+		pollerCfg.BufferSize = 1
 		poller := service.NewChainPoller(zap.NewNop(), &pollerCfg, mockClientController, m)
-		err := poller.Start(startHeight)
+		err := poller.Start()
 		require.NoError(t, err)
 		defer func() {
 			err := poller.Stop()
 			require.NoError(t, err)
 		}()
 
+		// the slow subscription never reads its blocks
+		slowSub, err := poller.Subscribe(startHeight)
+		require.NoError(t, err)
+		sub, err := poller.Subscribe(startHeight)
+		require.NoError(t, err)
+
 		for i := startHeight; i <= endHeight; i++ {
 			select {
-			case info := <-poller.GetBlockInfoChan():
+			case info := <-sub.GetBlockInfoChan():
 				require.Equal(t, i, info.Height)
 			case <-time.After(10 * time.Second):
 				t.Fatalf("Failed to get block info")
 			}
 		}
+
+		require.Equal(t, startHeight+1, slowSub.NextHeight())
+		info := <-slowSub.GetBlockInfoChan()
+		require.Equal(t, startHeight, info.Height)
+
+		// the slow subscription receives the next block once it has room
+		select {
+		case info := <-slowSub.GetBlockInfoChan():
+			require.Equal(t, startHeight+1, info.Height)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Failed to get block info")
+		}
 	})
 }
 
@@ -102,16 +188,18 @@ func FuzzChainPoller_SkipHeight(f *testing.F) {
 		pollerCfg := fpcfg.DefaultChainPollerConfig()
 		pollerCfg.PollInterval = 1 * time.Second
 		poller := service.NewChainPoller(zap.NewNop(), &pollerCfg, mockClientController, m)
+		sub, err := poller.Subscribe(startHeight)
+		require.NoError(t, err)
 		// should expect error if the poller is not started
-		err := poller.SkipToHeight(skipHeight)
+		err = sub.SkipToHeight(skipHeight)
 		require.Error(t, err)
-		err = poller.Start(startHeight)
+		err = poller.Start()
 		require.NoError(t, err)
 		defer func() {
 			err := poller.Stop()
 			require.NoError(t, err)
 			// should expect error if the poller is stopped
-			err = poller.SkipToHeight(skipHeight)
+			err = sub.SkipToHeight(skipHeight)
 			require.Error(t, err)
 		}()
 
@@ -121,11 +209,11 @@ func FuzzChainPoller_SkipHeight(f *testing.F) {
 			wg.Done()
 			// insert a skipToHeight request with height lower than the next
 			// height to retrieve, expecting an error
-			err = poller.SkipToHeight(poller.NextHeight() - 1)
+			err = sub.SkipToHeight(sub.NextHeight() - 1)
 			require.Error(t, err)
 			// insert a skipToHeight request with a height higher than the
 			// next height to retrieve
-			err = poller.SkipToHeight(skipHeight)
+			err = sub.SkipToHeight(skipHeight)
 			require.NoError(t, err)
 		}()
 
@@ -135,7 +223,7 @@ func FuzzChainPoller_SkipHeight(f *testing.F) {
 				break
 			}
 			select {
-			case info := <-poller.GetBlockInfoChan():
+			case info := <-sub.GetBlockInfoChan():
 				if info.Height == skipHeight {
 					skipped = true
 				} else {
@@ -148,6 +236,6 @@ func FuzzChainPoller_SkipHeight(f *testing.F) {
 
 		wg.Wait()
 
-		require.Equal(t, skipHeight+1, poller.NextHeight())
+		require.Equal(t, skipHeight+1, sub.NextHeight())
 	})
 }
@@ -59,7 +59,7 @@ func (c *FinalityProviderServiceGRpcClient) RegisterFinalityProvider(
 
 func (c *FinalityProviderServiceGRpcClient) CreateFinalityProvider(
 	ctx context.Context,
-	keyName, chainID, passphrase, hdPath string,
+	keyName, chainID, passphrase, hdPath, randScheme string,
 	description types.Description,
 	commission *sdkmath.LegacyDec,
 ) (*proto.CreateFinalityProviderResponse, error) {
@@ -76,6 +76,7 @@ func (c *FinalityProviderServiceGRpcClient) CreateFinalityProvider(
 		HdPath:      hdPath,
 		Description: descBytes,
 		Commission:  commission.String(),
+		RandScheme:  randScheme,
 	}
 
 	res, err := c.client.CreateFinalityProvider(ctx, req)
@@ -86,11 +87,12 @@ func (c *FinalityProviderServiceGRpcClient) CreateFinalityProvider(
 	return res, nil
 }
 
-func (c *FinalityProviderServiceGRpcClient) AddFinalitySignature(ctx context.Context, fpPk string, height uint64, appHash []byte) (*proto.AddFinalitySignatureResponse, error) {
+func (c *FinalityProviderServiceGRpcClient) AddFinalitySignature(ctx context.Context, fpPk string, height uint64, appHash []byte, unsafe bool) (*proto.AddFinalitySignatureResponse, error) {
 	req := &proto.AddFinalitySignatureRequest{
 		BtcPk:   fpPk,
 		Height:  height,
 		AppHash: appHash,
+		Unsafe:  unsafe,
 	}
 
 	res, err := c.client.AddFinalitySignature(ctx, req)
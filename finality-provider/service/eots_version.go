@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+	fpkr "github.com/babylonchain/finality-provider/keyring"
+)
+
+// requiredEOTSMethods are the RPC methods of the EOTS manager that the finality provider calls
+var requiredEOTSMethods = []string{
+	"Ping",
+	"GetVersion",
+	"CreateKey",
+	"CreateMasterRandPair",
+	"KeyRecord",
+	"SignEOTS",
+	"SignEOTSBatch",
+	"SignPoP",
+	"SigningRecord",
+}
+
+// checkEOTSManagerVersion refuses an EOTS manager that the finality provider cannot work with,
+// i.e., one of another protocol version, lacking any of the RPC methods that the finality
+// provider calls, or not deriving the randomness with all the schemes the finality provider
+// could use
+func checkEOTSManagerVersion(info *eotstypes.VersionInfo) error {
+	if info.ProtocolVersion != eotstypes.ProtocolVersion {
+		return fmt.Errorf("%w: eotsd %s speaks the protocol version %d, while fpd speaks %d, please run fpd and eotsd of compatible versions",
+			eotstypes.ErrIncompatibleEOTSManager, info.Version, info.ProtocolVersion, eotstypes.ProtocolVersion)
+	}
+
+	var missingMethods []string
+	for _, m := range requiredEOTSMethods {
+		if !info.SupportsRPCMethod(m) {
+			missingMethods = append(missingMethods, m)
+		}
+	}
+	if len(missingMethods) != 0 {
+		return fmt.Errorf("%w: eotsd %s does not support the RPC methods %s, please upgrade eotsd",
+			eotstypes.ErrIncompatibleEOTSManager, info.Version, strings.Join(missingMethods, ", "))
+	}
+
+	var missingSchemes []string
+	for _, scheme := range fpkr.RandSchemes() {
+		if !info.SupportsRandScheme(scheme) {
+			missingSchemes = append(missingSchemes, scheme)
+		}
+	}
+	if len(missingSchemes) != 0 {
+		return fmt.Errorf("%w: eotsd %s does not support the randomness derivation schemes %s, please upgrade eotsd",
+			eotstypes.ErrIncompatibleEOTSManager, info.Version, strings.Join(missingSchemes, ", "))
+	}
+
+	return nil
+}
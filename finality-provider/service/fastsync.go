@@ -5,6 +5,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/types"
 )
 
@@ -60,6 +61,15 @@ func (fp *FinalityProviderInstance) FastSync(startHeight, endHeight uint64) (*Fa
 			}
 			if !hasVp {
 				fp.metrics.IncrementFpTotalBlocksWithoutVotingPower(fp.GetBtcPkHex())
+				fp.journalVote(b, proto.VoteDecision_NO_POWER, "", nil)
+				continue
+			}
+			// check whether the consumer chain already has the vote in the paranoid mode
+			votedOnChain, err := fp.checkVotedOnChain(b)
+			if err != nil {
+				return nil, err
+			}
+			if votedOnChain {
 				continue
 			}
 			// all good, add the block for catching up
@@ -74,8 +84,10 @@ func (fp *FinalityProviderInstance) FastSync(startHeight, endHeight uint64) (*Fa
 
 		res, err := fp.SubmitBatchFinalitySignatures(catchUpBlocks)
 		if err != nil {
+			fp.journalVotes(catchUpBlocks, proto.VoteDecision_FAILED, "", err)
 			return nil, err
 		}
+		fp.journalVotes(catchUpBlocks, proto.VoteDecision_VOTED, res.TxHash, nil)
 		fp.metrics.AddToFpTotalVotedBlocks(fp.GetBtcPkHex(), float64(len(catchUpBlocks)))
 
 		responses = append(responses, res)
@@ -89,7 +101,9 @@ func (fp *FinalityProviderInstance) FastSync(startHeight, endHeight uint64) (*Fa
 	}
 
 	// update the processed height
-	fp.MustSetLastProcessedHeight(syncedHeight)
+	if err := fp.SetLastProcessedHeight(syncedHeight); err != nil {
+		return nil, err
+	}
 
 	return &FastSyncResult{
 		Responses:           responses,
@@ -4,9 +4,11 @@ import (
 	"math/rand"
 	"testing"
 
+	bbntypes "github.com/babylonchain/babylon/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/testutil"
 	"github.com/babylonchain/finality-provider/types"
 )
@@ -27,7 +29,7 @@ func FuzzFastSync(f *testing.F) {
 		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
 		// mock finalised BTC timestamped
 		mockClientController.EXPECT().QueryLastFinalizedEpoch().Return(randomRegiteredEpoch, nil).AnyTimes()
-		_, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight, randomRegiteredEpoch)
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight, randomRegiteredEpoch)
 		defer cleanUp()
 
 		// mock voting power
@@ -48,5 +50,81 @@ func FuzzFastSync(f *testing.F) {
 		require.Equal(t, expectedTxHash, result.Responses[0].TxHash)
 		require.Equal(t, currentHeight, fpIns.GetLastVotedHeight())
 		require.Equal(t, currentHeight, fpIns.GetLastProcessedHeight())
+
+		// every caught-up block is journaled with the batch transaction
+		records, err := app.GetFinalityProviderStore().GetVoteRecords(fpIns.GetBtcPk(), finalizedHeight+1, currentHeight)
+		require.NoError(t, err)
+		require.Len(t, records, len(catchUpBlocks))
+		for i, record := range records {
+			require.Equal(t, catchUpBlocks[i].Height, record.Height)
+			require.Equal(t, catchUpBlocks[i].Hash, record.BlockHash)
+			require.Equal(t, proto.VoteDecision_VOTED, record.Decision)
+			require.Equal(t, expectedTxHash, record.TxHash)
+		}
+	})
+}
+
+// FuzzFastSyncParanoidMode tests a case where the consumer chain already has
+// the vote of the finality provider at some of the heights to catch up, e.g.,
+// submitted by another daemon running with the same key.
+// It is expected that the finality provider only votes for the other blocks
+// in the paranoid mode
+func FuzzFastSyncParanoidMode(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomRegiteredEpoch := uint64(r.Int63n(10) + 1)
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		finalizedHeight := randomStartingHeight + uint64(r.Int63n(10)+2)
+		currentHeight := finalizedHeight + uint64(r.Int63n(10)+2)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		// mock finalised BTC timestamped
+		mockClientController.EXPECT().QueryLastFinalizedEpoch().Return(randomRegiteredEpoch, nil).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight, randomRegiteredEpoch)
+		defer cleanUp()
+		app.GetConfig().ParanoidMode = true
+
+		// mock voting power
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(fpIns.GetBtcPk(), gomock.Any()).
+			Return(uint64(1), nil).AnyTimes()
+
+		// the chain has the vote at a random height to catch up
+		catchUpBlocks := testutil.GenBlocks(r, finalizedHeight+1, currentHeight)
+		votedIdx := r.Intn(len(catchUpBlocks))
+		var blocksToVote []*types.BlockInfo
+		for i, b := range catchUpBlocks {
+			if i == votedIdx {
+				mockClientController.EXPECT().QueryVotesAtHeight(b.Height).
+					Return([]bbntypes.BIP340PubKey{*fpIns.GetBtcPkBIP340()}, nil).AnyTimes()
+				continue
+			}
+			mockClientController.EXPECT().QueryVotesAtHeight(b.Height).Return(nil, nil).AnyTimes()
+			blocksToVote = append(blocksToVote, b)
+		}
+
+		expectedTxHash := testutil.GenRandomHexStr(r, 32)
+		finalizedBlock := &types.BlockInfo{Height: finalizedHeight, Hash: testutil.GenRandomByteArray(r, 32)}
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(uint64(1)).Return([]*types.BlockInfo{finalizedBlock}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryBlocks(finalizedHeight+1, currentHeight, uint64(10)).
+			Return(catchUpBlocks, nil)
+		mockClientController.EXPECT().SubmitBatchFinalitySigs(fpIns.GetBtcPk(), blocksToVote, gomock.Any()).
+			Return(&types.TxResponse{TxHash: expectedTxHash}, nil).Times(1)
+		result, err := fpIns.FastSync(finalizedHeight+1, currentHeight)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, currentHeight, fpIns.GetLastVotedHeight())
+		require.Equal(t, currentHeight, fpIns.GetLastProcessedHeight())
+
+		records, err := app.GetFinalityProviderStore().GetVoteRecords(fpIns.GetBtcPk(), finalizedHeight+1, currentHeight)
+		require.NoError(t, err)
+		require.Len(t, records, len(catchUpBlocks))
+		for i, record := range records {
+			if i == votedIdx {
+				require.Equal(t, proto.VoteDecision_ALREADY_VOTED, record.Decision)
+				continue
+			}
+			require.Equal(t, proto.VoteDecision_VOTED, record.Decision)
+		}
 	})
 }
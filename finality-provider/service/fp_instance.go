@@ -1,6 +1,9 @@
 package service
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -19,6 +22,7 @@ import (
 
 	"github.com/babylonchain/finality-provider/clientcontroller"
 	"github.com/babylonchain/finality-provider/eotsmanager"
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/store"
@@ -39,6 +43,9 @@ type FinalityProviderInstance struct {
 	poller  *ChainPoller
 	metrics *metrics.FpMetrics
 
+	// blockSub receives the blocks from the poller shared by the instances
+	blockSub *BlockSubscription
+
 	// passphrase is used to unlock private keys
 	passphrase string
 
@@ -60,6 +67,7 @@ func NewFinalityProviderInstance(
 	cfg *fpcfg.Config,
 	s *store.FinalityProviderStore,
 	cc clientcontroller.ClientController,
+	poller *ChainPoller,
 	em eotsmanager.EOTSManager,
 	metrics *metrics.FpMetrics,
 	passphrase string,
@@ -101,6 +109,7 @@ func NewFinalityProviderInstance(
 		passphrase:      passphrase,
 		em:              em,
 		cc:              cc,
+		poller:          poller,
 		metrics:         metrics,
 	}, nil
 }
@@ -120,13 +129,12 @@ func (fp *FinalityProviderInstance) Start() error {
 	fp.logger.Info("the finality-provider has been bootstrapped",
 		zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", startHeight))
 
-	poller := NewChainPoller(fp.logger, fp.cfg.PollerConfig, fp.cc, fp.metrics)
-
-	if err := poller.Start(startHeight + 1); err != nil {
-		return fmt.Errorf("failed to start the poller: %w", err)
+	blockSub, err := fp.poller.Subscribe(startHeight + 1)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to the poller: %w", err)
 	}
 
-	fp.poller = poller
+	fp.blockSub = blockSub
 
 	fp.laggingTargetChan = make(chan *types.BlockInfo, 1)
 
@@ -146,6 +154,14 @@ func (fp *FinalityProviderInstance) bootstrap() (uint64, error) {
 		return 0, err
 	}
 
+	if err := fp.reconcileVoteIntents(); err != nil {
+		return 0, fmt.Errorf("failed to reconcile the vote intents: %w", err)
+	}
+
+	if err := fp.scanVotesOnChain(latestBlock.Height); err != nil {
+		return 0, fmt.Errorf("failed to scan the votes on the consumer chain: %w", err)
+	}
+
 	if fp.checkLagging(latestBlock) {
 		_, err := fp.tryFastSync(latestBlock)
 		if err != nil && !clientcontroller.IsExpected(err) {
@@ -166,9 +182,7 @@ func (fp *FinalityProviderInstance) Stop() error {
 		return fmt.Errorf("the finality-provider %s has already stopped", fp.GetBtcPkHex())
 	}
 
-	if err := fp.poller.Stop(); err != nil {
-		return fmt.Errorf("failed to stop the poller: %w", err)
-	}
+	fp.blockSub.Unsubscribe()
 
 	fp.logger.Info("stopping finality-provider instance", zap.String("pk", fp.GetBtcPkHex()))
 
@@ -184,12 +198,15 @@ func (fp *FinalityProviderInstance) IsRunning() bool {
 	return fp.isStarted.Load()
 }
 
+// finalitySigSubmissionLoop processes the blocks received from the poller until the
+// instance is stopped, or until it reports a critical error, upon which the instance
+// is stopped by the manager
 func (fp *FinalityProviderInstance) finalitySigSubmissionLoop() {
 	defer fp.wg.Done()
 
 	for {
 		select {
-		case b := <-fp.poller.GetBlockInfoChan():
+		case b := <-fp.blockSub.GetBlockInfoChan():
 			fp.logger.Debug(
 				"the finality-provider received a new block, start processing",
 				zap.String("pk", fp.GetBtcPkHex()),
@@ -204,13 +221,26 @@ func (fp *FinalityProviderInstance) finalitySigSubmissionLoop() {
 			hasVp, err := fp.hasVotingPower(b)
 			if err != nil {
 				fp.reportCriticalErr(err)
-				continue
+				return
 			}
 			if !hasVp {
 				// the finality provider does not have voting power
 				// and it will never will at this block
-				fp.MustSetLastProcessedHeight(b.Height)
+				if err := fp.SetLastProcessedHeight(b.Height); err != nil {
+					fp.reportCriticalErr(err)
+					return
+				}
 				fp.metrics.IncrementFpTotalBlocksWithoutVotingPower(fp.GetBtcPkHex())
+				fp.journalVote(b, proto.VoteDecision_NO_POWER, "", nil)
+				continue
+			}
+			// check whether the consumer chain already has the vote in the paranoid mode
+			votedOnChain, err := fp.checkVotedOnChain(b)
+			if err != nil {
+				fp.reportCriticalErr(err)
+				return
+			}
+			if votedOnChain {
 				continue
 			}
 
@@ -219,15 +249,22 @@ func (fp *FinalityProviderInstance) finalitySigSubmissionLoop() {
 			res, err := fp.retrySubmitFinalitySignatureUntilBlockFinalized(&nextBlock)
 			if err != nil {
 				fp.metrics.IncrementFpTotalFailedVotes(fp.GetBtcPkHex())
+				fp.journalVote(b, proto.VoteDecision_FAILED, "", err)
 				fp.reportCriticalErr(err)
-				continue
+				return
 			}
 			if res == nil {
 				// this can happen when a finality signature is not needed
 				// either if the block is already submitted or the signature
 				// is already submitted
+				// nil is also returned if the instance is stopping, in which
+				// case no decision has been made
+				if fp.IsRunning() {
+					fp.journalVote(b, proto.VoteDecision_ALREADY_FINALIZED, "", nil)
+				}
 				continue
 			}
+			fp.journalVote(b, proto.VoteDecision_VOTED, res.TxHash, nil)
 			fp.logger.Info(
 				"successfully submitted a finality signature to the consumer chain",
 				zap.String("pk", fp.GetBtcPkHex()),
@@ -239,9 +276,10 @@ func (fp *FinalityProviderInstance) finalitySigSubmissionLoop() {
 			res, err := fp.tryFastSync(targetBlock)
 			fp.isLagging.Store(false)
 			if err != nil {
-				if errors.Is(err, bstypes.ErrFpAlreadySlashed) {
+				if errors.Is(err, bstypes.ErrFpAlreadySlashed) || errors.Is(err, store.ErrConflictingVote) ||
+					errors.Is(err, ErrFpStateNotPersisted) {
 					fp.reportCriticalErr(err)
-					continue
+					return
 				}
 				fp.logger.Debug(
 					"failed to sync up, will try again later",
@@ -261,7 +299,7 @@ func (fp *FinalityProviderInstance) finalitySigSubmissionLoop() {
 
 				// inform the poller to skip to the next block of the last
 				// processed one
-				err := fp.poller.SkipToHeight(fp.GetLastProcessedHeight() + 1)
+				err := fp.blockSub.SkipToHeight(fp.GetLastProcessedHeight() + 1)
 				if err != nil {
 					fp.logger.Debug(
 						"failed to skip heights from the poller",
@@ -389,10 +427,16 @@ func (fp *FinalityProviderInstance) hasVotingPower(b *types.BlockInfo) (bool, er
 	return true, nil
 }
 
+// reportCriticalErr reports the error to the manager, which stops the instance, so
+// the caller should not process any more blocks after reporting
+// It gives up if the instance is stopped before the error is received
 func (fp *FinalityProviderInstance) reportCriticalErr(err error) {
-	fp.criticalErrChan <- &CriticalError{
+	select {
+	case fp.criticalErrChan <- &CriticalError{
 		err:     err,
 		fpBtcPk: fp.GetBtcPkBIP340(),
+	}:
+	case <-fp.quit:
 	}
 }
 
@@ -421,7 +465,8 @@ func (fp *FinalityProviderInstance) retrySubmitFinalitySignatureUntilBlockFinali
 				zap.Error(err),
 			)
 
-			if clientcontroller.IsUnrecoverable(err) {
+			if clientcontroller.IsUnrecoverable(err) || errors.Is(err, eotstypes.ErrDoubleSign) ||
+				errors.Is(err, store.ErrConflictingVote) || errors.Is(err, ErrFpStateNotPersisted) {
 				return nil, err
 			}
 
@@ -473,6 +518,10 @@ func (fp *FinalityProviderInstance) checkBlockFinalization(height uint64) (bool,
 
 // SubmitFinalitySignature builds and sends a finality signature over the given block to the consumer chain
 func (fp *FinalityProviderInstance) SubmitFinalitySignature(b *types.BlockInfo) (*types.TxResponse, error) {
+	if err := fp.saveVoteIntents([]*types.BlockInfo{b}); err != nil {
+		return nil, err
+	}
+
 	eotsSig, err := fp.signEotsSig(b)
 	if err != nil {
 		return nil, err
@@ -485,7 +534,9 @@ func (fp *FinalityProviderInstance) SubmitFinalitySignature(b *types.BlockInfo)
 	}
 
 	// update DB
-	fp.MustUpdateStateAfterFinalitySigSubmission(b.Height)
+	if err := fp.confirmVotes([]*types.BlockInfo{b}); err != nil {
+		return nil, err
+	}
 
 	// update metrics
 	fp.metrics.RecordFpVoteTime(fp.GetBtcPkHex())
@@ -501,13 +552,13 @@ func (fp *FinalityProviderInstance) SubmitBatchFinalitySignatures(blocks []*type
 		return nil, fmt.Errorf("should not submit batch finality signature with zero block")
 	}
 
-	sigs := make([]*btcec.ModNScalar, 0, len(blocks))
-	for _, b := range blocks {
-		eotsSig, err := fp.signEotsSig(b)
-		if err != nil {
-			return nil, err
-		}
-		sigs = append(sigs, eotsSig.ToModNScalar())
+	if err := fp.saveVoteIntents(blocks); err != nil {
+		return nil, err
+	}
+
+	sigs, err := fp.signEotsSigs(blocks)
+	if err != nil {
+		return nil, err
 	}
 
 	// send finality signature to the consumer chain
@@ -517,30 +568,112 @@ func (fp *FinalityProviderInstance) SubmitBatchFinalitySignatures(blocks []*type
 	}
 
 	// update DB
-	highBlock := blocks[len(blocks)-1]
-	fp.MustUpdateStateAfterFinalitySigSubmission(highBlock.Height)
+	if err := fp.confirmVotes(blocks); err != nil {
+		return nil, err
+	}
 
 	return res, nil
 }
 
+// saveVoteIntents persists the intents to vote for the blocks before they are
+// signed, which is refused with store.ErrConflictingVote if a different block
+// has been voted for at the height of any of them
+func (fp *FinalityProviderInstance) saveVoteIntents(blocks []*types.BlockInfo) error {
+	err := fp.state.s.SaveVoteIntents(fp.GetBtcPk(), blocks)
+	if errors.Is(err, store.ErrConflictingVote) {
+		fp.logger.Error(
+			"refused to vote for a block conflicting with a voted one",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Error(err),
+		)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save the intents to vote: %w", err)
+	}
+
+	return nil
+}
+
 func (fp *FinalityProviderInstance) signEotsSig(b *types.BlockInfo) (*bbntypes.SchnorrEOTSSig, error) {
-	// build proper finality signature request
+	msgToSign := fp.finalitySigMsgToSign(b)
+	sig, err := fp.em.SignEOTS(fp.btcPk.MustMarshal(), fp.GetChainID(), msgToSign, b.Height, fp.passphrase)
+	if errors.Is(err, eotstypes.ErrDoubleSign) {
+		fp.logDoubleSignRefusal(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign EOTS: %w", err)
+	}
+
+	return bbntypes.NewSchnorrEOTSSigFromModNScalar(sig), nil
+}
+
+// signEotsSigs signs EOTS over the given blocks in a single request to the EOTS manager
+func (fp *FinalityProviderInstance) signEotsSigs(blocks []*types.BlockInfo) ([]*btcec.ModNScalar, error) {
+	msgs := make([]*eotstypes.HeightMsg, 0, len(blocks))
+	for _, b := range blocks {
+		msgs = append(msgs, &eotstypes.HeightMsg{
+			Height: b.Height,
+			Msg:    fp.finalitySigMsgToSign(b),
+		})
+	}
+
+	sigs, err := fp.em.SignEOTSBatch(fp.btcPk.MustMarshal(), fp.GetChainID(), msgs, fp.passphrase)
+	if errors.Is(err, eotstypes.ErrDoubleSign) {
+		// the whole batch is refused, so find out which blocks conflict with the records
+		for i, b := range blocks {
+			msgHash := sha256.Sum256(msgs[i].Msg)
+			record, recordErr := fp.em.SigningRecord(fp.btcPk.MustMarshal(), fp.GetChainID(), b.Height)
+			if recordErr == nil && !bytes.Equal(record.MsgHash, msgHash[:]) {
+				fp.logDoubleSignRefusal(b)
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign a batch of EOTS: %w", err)
+	}
+
+	return sigs, nil
+}
+
+// finalitySigMsgToSign builds the message of the finality signature over the given block
+func (fp *FinalityProviderInstance) finalitySigMsgToSign(b *types.BlockInfo) []byte {
 	msg := &ftypes.MsgAddFinalitySig{
 		FpBtcPk:      fp.btcPk,
 		BlockHeight:  b.Height,
 		BlockAppHash: b.Hash,
 	}
-	msgToSign := msg.MsgToSign()
-	sig, err := fp.em.SignEOTS(fp.btcPk.MustMarshal(), fp.GetChainID(), msgToSign, b.Height, fp.passphrase)
+
+	return msg.MsgToSign()
+}
+
+// logDoubleSignRefusal logs the signing record kept by the EOTS manager
+// for the height at which signing a conflicting block was refused
+func (fp *FinalityProviderInstance) logDoubleSignRefusal(b *types.BlockInfo) {
+	record, err := fp.em.SigningRecord(fp.btcPk.MustMarshal(), fp.GetChainID(), b.Height)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign EOTS: %w", err)
+		fp.logger.Error(
+			"the EOTS manager refused to double sign but the signing record cannot be retrieved",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("height", b.Height),
+			zap.Error(err),
+		)
+		return
 	}
 
-	return bbntypes.NewSchnorrEOTSSigFromModNScalar(sig), nil
+	fp.logger.Error(
+		"the EOTS manager refused to double sign",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("height", b.Height),
+		zap.String("block_hash", hex.EncodeToString(b.Hash)),
+		zap.String("signed_msg_hash", hex.EncodeToString(record.MsgHash)),
+		zap.Time("signed_at", time.Unix(record.Timestamp, 0)),
+	)
 }
 
 // TestSubmitFinalitySignatureAndExtractPrivKey is exposed for presentation/testing purpose to allow manual sending finality signature
 // this API is the same as SubmitFinalitySignature except that we don't constraint the voting height and update status
+// nor check the block against the voted ones, so that it can vote for a conflicting block
 // Note: this should not be used in the submission loop
 func (fp *FinalityProviderInstance) TestSubmitFinalitySignatureAndExtractPrivKey(b *types.BlockInfo) (*types.TxResponse, *btcec.PrivateKey, error) {
 	eotsSig, err := fp.signEotsSig(b)
@@ -1,11 +1,15 @@
 package service_test
 
 import (
+	"errors"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	bbntypes "github.com/babylonchain/babylon/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -16,6 +20,7 @@ import (
 	"github.com/babylonchain/finality-provider/finality-provider/config"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/service"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
 	"github.com/babylonchain/finality-provider/metrics"
 	"github.com/babylonchain/finality-provider/testutil"
 	"github.com/babylonchain/finality-provider/types"
@@ -57,9 +62,110 @@ func FuzzSubmitFinalitySig(f *testing.F) {
 		// check the last_voted_height
 		require.Equal(t, nextBlock.Height, fpIns.GetLastVotedHeight())
 		require.Equal(t, nextBlock.Height, fpIns.GetLastProcessedHeight())
+
+		// voting for a different block at the same height is refused before signing
+		conflictingBlock := &types.BlockInfo{
+			Height: nextBlock.Height,
+			Hash:   testutil.GenRandomByteArray(r, 32),
+		}
+		_, err = fpIns.SubmitFinalitySignature(conflictingBlock)
+		require.ErrorIs(t, err, store.ErrConflictingVote)
+		_, err = fpIns.SubmitBatchFinalitySignatures([]*types.BlockInfo{conflictingBlock})
+		require.ErrorIs(t, err, store.ErrConflictingVote)
 	})
 }
 
+// FuzzReconcileVoteIntents tests that the intents to vote left by a crash are
+// reconciled against the consumer chain when the finality provider is started
+func FuzzReconcileVoteIntents(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomRegiteredEpoch := uint64(r.Int63n(10) + 1)
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+2)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryLastFinalizedEpoch().Return(randomRegiteredEpoch, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), gomock.Any()).
+			Return(uint64(0), nil).AnyTimes()
+
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight, randomRegiteredEpoch)
+		defer cleanUp()
+
+		// the vote for the first block landed before the crash while the other did not
+		landedBlock := &types.BlockInfo{Height: randomStartingHeight + 1, Hash: testutil.GenRandomByteArray(r, 32)}
+		droppedBlock := &types.BlockInfo{Height: randomStartingHeight + 2, Hash: testutil.GenRandomByteArray(r, 32)}
+		err := app.GetFinalityProviderStore().SaveVoteIntents(fpIns.GetBtcPk(), []*types.BlockInfo{landedBlock, droppedBlock})
+		require.NoError(t, err)
+		mockClientController.EXPECT().QueryVotesAtHeight(landedBlock.Height).
+			Return([]bbntypes.BIP340PubKey{*fpIns.GetBtcPkBIP340()}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryVotesAtHeight(droppedBlock.Height).Return(nil, nil).AnyTimes()
+
+		err = fpIns.Start()
+		require.NoError(t, err)
+		defer func() {
+			err := fpIns.Stop()
+			require.NoError(t, err)
+		}()
+
+		require.Equal(t, landedBlock.Height, fpIns.GetLastVotedHeight())
+		intents, err := app.GetFinalityProviderStore().GetVoteIntents(fpIns.GetBtcPk())
+		require.NoError(t, err)
+		require.Empty(t, intents)
+		record, err := app.GetFinalityProviderStore().GetVoteRecord(fpIns.GetBtcPk(), landedBlock.Height)
+		require.NoError(t, err)
+		require.Equal(t, proto.VoteDecision_VOTED, record.Decision)
+
+		// the dropped block may only be voted for again with the same hash
+		votedHash, err := app.GetFinalityProviderStore().GetVotedBlockHash(fpIns.GetBtcPk(), droppedBlock.Height)
+		require.NoError(t, err)
+		require.Equal(t, droppedBlock.Hash, votedHash)
+	})
+}
+
+// TestStopAfterCriticalErr tests that the instance is stopped even if the critical
+// error it reports is not received, e.g., as the manager is busy
+func TestStopAfterCriticalErr(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	randomRegiteredEpoch := uint64(r.Int63n(10) + 1)
+	randomStartingHeight := uint64(r.Int63n(100) + 1)
+	currentHeight := randomStartingHeight + uint64(r.Int63n(10)+1)
+	mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+	mockClientController.EXPECT().QueryLastFinalizedEpoch().Return(randomRegiteredEpoch, nil).AnyTimes()
+	mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+	var queriedNum atomic.Uint32
+	mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ uint64) (uint64, error) {
+			queriedNum.Add(1)
+			return 0, errors.New("the consumer chain is unavailable")
+		}).AnyTimes()
+
+	_, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight, randomRegiteredEpoch)
+	defer cleanUp()
+
+	err := fpIns.Start()
+	require.NoError(t, err)
+
+	// the critical error is reported once the queries of the voting power are exhausted
+	require.Eventually(t, func() bool {
+		return queriedNum.Load() >= uint32(service.RtyAttNum)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- fpIns.Stop()
+	}()
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the instance is not stopped")
+	}
+}
+
 func startFinalityProviderAppWithRegisteredFp(t *testing.T, r *rand.Rand, cc clientcontroller.ClientController, startingHeight uint64, registeredEpoch uint64) (*service.FinalityProviderApp, *service.FinalityProviderInstance, func()) {
 	logger := zap.NewNop()
 	// create an EOTS manager
@@ -95,10 +201,15 @@ func startFinalityProviderAppWithRegisteredFp(t *testing.T, r *rand.Rand, cc cli
 
 	// TODO: use mock metrics
 	m := metrics.NewFpMetrics()
-	fpIns, err := service.NewFinalityProviderInstance(fp.GetBIP340BTCPK(), &fpCfg, app.GetFinalityProviderStore(), cc, em, m, passphrase, make(chan *service.CriticalError), logger)
+	poller := service.NewChainPoller(logger, fpCfg.PollerConfig, cc, m)
+	err = poller.Start()
+	require.NoError(t, err)
+	fpIns, err := service.NewFinalityProviderInstance(fp.GetBIP340BTCPK(), &fpCfg, app.GetFinalityProviderStore(), cc, poller, em, m, passphrase, make(chan *service.CriticalError), logger)
 	require.NoError(t, err)
 
 	cleanUp := func() {
+		err = poller.Stop()
+		require.NoError(t, err)
 		err = app.Stop()
 		require.NoError(t, err)
 		err = eotsdb.Close()
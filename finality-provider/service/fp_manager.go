@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -23,6 +24,10 @@ import (
 
 const instanceTerminatingMsg = "terminating the finality-provider instance due to critical error"
 
+// ErrFpStateNotPersisted is returned if the state of a finality provider fails to be persisted,
+// which the instance recovers from by being restarted from the persisted state
+var ErrFpStateNotPersisted = errors.New("the state of the finality provider is not persisted")
+
 type CriticalError struct {
 	err     error
 	fpBtcPk *bbntypes.BIP340PubKey
@@ -48,6 +53,9 @@ type FinalityProviderManager struct {
 	em     eotsmanager.EOTSManager
 	logger *zap.Logger
 
+	// poller polls the blocks once for all the finality-provider instances
+	poller *ChainPoller
+
 	metrics *metrics.FpMetrics
 
 	criticalErrChan chan *CriticalError
@@ -71,6 +79,7 @@ func NewFinalityProviderManager(
 		config:          config,
 		cc:              cc,
 		em:              em,
+		poller:          NewChainPoller(logger, config.PollerConfig, cc, metrics),
 		metrics:         metrics,
 		logger:          logger,
 		quit:            make(chan struct{}),
@@ -80,6 +89,9 @@ func NewFinalityProviderManager(
 // monitorCriticalErr takes actions when it receives critical errors from a finality-provider instance
 // if the finality-provider is slashed, it will be terminated and the program keeps running in case
 // new finality providers join
+// if the finality-provider refuses to vote for a conflicting block, it will be stopped likewise,
+// and only logged if it fails to stop
+// if the state of the finality-provider is not persisted, it will be restarted
 // otherwise, the program will panic
 func (fpm *FinalityProviderManager) monitorCriticalErr() {
 	defer fpm.wg.Done()
@@ -102,6 +114,30 @@ func (fpm *FinalityProviderManager) monitorCriticalErr() {
 					zap.String("pk", criticalErr.fpBtcPk.MarshalHex()))
 				continue
 			}
+			// the finality provider is asked to vote for a block conflicting with a
+			// voted one, which it keeps refusing, so it is stopped for investigation
+			if errors.Is(criticalErr.err, store.ErrConflictingVote) {
+				fpm.logger.Error("stopping the finality-provider as it is asked to vote for a conflicting block",
+					zap.String("pk", criticalErr.fpBtcPk.MarshalHex()), zap.Error(criticalErr.err))
+				// the other finality providers keep running even if this one fails
+				// to stop, as it is no longer managed and its loop has returned
+				if err := fpm.removeFinalityProviderInstance(criticalErr.fpBtcPk); err != nil {
+					fpm.logger.Error("failed to stop the finality-provider",
+						zap.String("pk", criticalErr.fpBtcPk.MarshalHex()), zap.Error(err))
+				}
+				continue
+			}
+			// the intended votes are reconciled with the consumer chain once the
+			// instance is restarted, so that nothing is lost by the restart
+			if errors.Is(criticalErr.err, ErrFpStateNotPersisted) {
+				fpm.logger.Error("restarting the finality-provider as its state is not persisted",
+					zap.String("pk", criticalErr.fpBtcPk.MarshalHex()), zap.Error(criticalErr.err))
+				// the restart waits for the instance to stop, which is done aside
+				// so that the errors of the other instances keep being received
+				fpm.wg.Add(1)
+				go fpm.restartFinalityProviderInstance(fpi)
+				continue
+			}
 			fpm.logger.Fatal(instanceTerminatingMsg,
 				zap.String("pk", criticalErr.fpBtcPk.MarshalHex()), zap.Error(criticalErr.err))
 		case <-fpm.quit:
@@ -208,6 +244,10 @@ func (fpm *FinalityProviderManager) StartFinalityProvider(fpPk *bbntypes.BIP340P
 	if !fpm.isStarted.Load() {
 		fpm.isStarted.Store(true)
 
+		if err := fpm.poller.Start(); err != nil {
+			return fmt.Errorf("failed to start the chain poller: %w", err)
+		}
+
 		fpm.wg.Add(1)
 		go fpm.monitorCriticalErr()
 
@@ -230,6 +270,10 @@ func (fpm *FinalityProviderManager) StartAll() error {
 	if !fpm.isStarted.Load() {
 		fpm.isStarted.Store(true)
 
+		if err := fpm.poller.Start(); err != nil {
+			return fmt.Errorf("failed to start the chain poller: %w", err)
+		}
+
 		fpm.wg.Add(1)
 		go fpm.monitorCriticalErr()
 
@@ -264,6 +308,10 @@ func (fpm *FinalityProviderManager) Stop() error {
 
 	var stopErr error
 
+	// quit is closed first so that no instance is restarted once they are stopped
+	close(fpm.quit)
+
+	fpm.mu.Lock()
 	for _, fpi := range fpm.fpis {
 		if !fpi.IsRunning() {
 			continue
@@ -274,10 +322,14 @@ func (fpm *FinalityProviderManager) Stop() error {
 		}
 		fpm.metrics.DecrementRunningFpGauge()
 	}
+	fpm.mu.Unlock()
 
-	close(fpm.quit)
 	fpm.wg.Wait()
 
+	if err := fpm.poller.Stop(); err != nil && stopErr == nil {
+		stopErr = err
+	}
+
 	return stopErr
 }
 
@@ -349,6 +401,8 @@ func (fpm *FinalityProviderManager) GetFinalityProviderInstance(fpPk *bbntypes.B
 	return v, nil
 }
 
+// removeFinalityProviderInstance stops the finality-provider instance and removes it
+// from the manager, which no longer considers it running even if it fails to stop
 func (fpm *FinalityProviderManager) removeFinalityProviderInstance(fpPk *bbntypes.BIP340PubKey) error {
 	fpm.mu.Lock()
 	defer fpm.mu.Unlock()
@@ -358,17 +412,43 @@ func (fpm *FinalityProviderManager) removeFinalityProviderInstance(fpPk *bbntype
 	if !exists {
 		return fmt.Errorf("cannot find the finality-provider instance with PK: %s", keyHex)
 	}
+
+	delete(fpm.fpis, keyHex)
+	fpm.metrics.DecrementRunningFpGauge()
+
 	if fpi.IsRunning() {
 		if err := fpi.Stop(); err != nil {
-			return fmt.Errorf("failed to stop the finality-provider instance %s", keyHex)
+			return fmt.Errorf("failed to stop the finality-provider instance %s: %w", keyHex, err)
 		}
 	}
 
-	delete(fpm.fpis, keyHex)
-	fpm.metrics.DecrementRunningFpGauge()
 	return nil
 }
 
+// restartFinalityProviderInstance stops the finality-provider instance and starts
+// a new one from the state in the db, unless the manager is stopping
+// NOTE: it should be run in its own goroutine with fpm.wg incremented
+func (fpm *FinalityProviderManager) restartFinalityProviderInstance(fpi *FinalityProviderInstance) {
+	defer fpm.wg.Done()
+
+	fpPk := fpi.GetBtcPkBIP340()
+	if err := fpm.removeFinalityProviderInstance(fpPk); err != nil {
+		fpm.logger.Error("failed to stop the finality-provider to restart it",
+			zap.String("pk", fpPk.MarshalHex()), zap.Error(err))
+		return
+	}
+
+	select {
+	case <-fpm.quit:
+		return
+	default:
+	}
+
+	if err := fpm.addFinalityProviderInstance(fpPk, fpi.passphrase); err != nil {
+		fpm.logger.Fatal(instanceTerminatingMsg, zap.String("pk", fpPk.MarshalHex()), zap.Error(err))
+	}
+}
+
 func (fpm *FinalityProviderManager) numOfRunningFinalityProviders() int {
 	fpm.mu.Lock()
 	defer fpm.mu.Unlock()
@@ -389,7 +469,7 @@ func (fpm *FinalityProviderManager) addFinalityProviderInstance(
 		return fmt.Errorf("finality-provider instance already exists")
 	}
 
-	fpIns, err := NewFinalityProviderInstance(pk, fpm.config, fpm.fps, fpm.cc, fpm.em, fpm.metrics, passphrase, fpm.criticalErrChan, fpm.logger)
+	fpIns, err := NewFinalityProviderInstance(pk, fpm.config, fpm.fps, fpm.cc, fpm.poller, fpm.em, fpm.metrics, passphrase, fpm.criticalErrChan, fpm.logger)
 	if err != nil {
 		return fmt.Errorf("failed to create finality-provider %s instance: %w", pkHex, err)
 	}
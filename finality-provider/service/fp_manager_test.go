@@ -152,6 +152,7 @@ func newFinalityProviderManagerWithRegisteredFp(t *testing.T, r *rand.Rand, cc c
 		testutil.RandomDescription(r),
 		testutil.ZeroCommissionRate(),
 		mpr.MarshalBase58(),
+		fpkr.RandSchemeUint32,
 		keyName,
 		chainID,
 		pop.BabylonSig,
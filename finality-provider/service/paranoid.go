@@ -0,0 +1,122 @@
+package service
+
+import (
+	"github.com/avast/retry-go/v4"
+	bbntypes "github.com/babylonchain/babylon/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// scanVotesOnChain scans the latest heights up to the given one in the paranoid mode
+// for the finality signatures of the finality provider that are not known to the db,
+// e.g., if the db is restored from an old backup or another daemon runs with the same
+// key, and warns about them
+// Nothing is marked as voted by the scan, as the heights in between without the
+// signatures are still to vote for, while each height with a signature is marked
+// as voted by checkVotedOnChain once it is processed
+func (fp *FinalityProviderInstance) scanVotesOnChain(latestHeight uint64) error {
+	window := fp.cfg.ParanoidScanWindow
+	if !fp.cfg.ParanoidMode || window == 0 {
+		return nil
+	}
+
+	startHeight := fp.GetLastProcessedHeight() + 1
+	if latestHeight >= window && latestHeight-window+1 > startHeight {
+		startHeight = latestHeight - window + 1
+	}
+
+	var votedHeights []uint64
+	for height := startHeight; height <= latestHeight; height++ {
+		voted, err := fp.hasVotedOnChain(height)
+		if err != nil {
+			return err
+		}
+		if voted {
+			votedHeights = append(votedHeights, height)
+		}
+	}
+	if len(votedHeights) == 0 {
+		return nil
+	}
+
+	fp.logger.Warn(
+		"found finality signatures on the consumer chain that are not known to the db",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("last_processed_height", fp.GetLastProcessedHeight()),
+		zap.Uint64s("voted_heights", votedHeights),
+	)
+
+	return nil
+}
+
+// checkVotedOnChain checks in the paranoid mode whether the consumer chain already
+// has the finality signature at the height of the given block, in which case the
+// block is marked as voted instead of being signed again
+func (fp *FinalityProviderInstance) checkVotedOnChain(b *types.BlockInfo) (bool, error) {
+	if !fp.cfg.ParanoidMode {
+		return false, nil
+	}
+
+	voted, err := fp.hasVotedOnChain(b.Height)
+	if err != nil || !voted {
+		return false, err
+	}
+
+	fp.logger.Warn(
+		"the consumer chain already has the finality signature at the height, skip voting",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("height", b.Height),
+	)
+	if err := fp.setLastVotedHeight(b.Height); err != nil {
+		return false, err
+	}
+	fp.journalVote(b, proto.VoteDecision_ALREADY_VOTED, "", nil)
+
+	return true, nil
+}
+
+// hasVotedOnChain returns whether the consumer chain has the finality signature
+// of the finality provider at the given height
+func (fp *FinalityProviderInstance) hasVotedOnChain(height uint64) (bool, error) {
+	votes, err := fp.queryVotesAtHeightWithRetry(height)
+	if err != nil {
+		return false, err
+	}
+
+	for _, pk := range votes {
+		if pk.Equals(fp.btcPk) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (fp *FinalityProviderInstance) queryVotesAtHeightWithRetry(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	var (
+		votes []bbntypes.BIP340PubKey
+		err   error
+	)
+
+	if err := retry.Do(func() error {
+		votes, err = fp.cc.QueryVotesAtHeight(height)
+		if err != nil {
+			return err
+		}
+		return nil
+	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+		fp.logger.Debug(
+			"failed to query the votes at height",
+			zap.Uint64("height", height),
+			zap.Uint("attempt", n+1),
+			zap.Uint("max_attempts", RtyAttNum),
+			zap.Error(err),
+		)
+	})); err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
@@ -100,6 +100,7 @@ func (r *rpcServer) CreateFinalityProvider(ctx context.Context, req *proto.Creat
 		req.ChainId,
 		req.Passphrase,
 		req.HdPath,
+		req.RandScheme,
 		&description,
 		&commissionRate,
 	)
@@ -132,10 +133,16 @@ func (r *rpcServer) RegisterFinalityProvider(ctx context.Context, req *proto.Reg
 }
 
 // AddFinalitySignature adds a manually constructed finality signature to Babylon
-// NOTE: this is only used for presentation/testing purposes
+// NOTE: this is only used for presentation/testing purposes, and it requires
+// the unsafe acknowledgement as the signature bypasses the double-vote guard
 func (r *rpcServer) AddFinalitySignature(ctx context.Context, req *proto.AddFinalitySignatureRequest) (
 	*proto.AddFinalitySignatureResponse, error) {
 
+	if !req.Unsafe {
+		return nil, fmt.Errorf("adding a finality signature manually bypasses the double-vote guard " +
+			"and could get the finality provider slashed, set unsafe to acknowledge it")
+	}
+
 	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(req.BtcPk)
 	if err != nil {
 		return nil, err
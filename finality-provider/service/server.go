@@ -14,6 +14,7 @@ import (
 
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/rpcinterceptor"
 )
 
 // Server is the main daemon construct for the Finality Provider server. It handles
@@ -79,7 +80,7 @@ func (s *Server) RunUntilShutdown() error {
 	}
 	defer lis.Close()
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(rpcinterceptor.ServerOption("fpd", s.logger, metrics.NewRPCMetrics()))
 	defer grpcServer.Stop()
 
 	if err := s.rpcServer.RegisterWithGrpcServer(grpcServer); err != nil {
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"sync"
 
 	sdkmath "cosmossdk.io/math"
@@ -25,6 +26,7 @@ type createFinalityProviderRequest struct {
 	passPhrase      string
 	hdPath          string
 	chainID         string
+	randScheme      string
 	description     *stakingtypes.Description
 	commission      *sdkmath.LegacyDec
 	errResponse     chan error
@@ -81,19 +83,55 @@ func (fps *fpState) setStatus(s proto.FinalityProviderStatus) error {
 	return fps.s.SetFpStatus(fps.fp.BtcPk, s)
 }
 
+// setLastProcessedHeight raises the last processed height, which never decreases
+// as in the store, and the height is only kept in memory once it is persisted
 func (fps *fpState) setLastProcessedHeight(height uint64) error {
+	if err := fps.s.SetFpLastProcessedHeight(fps.fp.BtcPk, height); err != nil {
+		return err
+	}
+
 	fps.mu.Lock()
-	fps.fp.LastProcessedHeight = height
-	fps.mu.Unlock()
-	return fps.s.SetFpLastProcessedHeight(fps.fp.BtcPk, height)
+	defer fps.mu.Unlock()
+	if fps.fp.LastProcessedHeight < height {
+		fps.fp.LastProcessedHeight = height
+	}
+
+	return nil
 }
 
+// setLastProcessedAndVotedHeight raises the last voted and processed heights
+// likewise
 func (fps *fpState) setLastProcessedAndVotedHeight(height uint64) error {
+	if err := fps.s.SetFpLastVotedHeight(fps.fp.BtcPk, height); err != nil {
+		return err
+	}
+
+	fps.raiseLastProcessedAndVotedHeight(height)
+	return nil
+}
+
+// confirmVotes removes the intents to vote for the given blocks and raises the last
+// voted and processed heights to the highest of them
+func (fps *fpState) confirmVotes(blocks []*types.BlockInfo) error {
+	if err := fps.s.ConfirmVotes(fps.fp.BtcPk, blocks); err != nil {
+		return err
+	}
+
+	for _, b := range blocks {
+		fps.raiseLastProcessedAndVotedHeight(b.Height)
+	}
+	return nil
+}
+
+func (fps *fpState) raiseLastProcessedAndVotedHeight(height uint64) {
 	fps.mu.Lock()
-	fps.fp.LastVotedHeight = height
-	fps.fp.LastProcessedHeight = height
-	fps.mu.Unlock()
-	return fps.s.SetFpLastVotedHeight(fps.fp.BtcPk, height)
+	defer fps.mu.Unlock()
+	if fps.fp.LastVotedHeight < height {
+		fps.fp.LastVotedHeight = height
+	}
+	if fps.fp.LastProcessedHeight < height {
+		fps.fp.LastProcessedHeight = height
+	}
 }
 
 func (fp *FinalityProviderInstance) GetStoreFinalityProvider() *store.StoredFinalityProvider {
@@ -139,29 +177,45 @@ func (fp *FinalityProviderInstance) MustSetStatus(s proto.FinalityProviderStatus
 	}
 }
 
+// SetLastProcessedHeight raises the last processed height
+// ErrFpStateNotPersisted is returned if the height fails to be persisted
 func (fp *FinalityProviderInstance) SetLastProcessedHeight(height uint64) error {
-	return fp.state.setLastProcessedHeight(height)
+	if err := fp.state.setLastProcessedHeight(height); err != nil {
+		return fmt.Errorf("%w: failed to set the last processed height %d: %v",
+			ErrFpStateNotPersisted, height, err)
+	}
+	fp.metrics.RecordFpLastProcessedHeight(fp.GetBtcPkHex(), fp.GetLastProcessedHeight())
+
+	return nil
 }
 
-func (fp *FinalityProviderInstance) MustSetLastProcessedHeight(height uint64) {
-	if err := fp.SetLastProcessedHeight(height); err != nil {
-		fp.logger.Fatal("failed to set last processed height",
-			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("last_processed_height", height))
+// setLastVotedHeight raises the last voted height to the height of a vote that
+// is found on the consumer chain
+// ErrFpStateNotPersisted is returned if the height fails to be persisted
+func (fp *FinalityProviderInstance) setLastVotedHeight(height uint64) error {
+	if err := fp.state.setLastProcessedAndVotedHeight(height); err != nil {
+		return fmt.Errorf("%w: failed to set the last voted height %d: %v",
+			ErrFpStateNotPersisted, height, err)
 	}
-	fp.metrics.RecordFpLastProcessedHeight(fp.GetBtcPkHex(), height)
-}
+	fp.recordVotedHeightMetrics()
 
-func (fp *FinalityProviderInstance) updateStateAfterFinalitySigSubmission(height uint64) error {
-	return fp.state.setLastProcessedAndVotedHeight(height)
+	return nil
 }
 
-func (fp *FinalityProviderInstance) MustUpdateStateAfterFinalitySigSubmission(height uint64) {
-	if err := fp.updateStateAfterFinalitySigSubmission(height); err != nil {
-		fp.logger.Fatal("failed to update state after finality signature submitted",
-			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", height))
+// confirmVotes marks the given blocks as voted once their votes are on the consumer chain
+// ErrFpStateNotPersisted is returned if the votes fail to be persisted
+func (fp *FinalityProviderInstance) confirmVotes(blocks []*types.BlockInfo) error {
+	if err := fp.state.confirmVotes(blocks); err != nil {
+		return fmt.Errorf("%w: failed to confirm the votes: %v", ErrFpStateNotPersisted, err)
 	}
-	fp.metrics.RecordFpLastVotedHeight(fp.GetBtcPkHex(), height)
-	fp.metrics.RecordFpLastProcessedHeight(fp.GetBtcPkHex(), height)
+	fp.recordVotedHeightMetrics()
+
+	return nil
+}
+
+func (fp *FinalityProviderInstance) recordVotedHeightMetrics() {
+	fp.metrics.RecordFpLastVotedHeight(fp.GetBtcPkHex(), fp.GetLastVotedHeight())
+	fp.metrics.RecordFpLastProcessedHeight(fp.GetBtcPkHex(), fp.GetLastProcessedHeight())
 }
 
 func (fp *FinalityProviderInstance) getEOTSPrivKey() (*btcec.PrivateKey, error) {
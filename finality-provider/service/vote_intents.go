@@ -0,0 +1,62 @@
+package service
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// reconcileVoteIntents reconciles the intents to vote that are not confirmed,
+// e.g., if the daemon stopped between broadcasting the votes and persisting
+// them, against the consumer chain
+// The votes on the chain are confirmed, which raises the last voted height,
+// while the other intents are dropped so that the blocks are voted for again
+func (fp *FinalityProviderInstance) reconcileVoteIntents() error {
+	intents, err := fp.state.s.GetVoteIntents(fp.GetBtcPk())
+	if err != nil {
+		return err
+	}
+	if len(intents) == 0 {
+		return nil
+	}
+
+	var landed, dropped []*types.BlockInfo
+	for _, b := range intents {
+		voted, err := fp.hasVotedOnChain(b.Height)
+		if err != nil {
+			return err
+		}
+		if voted {
+			landed = append(landed, b)
+		} else {
+			dropped = append(dropped, b)
+		}
+	}
+
+	if len(dropped) > 0 {
+		if err := fp.state.s.DropVoteIntents(fp.GetBtcPk(), dropped); err != nil {
+			return err
+		}
+		fp.logger.Info(
+			"dropped the intents to vote that are not on the consumer chain",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Int("num_intents", len(dropped)),
+		)
+	}
+
+	if len(landed) > 0 {
+		if err := fp.confirmVotes(landed); err != nil {
+			return err
+		}
+		fp.journalVotes(landed, proto.VoteDecision_VOTED, "", nil)
+		fp.logger.Warn(
+			"confirmed the votes on the consumer chain that were not persisted",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Int("num_votes", len(landed)),
+			zap.Uint64("last_voted_height", fp.GetLastVotedHeight()),
+		)
+	}
+
+	return nil
+}
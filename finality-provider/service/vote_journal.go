@@ -0,0 +1,57 @@
+package service
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// journalVote records the decision made on the given block in the vote journal
+// and prunes the records out of the retention
+// The journal is only for investigation, so that failing to write it is logged
+// without interrupting the voting
+func (fp *FinalityProviderInstance) journalVote(b *types.BlockInfo, decision proto.VoteDecision, txHash string, voteErr error) {
+	record := &store.VoteRecord{
+		BtcPk:     fp.GetBtcPk(),
+		Height:    b.Height,
+		BlockHash: b.Hash,
+		Decision:  decision,
+		TxHash:    txHash,
+	}
+	if voteErr != nil {
+		record.Error = voteErr.Error()
+	}
+
+	if err := fp.state.s.SaveVoteRecord(record); err != nil {
+		fp.logger.Warn(
+			"failed to journal the vote",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("height", b.Height),
+			zap.String("decision", decision.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	retention := fp.cfg.VoteJournalRetention
+	if retention == 0 || b.Height <= retention {
+		return
+	}
+	if _, err := fp.state.s.PruneVoteRecords(fp.GetBtcPk(), b.Height-retention); err != nil {
+		fp.logger.Warn(
+			"failed to prune the vote journal",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("below_height", b.Height-retention),
+			zap.Error(err),
+		)
+	}
+}
+
+// journalVotes records the same decision made on the given blocks
+func (fp *FinalityProviderInstance) journalVotes(blocks []*types.BlockInfo, decision proto.VoteDecision, txHash string, voteErr error) {
+	for _, b := range blocks {
+		fp.journalVote(b, decision, txHash, voteErr)
+	}
+}
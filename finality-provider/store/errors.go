@@ -11,4 +11,14 @@ var (
 
 	// ErrDuplicateFinalityProvider The finality provider we try to add already exists in db
 	ErrDuplicateFinalityProvider = errors.New("finality provider already exists")
+
+	// ErrVoteRecordNotFound The block at the given height has not been journaled
+	ErrVoteRecordNotFound = errors.New("vote record not found")
+
+	// ErrVotedBlockNotFound No block has been voted for at the given height
+	ErrVotedBlockNotFound = errors.New("voted block not found")
+
+	// ErrConflictingVote A different block has been voted for at the same height,
+	// so that voting for the block could get the finality provider slashed
+	ErrConflictingVote = errors.New("a different block has been voted for at the same height")
 )
@@ -19,6 +19,13 @@ import (
 var (
 	// mapping pk -> proto.FinalityProvider
 	finalityProviderBucketName = []byte("finalityProviders")
+	// mapping pk -> height -> proto.VoteRecord
+	voteJournalBucketName = []byte("voteJournal")
+	// mapping pk -> height -> hash of the block voted for
+	votedBlockBucketName = []byte("votedBlocks")
+	// mapping pk -> height -> hash of the block intended to vote for,
+	// whose vote is not confirmed yet
+	voteIntentBucketName = []byte("voteIntents")
 )
 
 type FinalityProviderStore struct {
@@ -27,19 +34,11 @@ type FinalityProviderStore struct {
 
 // NewFinalityProviderStore returns a new store backed by db
 func NewFinalityProviderStore(db kvdb.Backend) (*FinalityProviderStore, error) {
-	store := &FinalityProviderStore{db}
-	if err := store.initBuckets(); err != nil {
-		return nil, err
+	if _, err := Migrate(db, false); err != nil {
+		return nil, fmt.Errorf("failed to migrate the database: %w", err)
 	}
 
-	return store, nil
-}
-
-func (s *FinalityProviderStore) initBuckets() error {
-	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
-		_, err := tx.CreateTopLevelBucket(finalityProviderBucketName)
-		return err
-	})
+	return &FinalityProviderStore{db}, nil
 }
 
 func (s *FinalityProviderStore) CreateFinalityProvider(
@@ -47,7 +46,7 @@ func (s *FinalityProviderStore) CreateFinalityProvider(
 	btcPk *btcec.PublicKey,
 	description *stakingtypes.Description,
 	commission *sdkmath.LegacyDec,
-	masterPubRand string,
+	masterPubRand, masterPubRandScheme string,
 	keyName, chainId string,
 	chainSig, btcSig []byte,
 ) error {
@@ -64,11 +63,12 @@ func (s *FinalityProviderStore) CreateFinalityProvider(
 			ChainSig: chainSig,
 			BtcSig:   btcSig,
 		},
-		RegisteredEpoch: math.MaxUint64,
-		MasterPubRand:   masterPubRand,
-		KeyName:         keyName,
-		ChainId:         chainId,
-		Status:          proto.FinalityProviderStatus_CREATED,
+		RegisteredEpoch:     math.MaxUint64,
+		MasterPubRand:       masterPubRand,
+		MasterPubRandScheme: masterPubRandScheme,
+		KeyName:             keyName,
+		ChainId:             chainId,
+		Status:              proto.FinalityProviderStatus_CREATED,
 	}
 
 	return s.createFinalityProviderInternal(fp)
@@ -129,7 +129,11 @@ func (s *FinalityProviderStore) SetFpRegisteredEpoch(btcPk *btcec.PublicKey, reg
 // SetFpLastVotedHeight sets the last voted height to the stored last voted height and last processed height
 // only if it is larger than the stored one. This is to ensure the stored state to increase monotonically
 func (s *FinalityProviderStore) SetFpLastVotedHeight(btcPk *btcec.PublicKey, lastVotedHeight uint64) error {
-	setFpLastVotedHeight := func(fp *proto.FinalityProvider) error {
+	return s.setFinalityProviderState(btcPk, raiseFpLastVotedHeight(lastVotedHeight))
+}
+
+func raiseFpLastVotedHeight(lastVotedHeight uint64) func(fp *proto.FinalityProvider) error {
+	return func(fp *proto.FinalityProvider) error {
 		if fp.LastVotedHeight < lastVotedHeight {
 			fp.LastVotedHeight = lastVotedHeight
 		}
@@ -139,8 +143,6 @@ func (s *FinalityProviderStore) SetFpLastVotedHeight(btcPk *btcec.PublicKey, las
 
 		return nil
 	}
-
-	return s.setFinalityProviderState(btcPk, setFpLastVotedHeight)
 }
 
 // SetFpLastProcessedHeight sets the last processed height to the stored last processed height
@@ -163,27 +165,35 @@ func (s *FinalityProviderStore) setFinalityProviderState(
 ) error {
 	pkBytes := schnorr.SerializePubKey(btcPk)
 	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
-		fpBucket := tx.ReadWriteBucket(finalityProviderBucketName)
-		if fpBucket == nil {
-			return ErrCorruptedFinalityProviderDb
-		}
+		return updateFinalityProviderState(tx, pkBytes, stateTransitionFn)
+	})
+}
 
-		fpFromDb := fpBucket.Get(pkBytes)
-		if fpFromDb == nil {
-			return ErrFinalityProviderNotFound
-		}
+func updateFinalityProviderState(
+	tx kvdb.RwTx,
+	pkBytes []byte,
+	stateTransitionFn func(provider *proto.FinalityProvider) error,
+) error {
+	fpBucket := tx.ReadWriteBucket(finalityProviderBucketName)
+	if fpBucket == nil {
+		return ErrCorruptedFinalityProviderDb
+	}
 
-		var storedFp proto.FinalityProvider
-		if err := pm.Unmarshal(fpFromDb, &storedFp); err != nil {
-			return ErrCorruptedFinalityProviderDb
-		}
+	fpFromDb := fpBucket.Get(pkBytes)
+	if fpFromDb == nil {
+		return ErrFinalityProviderNotFound
+	}
 
-		if err := stateTransitionFn(&storedFp); err != nil {
-			return err
-		}
+	var storedFp proto.FinalityProvider
+	if err := pm.Unmarshal(fpFromDb, &storedFp); err != nil {
+		return ErrCorruptedFinalityProviderDb
+	}
 
-		return saveFinalityProvider(fpBucket, &storedFp)
-	})
+	if err := stateTransitionFn(&storedFp); err != nil {
+		return err
+	}
+
+	return saveFinalityProvider(fpBucket, &storedFp)
 }
 
 func (s *FinalityProviderStore) GetFinalityProvider(btcPk *btcec.PublicKey) (*StoredFinalityProvider, error) {
@@ -9,8 +9,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	fpstore "github.com/babylonchain/finality-provider/finality-provider/store"
 	"github.com/babylonchain/finality-provider/testutil"
+	"github.com/babylonchain/finality-provider/types"
 )
 
 // FuzzFinalityProvidersStore tests save and list finality providers properly
@@ -42,6 +44,7 @@ func FuzzFinalityProvidersStore(f *testing.F) {
 			fp.Description,
 			fp.Commission,
 			fp.MasterPubRand,
+			fp.MasterPubRandScheme,
 			fp.KeyName,
 			fp.ChainID,
 			fp.Pop.ChainSig,
@@ -57,6 +60,7 @@ func FuzzFinalityProvidersStore(f *testing.F) {
 			fp.Description,
 			fp.Commission,
 			fp.KeyName,
+			fp.MasterPubRandScheme,
 			fp.MasterPubRand,
 			fp.ChainID,
 			fp.Pop.ChainSig,
@@ -78,3 +82,220 @@ func FuzzFinalityProvidersStore(f *testing.F) {
 		require.ErrorIs(t, err, fpstore.ErrFinalityProviderNotFound)
 	})
 }
+
+// FuzzVoteJournal tests that the vote records are saved, queried by range and pruned properly
+func FuzzVoteJournal(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homePath := t.TempDir()
+		cfg := config.DefaultDBConfigWithHomePath(homePath)
+
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+		defer func() {
+			err := fpdb.Close()
+			require.NoError(t, err)
+		}()
+
+		_, btcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+
+		// nothing has been journaled yet
+		_, err = vs.GetVoteRecord(btcPk, 1)
+		require.ErrorIs(t, err, fpstore.ErrVoteRecordNotFound)
+		records, err := vs.GetVoteRecords(btcPk, 1, 100)
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		startHeight := uint64(r.Int63n(100) + 1)
+		numRecords := uint64(r.Int63n(20) + 2)
+		endHeight := startHeight + numRecords - 1
+		for h := startHeight; h <= endHeight; h++ {
+			err := vs.SaveVoteRecord(&fpstore.VoteRecord{
+				BtcPk:     btcPk,
+				Height:    h,
+				BlockHash: testutil.GenRandomByteArray(r, 32),
+				Decision:  proto.VoteDecision_FAILED,
+				Error:     "failed to submit",
+			})
+			require.NoError(t, err)
+		}
+
+		// a failed vote that later succeeds is overwritten
+		failed, err := vs.GetVoteRecord(btcPk, endHeight)
+		require.NoError(t, err)
+		require.Equal(t, proto.VoteDecision_FAILED, failed.Decision)
+		txHash := testutil.GenRandomHexStr(r, 32)
+		err = vs.SaveVoteRecord(&fpstore.VoteRecord{
+			BtcPk:     btcPk,
+			Height:    endHeight,
+			BlockHash: failed.BlockHash,
+			Decision:  proto.VoteDecision_VOTED,
+			TxHash:    txHash,
+		})
+		require.NoError(t, err)
+		voted, err := vs.GetVoteRecord(btcPk, endHeight)
+		require.NoError(t, err)
+		require.Equal(t, proto.VoteDecision_VOTED, voted.Decision)
+		require.Equal(t, txHash, voted.TxHash)
+		require.Empty(t, voted.Error)
+		require.Equal(t, failed.CreatedAt, voted.CreatedAt)
+
+		// the range is inclusive and in the ascending order of height
+		queryStart := startHeight + uint64(r.Int63n(int64(numRecords)))
+		records, err = vs.GetVoteRecords(btcPk, queryStart, endHeight+10)
+		require.NoError(t, err)
+		require.Len(t, records, int(endHeight-queryStart+1))
+		for i, record := range records {
+			require.Equal(t, queryStart+uint64(i), record.Height)
+		}
+
+		pruned, err := vs.PruneVoteRecords(btcPk, queryStart)
+		require.NoError(t, err)
+		require.Equal(t, int(queryStart-startHeight), pruned)
+		records, err = vs.GetVoteRecords(btcPk, 0, endHeight)
+		require.NoError(t, err)
+		require.Len(t, records, int(endHeight-queryStart+1))
+		require.Equal(t, queryStart, records[0].Height)
+	})
+}
+
+// FuzzVotedBlocks tests that voting for a block conflicting with a voted one is refused
+func FuzzVotedBlocks(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homePath := t.TempDir()
+		cfg := config.DefaultDBConfigWithHomePath(homePath)
+
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+		defer func() {
+			err := fpdb.Close()
+			require.NoError(t, err)
+		}()
+
+		_, btcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+
+		startHeight := uint64(r.Int63n(100) + 1)
+		blocks := testutil.GenBlocks(r, startHeight, startHeight+uint64(r.Int63n(10)+1))
+		err = vs.SaveVotedBlocks(btcPk, blocks)
+		require.NoError(t, err)
+		for _, b := range blocks {
+			votedHash, err := vs.GetVotedBlockHash(btcPk, b.Height)
+			require.NoError(t, err)
+			require.Equal(t, b.Hash, votedHash)
+		}
+
+		// voting for the same blocks again is allowed
+		err = vs.SaveVotedBlocks(btcPk, blocks[len(blocks)-1:])
+		require.NoError(t, err)
+
+		// nothing is saved if any of the blocks conflicts with a voted one
+		lastBlock := blocks[len(blocks)-1]
+		newBlock := &types.BlockInfo{Height: lastBlock.Height + 1, Hash: testutil.GenRandomByteArray(r, 32)}
+		conflictingBlock := &types.BlockInfo{Height: lastBlock.Height, Hash: testutil.GenRandomByteArray(r, 32)}
+		err = vs.SaveVotedBlocks(btcPk, []*types.BlockInfo{newBlock, conflictingBlock})
+		require.ErrorIs(t, err, fpstore.ErrConflictingVote)
+		_, err = vs.GetVotedBlockHash(btcPk, newBlock.Height)
+		require.ErrorIs(t, err, fpstore.ErrVotedBlockNotFound)
+		votedHash, err := vs.GetVotedBlockHash(btcPk, lastBlock.Height)
+		require.NoError(t, err)
+		require.Equal(t, lastBlock.Hash, votedHash)
+	})
+}
+
+// FuzzVoteIntents tests that the vote intents are saved along with the voted blocks,
+// and that confirming them raises the last voted height of the finality provider
+func FuzzVoteIntents(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homePath := t.TempDir()
+		cfg := config.DefaultDBConfigWithHomePath(homePath)
+
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+		defer func() {
+			err := fpdb.Close()
+			require.NoError(t, err)
+		}()
+
+		fp := testutil.GenRandomFinalityProvider(r, t)
+		err = vs.CreateFinalityProvider(
+			fp.ChainPk,
+			fp.BtcPk,
+			fp.Description,
+			fp.Commission,
+			fp.MasterPubRand,
+			fp.MasterPubRandScheme,
+			fp.KeyName,
+			fp.ChainID,
+			fp.Pop.ChainSig,
+			fp.Pop.BtcSig,
+		)
+		require.NoError(t, err)
+
+		startHeight := uint64(r.Int63n(100) + 1)
+		blocks := testutil.GenBlocks(r, startHeight, startHeight+uint64(r.Int63n(10)+1))
+		err = vs.SaveVoteIntents(fp.BtcPk, blocks)
+		require.NoError(t, err)
+		intents, err := vs.GetVoteIntents(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, blocks, intents)
+		for _, b := range blocks {
+			votedHash, err := vs.GetVotedBlockHash(fp.BtcPk, b.Height)
+			require.NoError(t, err)
+			require.Equal(t, b.Hash, votedHash)
+		}
+
+		// no intent is saved if any of the blocks conflicts with a voted one
+		lastBlock := blocks[len(blocks)-1]
+		newBlock := &types.BlockInfo{Height: lastBlock.Height + 1, Hash: testutil.GenRandomByteArray(r, 32)}
+		conflictingBlock := &types.BlockInfo{Height: lastBlock.Height, Hash: testutil.GenRandomByteArray(r, 32)}
+		err = vs.SaveVoteIntents(fp.BtcPk, []*types.BlockInfo{newBlock, conflictingBlock})
+		require.ErrorIs(t, err, fpstore.ErrConflictingVote)
+		intents, err = vs.GetVoteIntents(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, blocks, intents)
+
+		// the first blocks are confirmed and the others are dropped
+		confirmedNum := r.Intn(len(blocks)) + 1
+		err = vs.ConfirmVotes(fp.BtcPk, blocks[:confirmedNum])
+		require.NoError(t, err)
+		storedFp, err := vs.GetFinalityProvider(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, blocks[confirmedNum-1].Height, storedFp.LastVotedHeight)
+		intents, err = vs.GetVoteIntents(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, len(blocks)-confirmedNum, len(intents))
+
+		err = vs.DropVoteIntents(fp.BtcPk, blocks[confirmedNum:])
+		require.NoError(t, err)
+		intents, err = vs.GetVoteIntents(fp.BtcPk)
+		require.NoError(t, err)
+		require.Empty(t, intents)
+		// the dropped blocks are still the voted ones
+		votedHash, err := vs.GetVotedBlockHash(fp.BtcPk, lastBlock.Height)
+		require.NoError(t, err)
+		require.Equal(t, lastBlock.Hash, votedHash)
+
+		// confirming lower votes does not lower the last voted height
+		err = vs.ConfirmVotes(fp.BtcPk, blocks[:1])
+		require.NoError(t, err)
+		storedFp, err = vs.GetFinalityProvider(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, blocks[confirmedNum-1].Height, storedFp.LastVotedHeight)
+	})
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/migration"
+)
+
+// migrations are the ordered schema migrations of the finality provider db
+// NOTE: a released migration must never be changed, append a new one instead
+var migrations = []*migration.Migration{
+	{
+		Version:     1,
+		Description: "create the bucket of finality providers",
+		Migrate:     migration.CreateTopLevelBuckets(finalityProviderBucketName),
+	},
+	{
+		Version:     2,
+		Description: "create the bucket of the vote journal",
+		Migrate:     migration.CreateTopLevelBuckets(voteJournalBucketName),
+	},
+	{
+		Version:     3,
+		Description: "create the bucket of the voted blocks",
+		Migrate:     migration.CreateTopLevelBuckets(votedBlockBucketName),
+	},
+	{
+		Version:     4,
+		Description: "create the bucket of the vote intents",
+		Migrate:     migration.CreateTopLevelBuckets(voteIntentBucketName),
+	},
+}
+
+// Migrate applies the pending schema migrations of the finality provider db
+// If dryRun is true, nothing is written and the report tells what would change
+func Migrate(db kvdb.Backend, dryRun bool) (*migration.Report, error) {
+	return migration.Run(db, migrations, dryRun)
+}
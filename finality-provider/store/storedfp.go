@@ -12,6 +12,7 @@ import (
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
 )
 
 type StoredFinalityProvider struct {
@@ -22,6 +23,7 @@ type StoredFinalityProvider struct {
 	Pop                 *proto.ProofOfPossession
 	RegisteredEpoch     uint64
 	MasterPubRand       string
+	MasterPubRandScheme string
 	KeyName             string
 	ChainID             string
 	LastVotedHeight     uint64
@@ -46,6 +48,13 @@ func protoFpToStoredFinalityProvider(fp *proto.FinalityProvider) (*StoredFinalit
 		return nil, fmt.Errorf("invalid commission: %w", err)
 	}
 
+	// the finality providers created before the randomness derivation
+	// schemes were introduced use the uint32 scheme
+	masterPubRandScheme := fp.MasterPubRandScheme
+	if masterPubRandScheme == "" {
+		masterPubRandScheme = fpkeyring.RandSchemeUint32
+	}
+
 	return &StoredFinalityProvider{
 		ChainPk:     chainPk,
 		BtcPk:       btcPk,
@@ -57,6 +66,7 @@ func protoFpToStoredFinalityProvider(fp *proto.FinalityProvider) (*StoredFinalit
 		},
 		RegisteredEpoch:     fp.RegisteredEpoch,
 		MasterPubRand:       fp.MasterPubRand,
+		MasterPubRandScheme: masterPubRandScheme,
 		KeyName:             fp.KeyName,
 		ChainID:             fp.ChainId,
 		LastVotedHeight:     fp.LastVotedHeight,
@@ -84,11 +94,12 @@ func (sfp *StoredFinalityProvider) ToFinalityProviderInfo() *proto.FinalityProvi
 			SecurityContact: sfp.Description.SecurityContact,
 			Details:         sfp.Description.Details,
 		},
-		Pop:             sfp.Pop,
-		RegisteredEpoch: sfp.RegisteredEpoch,
-		MasterPubRand:   sfp.MasterPubRand,
-		Commission:      sfp.Commission.String(),
-		LastVotedHeight: sfp.LastVotedHeight,
-		Status:          sfp.Status.String(),
+		Pop:                 sfp.Pop,
+		RegisteredEpoch:     sfp.RegisteredEpoch,
+		MasterPubRand:       sfp.MasterPubRand,
+		MasterPubRandScheme: sfp.MasterPubRandScheme,
+		Commission:          sfp.Commission.String(),
+		LastVotedHeight:     sfp.LastVotedHeight,
+		Status:              sfp.Status.String(),
 	}
 }
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// SaveVotedBlocks saves the hashes of the given blocks as the ones voted for at
+// their heights, which should be done before the finality signatures are made
+// ErrConflictingVote is returned and nothing is saved if a different block has
+// been voted for at the height of any of the blocks, while voting for the same
+// block again is allowed
+// NOTE: the voted blocks are never pruned, as a signature over a different block
+// at any height leaks the EOTS key
+func (s *FinalityProviderStore) SaveVotedBlocks(btcPk *btcec.PublicKey, blocks []*types.BlockInfo) error {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		return saveVotedBlocks(tx, pkBytes, blocks)
+	})
+}
+
+func saveVotedBlocks(tx kvdb.RwTx, pkBytes []byte, blocks []*types.BlockInfo) error {
+	votedBucket := tx.ReadWriteBucket(votedBlockBucketName)
+	if votedBucket == nil {
+		return ErrCorruptedFinalityProviderDb
+	}
+
+	fpBucket, err := votedBucket.CreateBucketIfNotExists(pkBytes)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range blocks {
+		votedHash := fpBucket.Get(heightToKey(b.Height))
+		if votedHash == nil {
+			if err := fpBucket.Put(heightToKey(b.Height), b.Hash); err != nil {
+				return err
+			}
+			continue
+		}
+		if !bytes.Equal(votedHash, b.Hash) {
+			return fmt.Errorf("%w: the block %s has been voted for at height %d, while the block is %s",
+				ErrConflictingVote, hex.EncodeToString(votedHash), b.Height, hex.EncodeToString(b.Hash))
+		}
+	}
+
+	return nil
+}
+
+// GetVotedBlockHash returns the hash of the block voted for at the given height
+func (s *FinalityProviderStore) GetVotedBlockHash(btcPk *btcec.PublicKey, height uint64) ([]byte, error) {
+	var votedHash []byte
+	err := s.db.View(func(tx kvdb.RTx) error {
+		votedBucket := tx.ReadBucket(votedBlockBucketName)
+		if votedBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		fpBucket := votedBucket.NestedReadBucket(schnorr.SerializePubKey(btcPk))
+		if fpBucket == nil {
+			return ErrVotedBlockNotFound
+		}
+
+		hashBytes := fpBucket.Get(heightToKey(height))
+		if hashBytes == nil {
+			return ErrVotedBlockNotFound
+		}
+
+		votedHash = append([]byte{}, hashBytes...)
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return votedHash, nil
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"encoding/binary"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// SaveVoteIntents records the intents to vote for the given blocks, which should
+// be done before the finality signatures are made, so that the votes that may
+// have been broadcast without being confirmed can be reconciled on restart
+// The blocks are saved as the voted ones in the same transaction, and
+// ErrConflictingVote is returned and nothing is saved as in SaveVotedBlocks
+func (s *FinalityProviderStore) SaveVoteIntents(btcPk *btcec.PublicKey, blocks []*types.BlockInfo) error {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		if err := saveVotedBlocks(tx, pkBytes, blocks); err != nil {
+			return err
+		}
+
+		fpBucket, err := voteIntentRwBucket(tx, pkBytes)
+		if err != nil {
+			return err
+		}
+
+		for _, b := range blocks {
+			if err := fpBucket.Put(heightToKey(b.Height), b.Hash); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ConfirmVotes removes the intents to vote for the given blocks and raises the
+// last voted height to the highest of them in a single transaction, once the
+// votes are known to be on the consumer chain
+func (s *FinalityProviderStore) ConfirmVotes(btcPk *btcec.PublicKey, blocks []*types.BlockInfo) error {
+	var highestHeight uint64
+	for _, b := range blocks {
+		if b.Height > highestHeight {
+			highestHeight = b.Height
+		}
+	}
+
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		if err := deleteVoteIntents(tx, pkBytes, blocks); err != nil {
+			return err
+		}
+
+		return updateFinalityProviderState(tx, pkBytes, raiseFpLastVotedHeight(highestHeight))
+	})
+}
+
+// DropVoteIntents removes the intents to vote for the given blocks without
+// confirming the votes, e.g., if the votes are not on the consumer chain
+// The blocks are kept as the voted ones, as their signatures may have been made
+func (s *FinalityProviderStore) DropVoteIntents(btcPk *btcec.PublicKey, blocks []*types.BlockInfo) error {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		return deleteVoteIntents(tx, pkBytes, blocks)
+	})
+}
+
+// GetVoteIntents returns the blocks intended to vote for whose votes
+// are not confirmed, in the ascending order of height
+func (s *FinalityProviderStore) GetVoteIntents(btcPk *btcec.PublicKey) ([]*types.BlockInfo, error) {
+	var blocks []*types.BlockInfo
+	err := s.db.View(func(tx kvdb.RTx) error {
+		intentBucket := tx.ReadBucket(voteIntentBucketName)
+		if intentBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		fpBucket := intentBucket.NestedReadBucket(schnorr.SerializePubKey(btcPk))
+		if fpBucket == nil {
+			return nil
+		}
+
+		return fpBucket.ForEach(func(k, v []byte) error {
+			blocks = append(blocks, &types.BlockInfo{
+				Height: binary.BigEndian.Uint64(k),
+				Hash:   append([]byte{}, v...),
+			})
+			return nil
+		})
+	}, func() {
+		blocks = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+func voteIntentRwBucket(tx kvdb.RwTx, pkBytes []byte) (walletdb.ReadWriteBucket, error) {
+	intentBucket := tx.ReadWriteBucket(voteIntentBucketName)
+	if intentBucket == nil {
+		return nil, ErrCorruptedFinalityProviderDb
+	}
+
+	return intentBucket.CreateBucketIfNotExists(pkBytes)
+}
+
+func deleteVoteIntents(tx kvdb.RwTx, pkBytes []byte, blocks []*types.BlockInfo) error {
+	fpBucket, err := voteIntentRwBucket(tx, pkBytes)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range blocks {
+		if err := fpBucket.Delete(heightToKey(b.Height)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
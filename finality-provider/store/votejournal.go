@@ -0,0 +1,232 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// VoteRecord is the journal entry of the decision made on a block
+type VoteRecord struct {
+	BtcPk     *btcec.PublicKey
+	Height    uint64
+	BlockHash []byte
+	Decision  proto.VoteDecision
+	// TxHash is empty if no finality signature has been submitted
+	TxHash string
+	// Error is the reason of the failure if the decision is FAILED
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SaveVoteRecord journals the decision made on a block
+// If the block at the same height has been journaled, the record is overwritten
+// while its creation time is kept, e.g., a failed vote that later succeeds
+func (s *FinalityProviderStore) SaveVoteRecord(record *VoteRecord) error {
+	if record == nil || record.BtcPk == nil {
+		return fmt.Errorf("cannot save nil vote record")
+	}
+
+	pkBytes := schnorr.SerializePubKey(record.BtcPk)
+	now := time.Now().Unix()
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		journalBucket := tx.ReadWriteBucket(voteJournalBucketName)
+		if journalBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		fpBucket, err := journalBucket.CreateBucketIfNotExists(pkBytes)
+		if err != nil {
+			return err
+		}
+
+		createdAt := now
+		if existing := fpBucket.Get(heightToKey(record.Height)); existing != nil {
+			existingRecord, err := unmarshalVoteRecord(existing)
+			if err != nil {
+				return err
+			}
+			createdAt = existingRecord.CreatedAt.Unix()
+		}
+
+		marshalled, err := pm.Marshal(&proto.VoteRecord{
+			BtcPk:     pkBytes,
+			Height:    record.Height,
+			BlockHash: record.BlockHash,
+			Decision:  record.Decision,
+			TxHash:    record.TxHash,
+			Error:     record.Error,
+			CreatedAt: createdAt,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		return fpBucket.Put(heightToKey(record.Height), marshalled)
+	})
+}
+
+// GetVoteRecord returns the journaled decision made on the block at the given height
+func (s *FinalityProviderStore) GetVoteRecord(btcPk *btcec.PublicKey, height uint64) (*VoteRecord, error) {
+	var record *VoteRecord
+	err := s.db.View(func(tx kvdb.RTx) error {
+		fpBucket, err := voteJournalFpBucket(tx, btcPk)
+		if err != nil {
+			return err
+		}
+		if fpBucket == nil {
+			return ErrVoteRecordNotFound
+		}
+
+		recordBytes := fpBucket.Get(heightToKey(height))
+		if recordBytes == nil {
+			return ErrVoteRecordNotFound
+		}
+
+		record, err = unmarshalVoteRecord(recordBytes)
+		return err
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// GetVoteRecords returns the journaled decisions made on the blocks
+// from startHeight to endHeight inclusively, in the ascending order of height
+func (s *FinalityProviderStore) GetVoteRecords(btcPk *btcec.PublicKey, startHeight, endHeight uint64) ([]*VoteRecord, error) {
+	if startHeight > endHeight {
+		return nil, fmt.Errorf("the start height %d should not be higher than the end height %d",
+			startHeight, endHeight)
+	}
+
+	var records []*VoteRecord
+	err := s.db.View(func(tx kvdb.RTx) error {
+		fpBucket, err := voteJournalFpBucket(tx, btcPk)
+		if err != nil || fpBucket == nil {
+			return err
+		}
+
+		cursor := fpBucket.ReadCursor()
+		for k, v := cursor.Seek(heightToKey(startHeight)); k != nil; k, v = cursor.Next() {
+			if binary.BigEndian.Uint64(k) > endHeight {
+				break
+			}
+			record, err := unmarshalVoteRecord(v)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+
+		return nil
+	}, func() {
+		records = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// PruneVoteRecords deletes the journaled decisions made on the blocks
+// lower than the given height and returns the number of deleted records
+func (s *FinalityProviderStore) PruneVoteRecords(btcPk *btcec.PublicKey, belowHeight uint64) (int, error) {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	var pruned int
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		pruned = 0
+
+		journalBucket := tx.ReadWriteBucket(voteJournalBucketName)
+		if journalBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		fpBucket := journalBucket.NestedReadWriteBucket(pkBytes)
+		if fpBucket == nil {
+			return nil
+		}
+
+		// the keys are collected first as the bucket should not
+		// be modified while it is iterated
+		var keys [][]byte
+		cursor := fpBucket.ReadCursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			if binary.BigEndian.Uint64(k) >= belowHeight {
+				break
+			}
+			keys = append(keys, append([]byte{}, k...))
+		}
+
+		for _, k := range keys {
+			if err := fpBucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		pruned = len(keys)
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return pruned, nil
+}
+
+// voteJournalFpBucket returns the journal bucket of the finality provider,
+// which is nil if nothing has been journaled for it
+func voteJournalFpBucket(tx kvdb.RTx, btcPk *btcec.PublicKey) (walletdb.ReadBucket, error) {
+	journalBucket := tx.ReadBucket(voteJournalBucketName)
+	if journalBucket == nil {
+		return nil, ErrCorruptedFinalityProviderDb
+	}
+
+	return journalBucket.NestedReadBucket(schnorr.SerializePubKey(btcPk)), nil
+}
+
+func unmarshalVoteRecord(recordBytes []byte) (*VoteRecord, error) {
+	var record proto.VoteRecord
+	if err := pm.Unmarshal(recordBytes, &record); err != nil {
+		return nil, ErrCorruptedFinalityProviderDb
+	}
+
+	btcPk, err := schnorr.ParsePubKey(record.BtcPk)
+	if err != nil {
+		return nil, ErrCorruptedFinalityProviderDb
+	}
+
+	return &VoteRecord{
+		BtcPk:     btcPk,
+		Height:    record.Height,
+		BlockHash: record.BlockHash,
+		Decision:  record.Decision,
+		TxHash:    record.TxHash,
+		Error:     record.Error,
+		CreatedAt: time.Unix(record.CreatedAt, 0),
+		UpdatedAt: time.Unix(record.UpdatedAt, 0),
+	}, nil
+}
+
+// heightToKey encodes the height in big endian so that
+// the records are iterated in the ascending order of height
+func heightToKey(height uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+	return key
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/stretchr/testify/require"
 
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/types"
 )
@@ -88,8 +89,12 @@ func TestDoubleSigning(t *testing.T) {
 		Height: finalizedBlocks[0].Height,
 		Hash:   datagen.GenRandomByteArray(r, 32),
 	}
-	_, extractedKey, err := fpIns.TestSubmitFinalitySignatureAndExtractPrivKey(b)
-	require.NoError(t, err)
+	_, _, err = fpIns.TestSubmitFinalitySignatureAndExtractPrivKey(b)
+	require.ErrorIs(t, err, eotstypes.ErrDoubleSign)
+
+	// the EOTS manager refuses to double sign, so the conflicting
+	// vote is signed outside of it to simulate a compromised key
+	extractedKey := tm.SubmitConflictingFinalitySig(t, fpIns, b)
 	require.NotNil(t, extractedKey)
 	localKey := tm.GetFpPrivKey(t, fpIns.GetBtcPkBIP340().MustMarshal())
 	require.True(t, localKey.Key.Equals(&extractedKey.Key) || localKey.Key.Negate().Equals(&extractedKey.Key))
@@ -14,6 +14,7 @@ import (
 	sdkmath "cosmossdk.io/math"
 	"github.com/babylonchain/babylon/btcstaking"
 	txformat "github.com/babylonchain/babylon/btctxformatter"
+	"github.com/babylonchain/babylon/crypto/eots"
 	asig "github.com/babylonchain/babylon/crypto/schnorr-adaptor-signature"
 	"github.com/babylonchain/babylon/testutil/datagen"
 	bbntypes "github.com/babylonchain/babylon/types"
@@ -21,6 +22,7 @@ import (
 	btclctypes "github.com/babylonchain/babylon/x/btclightclient/types"
 	bstypes "github.com/babylonchain/babylon/x/btcstaking/types"
 	ckpttypes "github.com/babylonchain/babylon/x/checkpointing/types"
+	ftypes "github.com/babylonchain/babylon/x/finality/types"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -29,6 +31,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	sdkquerytypes "github.com/cosmos/cosmos-sdk/types/query"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/gogo/protobuf/jsonpb"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
@@ -37,6 +40,7 @@ import (
 	eotsconfig "github.com/babylonchain/finality-provider/eotsmanager/config"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	"github.com/babylonchain/finality-provider/finality-provider/service"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
 	"github.com/babylonchain/finality-provider/types"
 )
 
@@ -107,9 +111,11 @@ func StartManager(t *testing.T) *TestManager {
 	// 3. prepare EOTS manager
 	eotsHomeDir := filepath.Join(testDir, "eots-home")
 	eotsCfg := eotsconfig.DefaultConfigWithHomePath(eotsHomeDir)
+	// the tests extract the EOTS private keys to check the slashing
+	eotsCfg.AllowKeyExport = true
 	eh := NewEOTSServerHandler(t, eotsCfg, eotsHomeDir)
 	eh.Start()
-	eotsCli, err := client.NewEOTSManagerGRpcClient(cfg.EOTSManagerAddress)
+	eotsCli, err := client.NewEOTSManagerGRpcClient(cfg.EOTSManagerAddress, nil)
 	require.NoError(t, err)
 
 	// 4. prepare finality-provider
@@ -166,7 +172,7 @@ func StartManagerWithFinalityProvider(t *testing.T, n int) (*TestManager, []*ser
 		cfg := app.GetConfig()
 		_, err := service.CreateChainKey(cfg.BabylonConfig.KeyDirectory, cfg.BabylonConfig.ChainID, fpName, keyring.BackendTest, passphrase, hdPath, "")
 		require.NoError(t, err)
-		res, err := app.CreateFinalityProvider(fpName, chainID, passphrase, hdPath, desc, &commission)
+		res, err := app.CreateFinalityProvider(fpName, chainID, passphrase, hdPath, "", desc, &commission)
 		require.NoError(t, err)
 		fpPk, err := bbntypes.NewBIP340PubKeyFromHex(res.FpInfo.BtcPkHex)
 		require.NoError(t, err)
@@ -392,6 +398,41 @@ func (tm *TestManager) GetFpPrivKey(t *testing.T, fpPk []byte) *btcec.PrivateKey
 	return record.PrivKey
 }
 
+// SubmitConflictingFinalitySig signs the given block with the EOTS key and randomness of
+// the finality provider without going through the EOTS manager and submits the vote
+// it returns the private key extracted from the slashing evidence if there is one
+func (tm *TestManager) SubmitConflictingFinalitySig(t *testing.T, fpIns *service.FinalityProviderInstance, b *types.BlockInfo) *btcec.PrivateKey {
+	fpSk := tm.GetFpPrivKey(t, fpIns.GetBtcPkBIP340().MustMarshal())
+	msr, _, err := fpkeyring.GenerateMasterRandPair(fpSk.Serialize(), fpIns.GetChainID())
+	require.NoError(t, err)
+	sr, _, err := msr.DeriveRandPair(uint32(b.Height))
+	require.NoError(t, err)
+
+	msg := &ftypes.MsgAddFinalitySig{
+		FpBtcPk:      fpIns.GetBtcPkBIP340(),
+		BlockHeight:  b.Height,
+		BlockAppHash: b.Hash,
+	}
+	sig, err := eots.Sign(fpSk, sr, msg.MsgToSign())
+	require.NoError(t, err)
+
+	res, err := tm.BBNClient.SubmitFinalitySig(fpIns.GetBtcPk(), b.Height, b.Hash, sig)
+	require.NoError(t, err)
+
+	for _, ev := range res.Events {
+		if strings.Contains(ev.EventType, "EventSlashedFinalityProvider") {
+			var evidence ftypes.Evidence
+			err := jsonpb.UnmarshalString(ev.Attributes["evidence"], &evidence)
+			require.NoError(t, err)
+			privKey, err := evidence.ExtractBTCSK()
+			require.NoError(t, err)
+			return privKey
+		}
+	}
+
+	return nil
+}
+
 func (tm *TestManager) InsertCovenantSigForDelegation(t *testing.T, btcDel *bstypes.BTCDelegation) {
 	slashingTx := btcDel.SlashingTx
 	stakingTx := btcDel.StakingTx
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"strings"
 
+	bbntypes "github.com/babylonchain/babylon/types"
 	bstypes "github.com/babylonchain/babylon/x/btcstaking/types"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
@@ -130,6 +132,40 @@ func (kc *ChainKeyringController) CreatePop(btcPrivKey *btcec.PrivateKey, passph
 	return bstypes.NewPoP(bbnPrivKey, btcPrivKey)
 }
 
+// BTCPopSigner makes the BTC signature of the proof-of-possession, i.e., a Schnorr
+// signature over the SHA-256 hash of the chain signature, with the BTC private key
+type BTCPopSigner func(chainPk []byte, chainSig []byte) (*schnorr.Signature, error)
+
+// CreatePopWithSigner creates proof-of-possession of Babylon and BTC public keys
+// in the same way as CreatePop, except that the BTC signature is made by the given
+// signer, e.g., the EOTS manager, so that the BTC private key is not needed here
+func (kc *ChainKeyringController) CreatePopWithSigner(
+	btcPk *bbntypes.BIP340PubKey,
+	passphrase string,
+	signBTC BTCPopSigner,
+) (*bstypes.ProofOfPossession, error) {
+	bbnPrivKey, err := kc.GetChainPrivKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	bbnSig, err := bbnPrivKey.Sign(btcPk.MustMarshal())
+	if err != nil {
+		return nil, err
+	}
+
+	btcSig, err := signBTC(bbnPrivKey.PubKey().Bytes(), bbnSig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the proof-of-possession with the BTC key: %w", err)
+	}
+
+	return &bstypes.ProofOfPossession{
+		BtcSigType: bstypes.BTCSigType_BIP340,
+		BabylonSig: bbnSig,
+		BtcSig:     bbntypes.NewBIP340SignatureFromBTCSig(btcSig).MustMarshal(),
+	}, nil
+}
+
 func (kc *ChainKeyringController) GetChainPrivKey(passphrase string) (*sdksecp256k1.PrivKey, error) {
 	kc.input.Reset(passphrase)
 	k, err := kc.kr.Key(kc.fpName)
@@ -1,6 +1,7 @@
 package keyring_test
 
 import (
+	"crypto/sha256"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
@@ -19,6 +21,7 @@ import (
 	fpkr "github.com/babylonchain/finality-provider/keyring"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
 	"github.com/babylonchain/finality-provider/testutil"
 )
 
@@ -64,5 +67,21 @@ func FuzzCreatePoP(f *testing.F) {
 		require.NoError(t, err)
 		err = pop.Verify(bbnPk, btcPk, &chaincfg.SimNetParams)
 		require.NoError(t, err)
+
+		// the PoP can be created without accessing the EOTS private key
+		popWithSigner, err := kc.CreatePopWithSigner(btcPk, passphrase, func(chainPk, chainSig []byte) (*schnorr.Signature, error) {
+			return em.SignPoP(btcPk.MustMarshal(), chainPk, chainSig, passphrase)
+		})
+		require.NoError(t, err)
+		btcSig, err := schnorr.ParseSignature(popWithSigner.BtcSig)
+		require.NoError(t, err)
+		bbnSigHash := sha256.Sum256(popWithSigner.BabylonSig)
+		require.True(t, btcSig.Verify(bbnSigHash[:], btcPk.MustToBTCPK()))
+		err = popWithSigner.Verify(bbnPk, btcPk, &chaincfg.SimNetParams)
+		require.NoError(t, err)
+
+		// the EOTS manager refuses to sign a binding that is not signed by the chain key
+		_, err = em.SignPoP(btcPk.MustMarshal(), bbnPk.Bytes(), testutil.GenRandomByteArray(r, 64), passphrase)
+		require.ErrorIs(t, err, eotstypes.ErrInvalidChainSig)
 	})
 }
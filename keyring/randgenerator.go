@@ -3,13 +3,140 @@ package keyring
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
 
 	"github.com/babylonchain/babylon/crypto/eots"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
 )
 
+const (
+	// RandSchemeUint32 derives the randomness at a height as the child of the master
+	// randomness at the index of the height, so it only supports heights up to 2^32-1
+	// This is the scheme that Babylon verifies the finality signatures with
+	RandSchemeUint32 = "uint32"
+	// RandSchemeUint64 derives the randomness at a height along the non-hardened path
+	// (height >> 62) / ((height >> 31) & (2^31-1)) / (height & (2^31-1)),
+	// so it supports any uint64 height
+	RandSchemeUint64 = "uint64"
+
+	// nonHardenedIndexMask is the mask of the indices that are derived without hardening,
+	// so that the public randomness can be derived from the master public randomness
+	nonHardenedIndexMask = hdkeychain.HardenedKeyStart - 1
+)
+
+var (
+	// ErrHeightOverflow is returned if the randomness at a height cannot be derived
+	// by the derivation scheme without truncating the height
+	ErrHeightOverflow = errors.New("the height overflows the randomness derivation scheme")
+	// ErrMasterRandZeroed is returned if the randomness is derived from
+	// a master secret randomness that has been zeroized
+	ErrMasterRandZeroed = errors.New("the master secret randomness has been zeroized")
+)
+
+// MasterSecretRand derives the secret randomness at each height
+type MasterSecretRand interface {
+	DeriveRandPair(height uint64) (*eots.PrivateRand, *eots.PublicRand, error)
+	// Zero wipes the master secret randomness, after which no randomness can be derived
+	Zero()
+	// Clone returns a copy of the master secret randomness that is not affected
+	// by zeroizing the original, and vice versa
+	Clone() MasterSecretRand
+}
+
+// MasterPublicRand derives the public randomness at each height
+type MasterPublicRand interface {
+	DerivePubRand(height uint64) (*eots.PublicRand, error)
+	MarshalBase58() string
+}
+
+// RandSchemes returns all the supported randomness derivation schemes
+func RandSchemes() []string {
+	return []string{RandSchemeUint32, RandSchemeUint64}
+}
+
+// ValidateRandScheme checks that the given randomness derivation scheme is supported
+func ValidateRandScheme(scheme string) error {
+	switch scheme {
+	case RandSchemeUint32, RandSchemeUint64:
+		return nil
+	default:
+		return fmt.Errorf("unsupported randomness derivation scheme %q, expected %q or %q",
+			scheme, RandSchemeUint32, RandSchemeUint64)
+	}
+}
+
 // GenerateMasterRandPair generates pair of master secret/public randomness
 // The result is deterministic with each given input
 func GenerateMasterRandPair(key []byte, chainID []byte) (*eots.MasterSecretRand, *eots.MasterPublicRand, error) {
+	seed := masterRandSeed(key, chainID)
+
+	// convert the hash into private random
+	return eots.NewMasterRandPairFromSeed(seed)
+}
+
+// GenerateMasterRandPairWithScheme generates pair of master secret/public randomness
+// that derive the randomness at each height with the given scheme
+// The result is deterministic with each given input
+func GenerateMasterRandPairWithScheme(key []byte, chainID []byte, scheme string) (MasterSecretRand, MasterPublicRand, error) {
+	switch scheme {
+	case RandSchemeUint32:
+		msr, mpr, err := GenerateMasterRandPair(key, chainID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &uint32MasterSecretRand{msr: msr}, &uint32MasterPublicRand{mpr}, nil
+	case RandSchemeUint64:
+		seed := masterRandSeed(key, chainID)
+		k, err := hdkeychain.NewMaster(seed[:], &chaincfg.MainNetParams)
+		if err != nil {
+			return nil, nil, err
+		}
+		neutered, err := k.Neuter()
+		if err != nil {
+			return nil, nil, err
+		}
+		// the neutered key shares the chain code with the master key, which is wiped
+		// by Zero, so the master public randomness is parsed from its serialization
+		pk, err := hdkeychain.NewKeyFromString(neutered.String())
+		if err != nil {
+			return nil, nil, err
+		}
+		return &uint64MasterSecretRand{k: k}, &uint64MasterPublicRand{pk}, nil
+	default:
+		return nil, nil, ValidateRandScheme(scheme)
+	}
+}
+
+// NewMasterPublicRandFromBase58 parses the master public randomness
+// that derives the public randomness with the given scheme
+func NewMasterPublicRandFromBase58(s string, scheme string) (MasterPublicRand, error) {
+	switch scheme {
+	case RandSchemeUint32:
+		mpr, err := eots.NewMasterPublicRandFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return &uint32MasterPublicRand{mpr}, nil
+	case RandSchemeUint64:
+		k, err := hdkeychain.NewKeyFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		if k.IsPrivate() {
+			return nil, fmt.Errorf("the master public randomness should not contain the secret")
+		}
+		return &uint64MasterPublicRand{k}, nil
+	default:
+		return nil, ValidateRandScheme(scheme)
+	}
+}
+
+func masterRandSeed(key []byte, chainID []byte) [32]byte {
 	// calculate the random hash of the key concatenated with chainID and height
 	hasher := hmac.New(sha256.New, key)
 	hasher.Write(chainID)
@@ -19,6 +146,162 @@ func GenerateMasterRandPair(key []byte, chainID []byte) (*eots.MasterSecretRand,
 	var seed [32]byte
 	copy(seed[:], seedSlice[:32])
 
-	// convert the hash into private random
-	return eots.NewMasterRandPairFromSeed(seed)
+	return seed
+}
+
+type uint32MasterSecretRand struct {
+	mu  sync.RWMutex
+	msr *eots.MasterSecretRand
+}
+
+func (r *uint32MasterSecretRand) DeriveRandPair(height uint64) (*eots.PrivateRand, *eots.PublicRand, error) {
+	if height > math.MaxUint32 {
+		return nil, nil, fmt.Errorf("%w: height %d, scheme %s", ErrHeightOverflow, height, RandSchemeUint32)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.msr == nil {
+		return nil, nil, ErrMasterRandZeroed
+	}
+
+	return r.msr.DeriveRandPair(uint32(height))
+}
+
+// Zero drops the reference to the master secret randomness of Babylon
+// NOTE: the Babylon type does not expose its key, so it cannot be wiped
+// and it stays in the heap until it is garbage collected
+func (r *uint32MasterSecretRand) Zero() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.msr = nil
+}
+
+// Clone returns a copy that refers to the same master secret randomness of Babylon,
+// which is only dropped by zeroizing either of them (see Zero)
+func (r *uint32MasterSecretRand) Clone() MasterSecretRand {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return &uint32MasterSecretRand{msr: r.msr}
+}
+
+type uint32MasterPublicRand struct {
+	mpr *eots.MasterPublicRand
+}
+
+func (r *uint32MasterPublicRand) DerivePubRand(height uint64) (*eots.PublicRand, error) {
+	if height > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: height %d, scheme %s", ErrHeightOverflow, height, RandSchemeUint32)
+	}
+
+	return r.mpr.DerivePubRand(uint32(height))
+}
+
+func (r *uint32MasterPublicRand) MarshalBase58() string {
+	return r.mpr.MarshalBase58()
+}
+
+type uint64MasterSecretRand struct {
+	mu sync.RWMutex
+	k  *hdkeychain.ExtendedKey
+}
+
+func (r *uint64MasterSecretRand) DeriveRandPair(height uint64) (*eots.PrivateRand, *eots.PublicRand, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.k == nil {
+		return nil, nil, ErrMasterRandZeroed
+	}
+
+	child, err := deriveUint64Child(r.k, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	sk, err := child.ECPrivKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pubRand eots.PublicRand
+	pubRand.SetByteSlice(sk.PubKey().SerializeCompressed()[1:])
+	privRand := sk.Key
+
+	return &privRand, &pubRand, nil
+}
+
+// Zero wipes the master extended key
+func (r *uint64MasterSecretRand) Zero() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.k != nil {
+		r.k.Zero()
+		r.k = nil
+	}
+}
+
+// Clone returns a copy with its own master extended key
+func (r *uint64MasterSecretRand) Clone() MasterSecretRand {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.k == nil {
+		return &uint64MasterSecretRand{}
+	}
+	// the master key is private, so that it always converts
+	sk, _ := r.k.ECPrivKey()
+	defer sk.Zero()
+	parentFP := make([]byte, 4)
+	binary.BigEndian.PutUint32(parentFP, r.k.ParentFingerprint())
+	k := hdkeychain.NewExtendedKey(r.k.Version(), sk.Serialize(), r.k.ChainCode(), parentFP,
+		r.k.Depth(), r.k.ChildIndex(), true)
+
+	return &uint64MasterSecretRand{k: k}
+}
+
+type uint64MasterPublicRand struct {
+	k *hdkeychain.ExtendedKey
+}
+
+func (r *uint64MasterPublicRand) DerivePubRand(height uint64) (*eots.PublicRand, error) {
+	child, err := deriveUint64Child(r.k, height)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := child.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var pubRand eots.PublicRand
+	pubRand.SetByteSlice(pk.SerializeCompressed()[1:])
+
+	return &pubRand, nil
+}
+
+func (r *uint64MasterPublicRand) MarshalBase58() string {
+	return r.k.String()
+}
+
+// deriveUint64Child derives the child key of the given height along the path of RandSchemeUint64
+func deriveUint64Child(k *hdkeychain.ExtendedKey, height uint64) (*hdkeychain.ExtendedKey, error) {
+	path := []uint32{
+		uint32(height >> 62),
+		uint32(height>>31) & nonHardenedIndexMask,
+		uint32(height) & nonHardenedIndexMask,
+	}
+
+	var err error
+	for _, index := range path {
+		k, err = k.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
 }
@@ -11,6 +11,9 @@ type EotsMetrics struct {
 	EotsFpTotalEotsSignCounter    *prometheus.CounterVec
 	EotsFpLastEotsSignHeight      *prometheus.GaugeVec
 	EotsFpTotalSchnorrSignCounter *prometheus.CounterVec
+	EotsFpPolicyViolationCounter  *prometheus.CounterVec
+	EotsRandWindowHitCounter      *prometheus.CounterVec
+	EotsRandWindowMissCounter     *prometheus.CounterVec
 }
 
 var eotsMetricsRegisterOnce sync.Once
@@ -45,6 +48,27 @@ func NewEotsMetrics() *EotsMetrics {
 				},
 				[]string{"fp_btc_pk_hex"},
 			),
+			EotsFpPolicyViolationCounter: prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "eots_fp_policy_violation_counter",
+					Help: "Total number of signing requests refused by the signing policy",
+				},
+				[]string{"fp_btc_pk_hex", "rule"},
+			),
+			EotsRandWindowHitCounter: prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "eots_rand_window_hit_counter",
+					Help: "Total number of EOTS signatures made with the precomputed randomness",
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
+			EotsRandWindowMissCounter: prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "eots_rand_window_miss_counter",
+					Help: "Total number of EOTS signatures made without the precomputed randomness",
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
 		}
 
 		// Register the EOTS metrics with Prometheus
@@ -52,6 +76,9 @@ func NewEotsMetrics() *EotsMetrics {
 		prometheus.MustRegister(eotsMetricsInstance.EotsFpTotalEotsSignCounter)
 		prometheus.MustRegister(eotsMetricsInstance.EotsFpLastEotsSignHeight)
 		prometheus.MustRegister(eotsMetricsInstance.EotsFpTotalSchnorrSignCounter)
+		prometheus.MustRegister(eotsMetricsInstance.EotsFpPolicyViolationCounter)
+		prometheus.MustRegister(eotsMetricsInstance.EotsRandWindowHitCounter)
+		prometheus.MustRegister(eotsMetricsInstance.EotsRandWindowMissCounter)
 	})
 
 	return eotsMetricsInstance
@@ -76,3 +103,21 @@ func (em *EotsMetrics) SetEotsFpLastEotsSignHeight(fpBtcPkHex string, height flo
 func (em *EotsMetrics) IncrementEotsFpTotalSchnorrSignCounter(fpBtcPkHex string) {
 	em.EotsFpTotalSchnorrSignCounter.WithLabelValues(fpBtcPkHex).Inc()
 }
+
+// IncrementEotsFpPolicyViolationCounter increments the counter of the requests
+// refused by the given rule of the signing policy
+func (em *EotsMetrics) IncrementEotsFpPolicyViolationCounter(fpBtcPkHex string, rule string) {
+	em.EotsFpPolicyViolationCounter.WithLabelValues(fpBtcPkHex, rule).Inc()
+}
+
+// IncrementEotsRandWindowHitCounter increments the counter of the signatures
+// made with the precomputed randomness
+func (em *EotsMetrics) IncrementEotsRandWindowHitCounter(fpBtcPkHex string) {
+	em.EotsRandWindowHitCounter.WithLabelValues(fpBtcPkHex).Inc()
+}
+
+// IncrementEotsRandWindowMissCounter increments the counter of the signatures
+// made without the precomputed randomness
+func (em *EotsMetrics) IncrementEotsRandWindowMissCounter(fpBtcPkHex string) {
+	em.EotsRandWindowMissCounter.WithLabelValues(fpBtcPkHex).Inc()
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+)
+
+// RPCMetrics are the metrics of the requests served by the gRPC servers of the daemons
+type RPCMetrics struct {
+	rpcRequestDuration *prometheus.HistogramVec
+	rpcRequestErrors   *prometheus.CounterVec
+}
+
+var rpcMetricsRegisterOnce sync.Once
+
+var rpcMetricsInstance *RPCMetrics
+
+// NewRPCMetrics initializes and registers the RPC metrics, which are shared
+// by all the gRPC servers in the process and told apart by the server label
+func NewRPCMetrics() *RPCMetrics {
+	rpcMetricsRegisterOnce.Do(func() {
+		rpcMetricsInstance = &RPCMetrics{
+			rpcRequestDuration: prometheus.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Name:    "rpc_request_duration_seconds",
+					Help:    "Duration of the gRPC requests served",
+					Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+				},
+				[]string{"server", "method", "code"},
+			),
+			rpcRequestErrors: prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "rpc_request_errors_total",
+					Help: "Total number of gRPC requests that failed",
+				},
+				[]string{"server", "method", "code"},
+			),
+		}
+
+		prometheus.MustRegister(rpcMetricsInstance.rpcRequestDuration)
+		prometheus.MustRegister(rpcMetricsInstance.rpcRequestErrors)
+	})
+
+	return rpcMetricsInstance
+}
+
+// ObserveRequest records the duration of a request served by the given server,
+// and counts it as an error if its status code is not OK
+func (rm *RPCMetrics) ObserveRequest(server, method string, code codes.Code, duration time.Duration) {
+	rm.rpcRequestDuration.WithLabelValues(server, method, code.String()).Observe(duration.Seconds())
+	if code != codes.OK {
+		rm.rpcRequestErrors.WithLabelValues(server, method, code.String()).Inc()
+	}
+}
@@ -0,0 +1,209 @@
+package migration
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping schemaVersionKey -> the version of the database schema
+	metadataBucketName = []byte("metadata")
+	schemaVersionKey   = []byte("schemaVersion")
+
+	// ErrSchemaTooNew is returned if the database is written by a newer version of the software
+	ErrSchemaTooNew = errors.New("the database schema is newer than the supported one")
+
+	// errDryRun rolls back the transaction of a dry run
+	errDryRun = errors.New("dry run")
+)
+
+// Migration upgrades the database schema to its version
+type Migration struct {
+	// Version is the schema version after the migration, starting from 1
+	Version uint32
+	// Description tells what the migration changes
+	Description string
+	// Migrate applies the changes within the transaction of all the pending migrations
+	Migrate func(tx kvdb.RwTx) error
+}
+
+// Report describes the migrations that are applied, or would be applied in a dry run
+type Report struct {
+	// CurrentVersion is the schema version of the database before the migrations,
+	// 0 if the database has no version marker
+	CurrentVersion uint32
+	// TargetVersion is the latest schema version supported
+	TargetVersion uint32
+	// Pending are the migrations from the current version to the target version
+	Pending []*Migration
+	DryRun  bool
+}
+
+// UpToDate returns whether no migration is needed
+func (r *Report) UpToDate() bool {
+	return len(r.Pending) == 0
+}
+
+// String describes the report in a human readable form
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "current schema version: %d\n", r.CurrentVersion)
+	fmt.Fprintf(&sb, "target schema version: %d\n", r.TargetVersion)
+	if r.UpToDate() {
+		sb.WriteString("the database is up to date\n")
+		return sb.String()
+	}
+
+	if r.DryRun {
+		sb.WriteString("the following migrations would be applied:\n")
+	} else {
+		sb.WriteString("the following migrations are applied:\n")
+	}
+	for _, m := range r.Pending {
+		fmt.Fprintf(&sb, "  %d: %s\n", m.Version, m.Description)
+	}
+
+	return sb.String()
+}
+
+// Run applies the pending migrations in order within a single transaction,
+// so that either all of them are applied or the database is left untouched
+// If dryRun is true, the migrations are run and then rolled back
+// It fails with ErrSchemaTooNew if the database is newer than the given migrations
+func Run(db kvdb.Backend, migrations []*Migration, dryRun bool) (*Report, error) {
+	if err := validate(migrations); err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		TargetVersion: migrations[len(migrations)-1].Version,
+		DryRun:        dryRun,
+	}
+
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		currentVersion, err := getSchemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		if currentVersion > report.TargetVersion {
+			return fmt.Errorf("%w: the database is at version %d, while the latest supported version is %d",
+				ErrSchemaTooNew, currentVersion, report.TargetVersion)
+		}
+		report.CurrentVersion = currentVersion
+
+		for _, m := range migrations {
+			if m.Version <= currentVersion {
+				continue
+			}
+			if err := m.Migrate(tx); err != nil {
+				return fmt.Errorf("failed to migrate the database to version %d: %w", m.Version, err)
+			}
+			report.Pending = append(report.Pending, m)
+		}
+
+		if len(report.Pending) == 0 {
+			return nil
+		}
+
+		if err := setSchemaVersion(tx, report.TargetVersion); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	}, func() {
+		report.CurrentVersion = 0
+		report.Pending = nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// SchemaVersion returns the schema version of the database, 0 if it has no version marker
+func SchemaVersion(db kvdb.Backend) (uint32, error) {
+	var version uint32
+	err := kvdb.View(db, func(tx kvdb.RTx) error {
+		v, err := getSchemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		version = v
+		return nil
+	}, func() {})
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// validate checks that the versions of the migrations are consecutive from 1
+func validate(migrations []*Migration) error {
+	if len(migrations) == 0 {
+		return fmt.Errorf("no database migrations")
+	}
+	for i, m := range migrations {
+		if m.Version != uint32(i+1) {
+			return fmt.Errorf("the database migration %q should be of version %d, got %d",
+				m.Description, i+1, m.Version)
+		}
+		if m.Migrate == nil {
+			return fmt.Errorf("the database migration of version %d has no migrate function", m.Version)
+		}
+	}
+
+	return nil
+}
+
+func getSchemaVersion(tx kvdb.RTx) (uint32, error) {
+	bucket := tx.ReadBucket(metadataBucketName)
+	if bucket == nil {
+		return 0, nil
+	}
+
+	v := bucket.Get(schemaVersionKey)
+	if v == nil {
+		return 0, nil
+	}
+	if len(v) != 4 {
+		return 0, fmt.Errorf("invalid schema version of %d bytes", len(v))
+	}
+
+	return binary.BigEndian.Uint32(v), nil
+}
+
+func setSchemaVersion(tx kvdb.RwTx, version uint32) error {
+	bucket, err := tx.CreateTopLevelBucket(metadataBucketName)
+	if err != nil {
+		return err
+	}
+
+	v := make([]byte, 4)
+	binary.BigEndian.PutUint32(v, version)
+
+	return bucket.Put(schemaVersionKey, v)
+}
+
+// CreateTopLevelBuckets returns a migration function that creates the given
+// top level buckets if they do not exist
+func CreateTopLevelBuckets(names ...[]byte) func(tx kvdb.RwTx) error {
+	return func(tx kvdb.RwTx) error {
+		for _, name := range names {
+			if _, err := tx.CreateTopLevelBucket(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package migration_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/migration"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzMigrations tests that the pending migrations are applied in order,
+// dry runs leave the database untouched and newer databases are refused
+func FuzzMigrations(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		db, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+			DBPath:     t.TempDir(),
+			DBFileName: "migration.db",
+			DBTimeout:  kvdb.DefaultDBTimeout,
+		})
+		require.NoError(t, err)
+		defer db.Close()
+
+		numMigrations := 1 + r.Intn(10)
+		var applied []uint32
+		migrations := make([]*migration.Migration, numMigrations)
+		for i := range migrations {
+			version := uint32(i + 1)
+			bucketName := []byte(fmt.Sprintf("bucket%d", version))
+			migrations[i] = &migration.Migration{
+				Version:     version,
+				Description: fmt.Sprintf("create %s", bucketName),
+				Migrate: func(tx kvdb.RwTx) error {
+					applied = append(applied, version)
+					_, err := tx.CreateTopLevelBucket(bucketName)
+					return err
+				},
+			}
+		}
+
+		// migrate to a random version first
+		initialVersion := uint32(r.Intn(numMigrations + 1))
+		if initialVersion > 0 {
+			report, err := migration.Run(db, migrations[:initialVersion], false)
+			require.NoError(t, err)
+			require.Equal(t, uint32(0), report.CurrentVersion)
+			require.Len(t, report.Pending, int(initialVersion))
+		}
+		applied = nil
+
+		// a dry run reports the pending migrations without writing them
+		report, err := migration.Run(db, migrations, true)
+		require.NoError(t, err)
+		require.Equal(t, initialVersion, report.CurrentVersion)
+		require.Equal(t, uint32(numMigrations), report.TargetVersion)
+		require.Len(t, report.Pending, numMigrations-int(initialVersion))
+		version, err := migration.SchemaVersion(db)
+		require.NoError(t, err)
+		require.Equal(t, initialVersion, version)
+		err = kvdb.View(db, func(tx kvdb.RTx) error {
+			for i := initialVersion; i < uint32(numMigrations); i++ {
+				require.Nil(t, tx.ReadBucket([]byte(fmt.Sprintf("bucket%d", i+1))))
+			}
+			return nil
+		}, func() {})
+		require.NoError(t, err)
+
+		// the pending migrations are applied in order
+		applied = nil
+		report, err = migration.Run(db, migrations, false)
+		require.NoError(t, err)
+		require.Equal(t, initialVersion, report.CurrentVersion)
+		require.Len(t, applied, numMigrations-int(initialVersion))
+		for i, v := range applied {
+			require.Equal(t, initialVersion+uint32(i)+1, v)
+		}
+		version, err = migration.SchemaVersion(db)
+		require.NoError(t, err)
+		require.Equal(t, uint32(numMigrations), version)
+
+		// nothing to do once up to date
+		applied = nil
+		report, err = migration.Run(db, migrations, false)
+		require.NoError(t, err)
+		require.True(t, report.UpToDate())
+		require.Empty(t, applied)
+
+		// the database is newer than the code
+		if numMigrations > 1 {
+			_, err = migration.Run(db, migrations[:numMigrations-1], false)
+			require.ErrorIs(t, err, migration.ErrSchemaTooNew)
+		}
+
+		// a failing migration leaves the database untouched
+		failing := append(migrations, &migration.Migration{
+			Version:     uint32(numMigrations + 1),
+			Description: "fail",
+			Migrate: func(tx kvdb.RwTx) error {
+				return fmt.Errorf("failed")
+			},
+		})
+		_, err = migration.Run(db, failing, false)
+		require.Error(t, err)
+		version, err = migration.SchemaVersion(db)
+		require.NoError(t, err)
+		require.Equal(t, uint32(numMigrations), version)
+	})
+}
@@ -0,0 +1,97 @@
+package rpcinterceptor
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/metrics"
+)
+
+// slowRequestThreshold is the duration above which a request is logged as slow
+const slowRequestThreshold = time.Second
+
+// ServerOption returns the option installing the interceptors on a gRPC server,
+// with the given server name labelling its metrics and log lines
+// The panics are recovered innermost, so that they are logged and measured as
+// the internal errors returned
+func ServerOption(server string, logger *zap.Logger, m *metrics.RPCMetrics) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(
+		MetricsInterceptor(server, m),
+		LoggingInterceptor(logger),
+		RecoveryInterceptor(logger),
+	)
+}
+
+// MetricsInterceptor records the duration and the status of each request
+func MetricsInterceptor(server string, m *metrics.RPCMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.ObserveRequest(server, methodName(info.FullMethod), status.Code(err), time.Since(start))
+
+		return resp, err
+	}
+}
+
+// LoggingInterceptor emits a log line for each request with its secrets redacted
+// The successful requests are logged at debug level unless they are slow
+func LoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		duration := time.Since(start)
+
+		fields := []zap.Field{
+			zap.String("method", methodName(info.FullMethod)),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", duration),
+			zap.Any("request", Redact(req)),
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			fields = append(fields, zap.String("peer", p.Addr.String()))
+		}
+
+		switch {
+		case err != nil:
+			logger.Warn("failed to serve the RPC request", append(fields, zap.Error(err))...)
+		case duration > slowRequestThreshold:
+			logger.Warn("served a slow RPC request", fields...)
+		default:
+			logger.Debug("served the RPC request", fields...)
+		}
+
+		return resp, err
+	}
+}
+
+// RecoveryInterceptor turns a panic of the handler into an internal error,
+// so that a single request cannot crash the daemon
+func RecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("recovered from a panic while serving the RPC request",
+					zap.String("method", methodName(info.FullMethod)),
+					zap.Any("panic", r),
+					zap.Stack("stack"),
+				)
+				resp, err = nil, status.Errorf(codes.Internal, "internal error while serving %s", methodName(info.FullMethod))
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// methodName returns the name of the method without the service, e.g., SignEOTS
+// for /proto.EOTSManager/SignEOTS
+func methodName(fullMethod string) string {
+	return path.Base(fullMethod)
+}
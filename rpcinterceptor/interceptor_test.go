@@ -0,0 +1,132 @@
+package rpcinterceptor_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/rpcinterceptor"
+)
+
+type panickingEOTSManager struct {
+	proto.UnimplementedEOTSManagerServer
+}
+
+func (s *panickingEOTSManager) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
+	return &proto.PingResponse{}, nil
+}
+
+func (s *panickingEOTSManager) ImportMnemonic(context.Context, *proto.ImportMnemonicRequest) (*proto.ImportMnemonicResponse, error) {
+	return nil, status.Error(codes.InvalidArgument, "invalid mnemonic")
+}
+
+func (s *panickingEOTSManager) SignEOTS(context.Context, *proto.SignEOTSRequest) (*proto.SignEOTSResponse, error) {
+	panic("unexpected")
+}
+
+// TestInterceptors tests that the requests are logged with their secrets redacted,
+// measured, and that a panic is turned into an internal error
+func TestInterceptors(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer(rpcinterceptor.ServerOption(t.Name(), zap.New(core), metrics.NewRPCMetrics()))
+	proto.RegisterEOTSManagerServer(grpcServer, &panickingEOTSManager{})
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := proto.NewEOTSManagerClient(conn)
+
+	// the secrets of a request are redacted
+	_, err = client.ImportMnemonic(context.Background(), &proto.ImportMnemonicRequest{
+		Name:       "key",
+		Passphrase: "secret passphrase",
+		Mnemonic:   "secret mnemonic",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	entries := logs.FilterField(zap.String("method", "ImportMnemonic")).All()
+	require.Len(t, entries, 1)
+	require.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	request := fmt.Sprint(entries[0].ContextMap()["request"])
+	require.Contains(t, request, "key")
+	require.NotContains(t, request, "secret")
+
+	// a panic does not crash the server
+	_, err = client.SignEOTS(context.Background(), &proto.SignEOTSRequest{Uid: []byte{1}, Height: 10})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Len(t, logs.FilterMessageSnippet("panic").All(), 1)
+	_, err = client.Ping(context.Background(), &proto.PingRequest{})
+	require.NoError(t, err)
+	require.Len(t, logs.FilterField(zap.String("method", "Ping")).FilterField(zap.String("code", codes.OK.String())).All(), 1)
+
+	// the failed requests are counted by method and status code
+	require.Equal(t, float64(1), errorCount(t, t.Name(), "ImportMnemonic", codes.InvalidArgument))
+	require.Equal(t, float64(1), errorCount(t, t.Name(), "SignEOTS", codes.Internal))
+	require.Zero(t, errorCount(t, t.Name(), "Ping", codes.OK))
+}
+
+func errorCount(t *testing.T, server, method string, code codes.Code) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "rpc_request_errors_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["server"] == server && labels["method"] == method && labels["code"] == code.String() {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
+
+func TestRedact(t *testing.T) {
+	redacted := rpcinterceptor.Redact(&proto.SignEOTSBatchRequest{
+		Uid:        []byte{0xab},
+		Msgs:       []*proto.HeightMsg{{Height: 1, Msg: []byte{0xcd}}},
+		Passphrase: "secret",
+	})
+	require.Equal(t, map[string]interface{}{
+		"uid":        "ab",
+		"msgs":       []interface{}{map[string]interface{}{"height": uint64(1), "msg": "cd"}},
+		"passphrase": "[REDACTED]",
+	}, redacted)
+
+	redacted = rpcinterceptor.Redact(&proto.ResumeRequest{
+		Uid:             []byte{0xab},
+		Passphrase:      "secret",
+		AdminCredential: "credential",
+	})
+	require.Equal(t, map[string]interface{}{
+		"uid":              "ab",
+		"passphrase":       "[REDACTED]",
+		"admin_credential": "[REDACTED]",
+	}, redacted)
+
+	require.Equal(t, "string", rpcinterceptor.Redact("not a message"))
+}
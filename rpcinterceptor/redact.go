@@ -0,0 +1,84 @@
+package rpcinterceptor
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveFields are the names of the message fields carrying secrets,
+// which are never logged
+var sensitiveFields = map[protoreflect.Name]struct{}{
+	"passphrase":       {},
+	"mnemonic":         {},
+	"key_mnemonic":     {},
+	"private_key":      {},
+	"extracted_sk_hex": {},
+	"local_sk_hex":     {},
+	"admin_credential": {},
+}
+
+// Redact returns the set fields of the message in a form that can be logged,
+// with the values of the sensitive fields replaced and the bytes hex encoded
+// It returns the message type if it is not a protobuf message
+func Redact(msg interface{}) interface{} {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return fmt.Sprintf("%T", msg)
+	}
+
+	return redactMessage(m.ProtoReflect())
+}
+
+func redactMessage(m protoreflect.Message) map[string]interface{} {
+	fields := make(map[string]interface{})
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if _, ok := sensitiveFields[fd.Name()]; ok {
+			fields[string(fd.Name())] = redactedValue
+			return true
+		}
+
+		switch {
+		case fd.IsList():
+			list := v.List()
+			values := make([]interface{}, list.Len())
+			for i := 0; i < list.Len(); i++ {
+				values[i] = redactValue(fd, list.Get(i))
+			}
+			fields[string(fd.Name())] = values
+		case fd.IsMap():
+			values := make(map[string]interface{}, v.Map().Len())
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				values[k.String()] = redactValue(fd.MapValue(), mv)
+				return true
+			})
+			fields[string(fd.Name())] = values
+		default:
+			fields[string(fd.Name())] = redactValue(fd, v)
+		}
+
+		return true
+	})
+
+	return fields
+}
+
+func redactValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return redactMessage(v.Message())
+	case protoreflect.BytesKind:
+		return hex.EncodeToString(v.Bytes())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	default:
+		return v.Interface()
+	}
+}
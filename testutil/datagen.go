@@ -24,6 +24,7 @@ import (
 	"github.com/babylonchain/finality-provider/codec"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/service"
+	fpkeyring "github.com/babylonchain/finality-provider/keyring"
 	"github.com/babylonchain/finality-provider/types"
 )
 
@@ -72,13 +73,14 @@ func GenRandomFinalityProvider(r *rand.Rand, t *testing.T) *store.StoredFinality
 	require.NoError(t, err)
 
 	return &store.StoredFinalityProvider{
-		KeyName:       GenRandomHexStr(r, 4),
-		ChainID:       "chain-test",
-		ChainPk:       &secp256k1.PubKey{Key: chainPk.Bytes()},
-		BtcPk:         bip340PK.MustToBTCPK(),
-		Description:   RandomDescription(r),
-		Commission:    ZeroCommissionRate(),
-		MasterPubRand: mpr.MarshalBase58(),
+		KeyName:             GenRandomHexStr(r, 4),
+		ChainID:             "chain-test",
+		ChainPk:             &secp256k1.PubKey{Key: chainPk.Bytes()},
+		BtcPk:               bip340PK.MustToBTCPK(),
+		Description:         RandomDescription(r),
+		Commission:          ZeroCommissionRate(),
+		MasterPubRand:       mpr.MarshalBase58(),
+		MasterPubRandScheme: fpkeyring.RandSchemeUint32,
 		Pop: &proto.ProofOfPossession{
 			ChainSig: pop.BabylonSig,
 			BtcSig:   pop.BtcSig,
@@ -113,7 +115,7 @@ func GenStoredFinalityProvider(r *rand.Rand, t *testing.T, app *service.Finality
 	_, err := service.CreateChainKey(cfg.BabylonConfig.KeyDirectory, cfg.BabylonConfig.ChainID, keyName, keyring.BackendTest, passphrase, hdPath, "")
 	require.NoError(t, err)
 
-	res, err := app.CreateFinalityProvider(keyName, chainID, passphrase, hdPath, RandomDescription(r), ZeroCommissionRate())
+	res, err := app.CreateFinalityProvider(keyName, chainID, passphrase, hdPath, "", RandomDescription(r), ZeroCommissionRate())
 	require.NoError(t, err)
 
 	btcPk, err := bbn.NewBIP340PubKeyFromHex(res.FpInfo.BtcPkHex)
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	math "cosmossdk.io/math"
+	types0 "github.com/babylonchain/babylon/types"
 	types "github.com/babylonchain/finality-provider/types"
 	btcec "github.com/btcsuite/btcd/btcec/v2"
 	gomock "github.com/golang/mock/gomock"
@@ -170,6 +171,21 @@ func (mr *MockClientControllerMockRecorder) QueryLatestFinalizedBlocks(count int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryLatestFinalizedBlocks", reflect.TypeOf((*MockClientController)(nil).QueryLatestFinalizedBlocks), count)
 }
 
+// QueryVotesAtHeight mocks base method.
+func (m *MockClientController) QueryVotesAtHeight(height uint64) ([]types0.BIP340PubKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryVotesAtHeight", height)
+	ret0, _ := ret[0].([]types0.BIP340PubKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryVotesAtHeight indicates an expected call of QueryVotesAtHeight.
+func (mr *MockClientControllerMockRecorder) QueryVotesAtHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryVotesAtHeight", reflect.TypeOf((*MockClientController)(nil).QueryVotesAtHeight), height)
+}
+
 // RegisterFinalityProvider mocks base method.
 func (m *MockClientController) RegisterFinalityProvider(chainPk []byte, fpPk *btcec.PublicKey, pop []byte, commission *math.LegacyDec, description []byte, masterPubRand string) (*types.TxResponse, uint64, error) {
 	m.ctrl.T.Helper()